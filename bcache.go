@@ -0,0 +1,342 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+// BcacheCacheMode controls how a bcache device uses its cache.
+type BcacheCacheMode string
+
+const (
+	// BcacheWriteBack caches writes, and writes them to the backing
+	// device later.
+	BcacheWriteBack BcacheCacheMode = "writeback"
+
+	// BcacheWriteThrough writes to the cache and the backing device at the
+	// same time.
+	BcacheWriteThrough BcacheCacheMode = "writethrough"
+
+	// BcacheWriteAround only caches reads.
+	BcacheWriteAround BcacheCacheMode = "writearound"
+)
+
+type bcache struct {
+	controller *controller
+
+	resourceURI string
+
+	id        int
+	name      string
+	uuid      string
+	cacheMode string
+	size      uint64
+
+	cacheSet *bcacheCacheSet
+
+	// Only one of these is set.
+	backingBlockDevice *blockdevice
+	backingPartition   *partition
+
+	virtualDevice *blockdevice
+}
+
+func (b *bcache) updateFrom(other *bcache) {
+	b.resourceURI = other.resourceURI
+	b.id = other.id
+	b.name = other.name
+	b.uuid = other.uuid
+	b.cacheMode = other.cacheMode
+	b.size = other.size
+	b.cacheSet = other.cacheSet
+	b.backingBlockDevice = other.backingBlockDevice
+	b.backingPartition = other.backingPartition
+	b.virtualDevice = other.virtualDevice
+}
+
+// ID implements Bcache.
+func (b *bcache) ID() int {
+	return b.id
+}
+
+// Name implements Bcache.
+func (b *bcache) Name() string {
+	return b.name
+}
+
+// UUID implements Bcache.
+func (b *bcache) UUID() string {
+	return b.uuid
+}
+
+// CacheMode implements Bcache.
+func (b *bcache) CacheMode() string {
+	return b.cacheMode
+}
+
+// Size implements Bcache.
+func (b *bcache) Size() uint64 {
+	return b.size
+}
+
+// CacheSet implements Bcache.
+func (b *bcache) CacheSet() BcacheCacheSet {
+	if b.cacheSet == nil {
+		return nil
+	}
+	b.cacheSet.controller = b.controller
+	return b.cacheSet
+}
+
+// BackingDevice implements Bcache.
+func (b *bcache) BackingDevice() StorageDevice {
+	return storageDevice(b.controller, b.backingBlockDevice, b.backingPartition)
+}
+
+// VirtualDevice implements Bcache.
+func (b *bcache) VirtualDevice() BlockDevice {
+	if b.virtualDevice == nil {
+		return nil
+	}
+	b.virtualDevice.controller = b.controller
+	return b.virtualDevice
+}
+
+// CreateBcacheArgs is an argument struct for passing parameters to the
+// Machine.CreateBcache method.
+type CreateBcacheArgs struct {
+	// Name is optional, and MAAS generates one if it isn't specified.
+	Name string
+	// UUID is optional, and MAAS generates one if it isn't specified.
+	UUID string
+
+	// CacheSet is required.
+	CacheSet BcacheCacheSet
+	// Exactly one of BackingDevice or BackingPartition must be specified.
+	BackingDevice    BlockDevice
+	BackingPartition Partition
+	// CacheMode is required.
+	CacheMode BcacheCacheMode
+}
+
+// Validate ensures that the cache set, a single backing device and a known
+// cache mode are specified.
+func (a *CreateBcacheArgs) Validate() error {
+	if a.CacheSet == nil {
+		return errors.NotValidf("missing CacheSet")
+	}
+	if a.BackingDevice == nil && a.BackingPartition == nil {
+		return errors.NotValidf("missing BackingDevice or BackingPartition")
+	}
+	if a.BackingDevice != nil && a.BackingPartition != nil {
+		return errors.NotValidf("specifying both BackingDevice and BackingPartition")
+	}
+	switch a.CacheMode {
+	case BcacheWriteBack, BcacheWriteThrough, BcacheWriteAround:
+	case "":
+		return errors.NotValidf("missing CacheMode")
+	default:
+		return errors.NotValidf("unknown CacheMode value (%q)", a.CacheMode)
+	}
+	return nil
+}
+
+// UpdateBcacheArgs is an argument struct for passing parameters to the
+// Bcache.Update method. Only the values that are specified are changed.
+type UpdateBcacheArgs struct {
+	Name      string
+	UUID      string
+	CacheSet  BcacheCacheSet
+	CacheMode BcacheCacheMode
+
+	// At most one of BackingDevice or BackingPartition may be specified.
+	BackingDevice    BlockDevice
+	BackingPartition Partition
+}
+
+// Validate ensures that at most one backing device is specified and that
+// the cache mode is known.
+func (a *UpdateBcacheArgs) Validate() error {
+	if a.BackingDevice != nil && a.BackingPartition != nil {
+		return errors.NotValidf("specifying both BackingDevice and BackingPartition")
+	}
+	switch a.CacheMode {
+	case "", BcacheWriteBack, BcacheWriteThrough, BcacheWriteAround:
+	default:
+		return errors.NotValidf("unknown CacheMode value (%q)", a.CacheMode)
+	}
+	return nil
+}
+
+// Update implements Bcache.
+func (b *bcache) Update(args UpdateBcacheArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("uuid", args.UUID)
+	if args.CacheSet != nil {
+		params.Values.Add("cache_set", fmt.Sprint(args.CacheSet.ID()))
+	}
+	if args.BackingDevice != nil {
+		params.Values.Add("backing_device", fmt.Sprint(args.BackingDevice.ID()))
+	}
+	if args.BackingPartition != nil {
+		params.Values.Add("backing_partition", fmt.Sprint(args.BackingPartition.ID()))
+	}
+	params.MaybeAdd("cache_mode", string(args.CacheMode))
+	source, err := b.controller.put(b.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readBcache(b.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b.updateFrom(response)
+	return nil
+}
+
+// Delete implements Bcache.
+func (b *bcache) Delete() error {
+	err := b.controller.delete(b.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+func readBcache(controllerVersion version.Number, source interface{}) (*bcache, error) {
+	readFunc, err := getBcacheDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "bcache base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readBcaches(controllerVersion version.Number, source interface{}) ([]*bcache, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "bcache base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getBcacheDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readBcacheList(valid, readFunc)
+}
+
+func getBcacheDeserializationFunc(controllerVersion version.Number) (bcacheDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range bcacheDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no bcache read func for version %s", controllerVersion)
+	}
+	return bcacheDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readBcacheList expects the values of the sourceList to be string maps.
+func readBcacheList(sourceList []interface{}, readFunc bcacheDeserializationFunc) ([]*bcache, error) {
+	result := make([]*bcache, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for bcache %d, %T", i, value)
+		}
+		bcache, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "bcache %d", i)
+		}
+		result = append(result, bcache)
+	}
+	return result, nil
+}
+
+type bcacheDeserializationFunc func(map[string]interface{}) (*bcache, error)
+
+var bcacheDeserializationFuncs = map[version.Number]bcacheDeserializationFunc{
+	twoDotOh: bcache_2_0,
+}
+
+func bcache_2_0(source map[string]interface{}) (*bcache, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":         schema.ForceInt(),
+		"name":       schema.String(),
+		"uuid":       schema.OneOf(schema.Nil(""), schema.String()),
+		"cache_mode": schema.String(),
+		"size":       schema.ForceUint(),
+
+		"cache_set":      schema.StringMap(schema.Any()),
+		"backing_device": schema.StringMap(schema.Any()),
+		"virtual_device": schema.OneOf(schema.Nil(""), schema.StringMap(schema.Any())),
+	}
+	defaults := schema.Defaults{
+		"virtual_device": nil,
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "bcache 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	cacheSet, err := bcacheCacheSet_2_0(valid["cache_set"].(map[string]interface{}))
+	if err != nil {
+		return nil, errors.Annotate(err, "cache set")
+	}
+	backingBlockDevice, backingPartition, err := storageDevice_2_0(valid["backing_device"].(map[string]interface{}))
+	if err != nil {
+		return nil, errors.Annotate(err, "backing device")
+	}
+	var virtualDevice *blockdevice
+	if deviceSource, ok := valid["virtual_device"].(map[string]interface{}); ok {
+		if virtualDevice, err = blockdevice_2_0(deviceSource); err != nil {
+			return nil, errors.Annotate(err, "virtual device")
+		}
+	}
+
+	uuid, _ := valid["uuid"].(string)
+	result := &bcache{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:        valid["id"].(int),
+		name:      valid["name"].(string),
+		uuid:      uuid,
+		cacheMode: valid["cache_mode"].(string),
+		size:      valid["size"].(uint64),
+
+		cacheSet: cacheSet,
+
+		backingBlockDevice: backingBlockDevice,
+		backingPartition:   backingPartition,
+
+		virtualDevice: virtualDevice,
+	}
+	return result, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type bcacheSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&bcacheSuite{})
+
+func (*bcacheSuite) TestReadBcachesBadSchema(c *gc.C) {
+	_, err := readBcaches(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `bcache base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*bcacheSuite) TestReadBcaches(c *gc.C) {
+	bcaches, err := readBcaches(twoDotOh, parseJSON(c, bcachesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bcaches, gc.HasLen, 1)
+	bcache := bcaches[0]
+
+	c.Check(bcache.ID(), gc.Equals, 66)
+	c.Check(bcache.Name(), gc.Equals, "bcache0")
+	c.Check(bcache.UUID(), gc.Equals, "8d7c6b5a-4f3e-4d2c-b1a0-9f8e7d6c5b4a")
+	c.Check(bcache.CacheMode(), gc.Equals, "writeback")
+	c.Check(bcache.Size(), gc.Equals, uint64(8589934592))
+
+	cacheSet := bcache.CacheSet()
+	c.Assert(cacheSet, gc.NotNil)
+	c.Check(cacheSet.ID(), gc.Equals, 7)
+
+	backing := bcache.BackingDevice()
+	c.Assert(backing, gc.NotNil)
+	c.Check(backing.Type(), gc.Equals, "blockdevice")
+	c.Check(backing.ID(), gc.Equals, 34)
+
+	virtual := bcache.VirtualDevice()
+	c.Assert(virtual, gc.NotNil)
+	c.Check(virtual.ID(), gc.Equals, 67)
+	c.Check(virtual.Name(), gc.Equals, "bcache0")
+}
+
+func (*bcacheSuite) TestLowVersion(c *gc.C) {
+	_, err := readBcaches(version.MustParse("1.9.0"), parseJSON(c, bcachesResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*bcacheSuite) TestHighVersion(c *gc.C) {
+	bcaches, err := readBcaches(version.MustParse("2.1.9"), parseJSON(c, bcachesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bcaches, gc.HasLen, 1)
+}
+
+func (*bcacheSuite) TestCreateBcacheArgsValidate(c *gc.C) {
+	cacheSet := &bcacheCacheSet{}
+	for i, test := range []struct {
+		args    CreateBcacheArgs
+		errText string
+	}{{
+		errText: "missing CacheSet not valid",
+	}, {
+		args:    CreateBcacheArgs{CacheSet: cacheSet},
+		errText: "missing BackingDevice or BackingPartition not valid",
+	}, {
+		args: CreateBcacheArgs{
+			CacheSet:         cacheSet,
+			BackingDevice:    &blockdevice{},
+			BackingPartition: &partition{},
+		},
+		errText: "specifying both BackingDevice and BackingPartition not valid",
+	}, {
+		args:    CreateBcacheArgs{CacheSet: cacheSet, BackingDevice: &blockdevice{}},
+		errText: "missing CacheMode not valid",
+	}, {
+		args: CreateBcacheArgs{
+			CacheSet:      cacheSet,
+			BackingDevice: &blockdevice{},
+			CacheMode:     "writeoften",
+		},
+		errText: `unknown CacheMode value ("writeoften") not valid`,
+	}, {
+		args: CreateBcacheArgs{
+			CacheSet:         cacheSet,
+			BackingPartition: &partition{},
+			CacheMode:        BcacheWriteThrough,
+		},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *bcacheSuite) getServerAndBcache(c *gc.C) (*SimpleTestServer, *bcache) {
+	server, ctrl := createTestServerController(c, s)
+	bcache, err := readBcache(twoDotOh, parseJSON(c, bcacheResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	bcache.controller = ctrl.(*controller)
+	return server, bcache
+}
+
+func (s *bcacheSuite) TestUpdate(c *gc.C) {
+	server, bcache := s.getServerAndBcache(c)
+	response := updateJSONMap(c, bcacheResponse, map[string]interface{}{
+		"cache_mode": "writearound",
+	})
+	server.AddPutResponse(bcache.resourceURI, http.StatusOK, response)
+
+	err := bcache.Update(UpdateBcacheArgs{CacheMode: BcacheWriteAround})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bcache.CacheMode(), gc.Equals, "writearound")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 1)
+	c.Check(form.Get("cache_mode"), gc.Equals, "writearound")
+}
+
+func (s *bcacheSuite) TestUpdateValidates(c *gc.C) {
+	_, bcache := s.getServerAndBcache(c)
+	err := bcache.Update(UpdateBcacheArgs{CacheMode: "writeoften"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *bcacheSuite) TestDelete(c *gc.C) {
+	server, bcache := s.getServerAndBcache(c)
+	server.AddDeleteResponse(bcache.resourceURI, http.StatusNoContent, "")
+	err := bcache.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bcacheSuite) TestDeleteNotFound(c *gc.C) {
+	server, bcache := s.getServerAndBcache(c)
+	server.AddDeleteResponse(bcache.resourceURI, http.StatusNotFound, "")
+	err := bcache.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+var (
+	bcachesResponse = "[" + bcacheResponse + "]"
+	bcacheResponse  = `
+    {
+        "id": 66,
+        "uuid": "8d7c6b5a-4f3e-4d2c-b1a0-9f8e7d6c5b4a",
+        "name": "bcache0",
+        "cache_mode": "writeback",
+        "size": 8589934592,
+        "human_size": "8.6 GB",
+        "system_id": "4y3ha3",
+        "cache_set": ` + bcacheCacheSetResponse + `,
+        "backing_device": ` + blockdeviceResponse + `,
+        "virtual_device": {
+            "path": "/dev/disk/by-dname/bcache0",
+            "name": "bcache0",
+            "used_for": "Unused",
+            "partitions": [],
+            "filesystem": null,
+            "id_path": null,
+            "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/67/",
+            "id": 67,
+            "type": "virtual",
+            "block_size": 4096,
+            "used_size": 0,
+            "available_size": 8589934592,
+            "uuid": "8d7c6b5a-4f3e-4d2c-b1a0-9f8e7d6c5b4a",
+            "size": 8589934592,
+            "model": null,
+            "tags": []
+        },
+        "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/bcache/66/"
+    }
+`
+)
@@ -0,0 +1,205 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type bcacheCacheSet struct {
+	controller *controller
+
+	resourceURI string
+
+	id   int
+	name string
+
+	// Only one of these is set.
+	cacheBlockDevice *blockdevice
+	cachePartition   *partition
+}
+
+func (s *bcacheCacheSet) updateFrom(other *bcacheCacheSet) {
+	s.resourceURI = other.resourceURI
+	s.id = other.id
+	s.name = other.name
+	s.cacheBlockDevice = other.cacheBlockDevice
+	s.cachePartition = other.cachePartition
+}
+
+// ID implements BcacheCacheSet.
+func (s *bcacheCacheSet) ID() int {
+	return s.id
+}
+
+// Name implements BcacheCacheSet.
+func (s *bcacheCacheSet) Name() string {
+	return s.name
+}
+
+// CacheDevice implements BcacheCacheSet.
+func (s *bcacheCacheSet) CacheDevice() StorageDevice {
+	return storageDevice(s.controller, s.cacheBlockDevice, s.cachePartition)
+}
+
+// BcacheCacheSetArgs is an argument struct for passing parameters to the
+// Machine.CreateBcacheCacheSet and BcacheCacheSet.Update methods.
+type BcacheCacheSetArgs struct {
+	// Exactly one of CacheDevice or CachePartition must be specified.
+	CacheDevice    BlockDevice
+	CachePartition Partition
+}
+
+// Validate ensures that exactly one cache device is specified.
+func (a *BcacheCacheSetArgs) Validate() error {
+	if a.CacheDevice == nil && a.CachePartition == nil {
+		return errors.NotValidf("missing CacheDevice or CachePartition")
+	}
+	if a.CacheDevice != nil && a.CachePartition != nil {
+		return errors.NotValidf("specifying both CacheDevice and CachePartition")
+	}
+	return nil
+}
+
+func (a *BcacheCacheSetArgs) params() *URLParams {
+	params := NewURLParams()
+	if a.CacheDevice != nil {
+		params.Values.Add("cache_device", fmt.Sprint(a.CacheDevice.ID()))
+	}
+	if a.CachePartition != nil {
+		params.Values.Add("cache_partition", fmt.Sprint(a.CachePartition.ID()))
+	}
+	return params
+}
+
+// Update implements BcacheCacheSet.
+func (s *bcacheCacheSet) Update(args BcacheCacheSetArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	source, err := s.controller.put(s.resourceURI, args.params().Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readBcacheCacheSet(s.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.updateFrom(response)
+	return nil
+}
+
+// Delete implements BcacheCacheSet.
+func (s *bcacheCacheSet) Delete() error {
+	err := s.controller.delete(s.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+func readBcacheCacheSet(controllerVersion version.Number, source interface{}) (*bcacheCacheSet, error) {
+	readFunc, err := getBcacheCacheSetDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "bcache cache set base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readBcacheCacheSets(controllerVersion version.Number, source interface{}) ([]*bcacheCacheSet, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "bcache cache set base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getBcacheCacheSetDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readBcacheCacheSetList(valid, readFunc)
+}
+
+func getBcacheCacheSetDeserializationFunc(controllerVersion version.Number) (bcacheCacheSetDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range bcacheCacheSetDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no bcache cache set read func for version %s", controllerVersion)
+	}
+	return bcacheCacheSetDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readBcacheCacheSetList expects the values of the sourceList to be string maps.
+func readBcacheCacheSetList(sourceList []interface{}, readFunc bcacheCacheSetDeserializationFunc) ([]*bcacheCacheSet, error) {
+	result := make([]*bcacheCacheSet, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for bcache cache set %d, %T", i, value)
+		}
+		cacheSet, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "bcache cache set %d", i)
+		}
+		result = append(result, cacheSet)
+	}
+	return result, nil
+}
+
+type bcacheCacheSetDeserializationFunc func(map[string]interface{}) (*bcacheCacheSet, error)
+
+var bcacheCacheSetDeserializationFuncs = map[version.Number]bcacheCacheSetDeserializationFunc{
+	twoDotOh: bcacheCacheSet_2_0,
+}
+
+func bcacheCacheSet_2_0(source map[string]interface{}) (*bcacheCacheSet, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":           schema.ForceInt(),
+		"name":         schema.String(),
+		"cache_device": schema.StringMap(schema.Any()),
+	}
+	checker := schema.FieldMap(fields, nil) // no defaults
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "bcache cache set 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	blockDevice, partition, err := storageDevice_2_0(valid["cache_device"].(map[string]interface{}))
+	if err != nil {
+		return nil, errors.Annotate(err, "cache device")
+	}
+
+	result := &bcacheCacheSet{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:   valid["id"].(int),
+		name: valid["name"].(string),
+
+		cacheBlockDevice: blockDevice,
+		cachePartition:   partition,
+	}
+	return result, nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type bcacheCacheSetSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&bcacheCacheSetSuite{})
+
+func (*bcacheCacheSetSuite) TestReadBcacheCacheSetsBadSchema(c *gc.C) {
+	_, err := readBcacheCacheSets(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `bcache cache set base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*bcacheCacheSetSuite) TestReadBcacheCacheSets(c *gc.C) {
+	cacheSets, err := readBcacheCacheSets(twoDotOh, parseJSON(c, bcacheCacheSetsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cacheSets, gc.HasLen, 1)
+	cacheSet := cacheSets[0]
+
+	c.Check(cacheSet.ID(), gc.Equals, 7)
+	c.Check(cacheSet.Name(), gc.Equals, "cache0")
+	device := cacheSet.CacheDevice()
+	c.Assert(device, gc.NotNil)
+	c.Check(device.Type(), gc.Equals, "partition")
+	c.Check(device.ID(), gc.Equals, 104)
+}
+
+func (*bcacheCacheSetSuite) TestLowVersion(c *gc.C) {
+	_, err := readBcacheCacheSets(version.MustParse("1.9.0"), parseJSON(c, bcacheCacheSetsResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*bcacheCacheSetSuite) TestHighVersion(c *gc.C) {
+	cacheSets, err := readBcacheCacheSets(version.MustParse("2.1.9"), parseJSON(c, bcacheCacheSetsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cacheSets, gc.HasLen, 1)
+}
+
+func (*bcacheCacheSetSuite) TestBcacheCacheSetArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    BcacheCacheSetArgs
+		errText string
+	}{{
+		errText: "missing CacheDevice or CachePartition not valid",
+	}, {
+		args: BcacheCacheSetArgs{
+			CacheDevice:    &blockdevice{},
+			CachePartition: &partition{},
+		},
+		errText: "specifying both CacheDevice and CachePartition not valid",
+	}, {
+		args: BcacheCacheSetArgs{CacheDevice: &blockdevice{}},
+	}, {
+		args: BcacheCacheSetArgs{CachePartition: &partition{}},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *bcacheCacheSetSuite) getServerAndCacheSet(c *gc.C) (*SimpleTestServer, *bcacheCacheSet) {
+	server, ctrl := createTestServerController(c, s)
+	cacheSet, err := readBcacheCacheSet(twoDotOh, parseJSON(c, bcacheCacheSetResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	cacheSet.controller = ctrl.(*controller)
+	return server, cacheSet
+}
+
+func (s *bcacheCacheSetSuite) TestUpdate(c *gc.C) {
+	server, cacheSet := s.getServerAndCacheSet(c)
+	response := updateJSONMap(c, bcacheCacheSetResponse, map[string]interface{}{
+		"cache_device": parseJSON(c, blockdeviceResponse),
+	})
+	server.AddPutResponse(cacheSet.resourceURI, http.StatusOK, response)
+
+	err := cacheSet.Update(BcacheCacheSetArgs{CacheDevice: &blockdevice{id: 34}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cacheSet.CacheDevice().Type(), gc.Equals, "blockdevice")
+	c.Check(cacheSet.CacheDevice().ID(), gc.Equals, 34)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 1)
+	c.Check(form.Get("cache_device"), gc.Equals, "34")
+}
+
+func (s *bcacheCacheSetSuite) TestUpdateValidates(c *gc.C) {
+	_, cacheSet := s.getServerAndCacheSet(c)
+	err := cacheSet.Update(BcacheCacheSetArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *bcacheCacheSetSuite) TestDelete(c *gc.C) {
+	server, cacheSet := s.getServerAndCacheSet(c)
+	server.AddDeleteResponse(cacheSet.resourceURI, http.StatusNoContent, "")
+	err := cacheSet.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bcacheCacheSetSuite) TestDeleteInUse(c *gc.C) {
+	server, cacheSet := s.getServerAndCacheSet(c)
+	server.AddDeleteResponse(cacheSet.resourceURI, http.StatusBadRequest, "cache set is in use")
+	err := cacheSet.Delete()
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "cache set is in use")
+}
+
+var (
+	bcacheCacheSetsResponse = "[" + bcacheCacheSetResponse + "]"
+	bcacheCacheSetResponse  = `
+    {
+        "id": 7,
+        "name": "cache0",
+        "system_id": "4y3ha3",
+        "cache_device": {
+            "bootable": false,
+            "id": 104,
+            "path": "/dev/disk/by-dname/sde-part1",
+            "filesystem": {
+                "fstype": "bcache-cache",
+                "mount_point": null,
+                "label": null,
+                "mount_options": null,
+                "uuid": "4e5d6c7b-8a9f-4e0d-b1c2-a3b4c5d6e7f8"
+            },
+            "type": "partition",
+            "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/101/partition/104",
+            "uuid": "2c3d4e5f-6a7b-4c8d-9e0f-1a2b3c4d5e6f",
+            "used_for": "bcache cache",
+            "size": 4294967296,
+            "tags": []
+        },
+        "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/bcache-cache-set/7/"
+    }
+`
+)
@@ -10,6 +10,8 @@ import (
 )
 
 type blockdevice struct {
+	controller *controller
+
 	resourceURI string
 
 	id      int
@@ -29,6 +31,23 @@ type blockdevice struct {
 	partitions []*partition
 }
 
+func (b *blockdevice) updateFrom(other *blockdevice) {
+	b.resourceURI = other.resourceURI
+	b.id = other.id
+	b.uuid = other.uuid
+	b.name = other.name
+	b.model = other.model
+	b.idPath = other.idPath
+	b.path = other.path
+	b.usedFor = other.usedFor
+	b.tags = other.tags
+	b.blockSize = other.blockSize
+	b.usedSize = other.usedSize
+	b.size = other.size
+	b.filesystem = other.filesystem
+	b.partitions = other.partitions
+}
+
 // Type implements BlockDevice
 func (b *blockdevice) Type() string {
 	return "blockdevice"
@@ -98,11 +117,164 @@ func (b *blockdevice) FileSystem() FileSystem {
 func (b *blockdevice) Partitions() []Partition {
 	result := make([]Partition, len(b.partitions))
 	for i, v := range b.partitions {
+		v.controller = b.controller
 		result[i] = v
 	}
 	return result
 }
 
+// FormatStorageDeviceArgs is an argument struct for passing parameters to
+// the Format method of block devices and partitions.
+type FormatStorageDeviceArgs struct {
+	// FSType is the filesystem type, such as "ext4" (required).
+	FSType string
+	// UUID is optional, and MAAS generates one if it isn't specified.
+	UUID string
+	// Label is optional, and is only used for partitions.
+	Label string
+}
+
+// Validate ensures that the FSType is specified.
+func (a *FormatStorageDeviceArgs) Validate() error {
+	if a.FSType == "" {
+		return errors.NotValidf("missing FSType")
+	}
+	return nil
+}
+
+// MountStorageDeviceArgs is an argument struct for passing parameters to
+// the Mount method of block devices and partitions.
+type MountStorageDeviceArgs struct {
+	// MountPoint is the absolute path to mount the filesystem on (required).
+	MountPoint string
+	// MountOptions is the comma separated list of options passed to mount.
+	MountOptions string
+}
+
+// Validate ensures that the MountPoint is specified.
+func (a *MountStorageDeviceArgs) Validate() error {
+	if a.MountPoint == "" {
+		return errors.NotValidf("missing MountPoint")
+	}
+	return nil
+}
+
+// Format implements BlockDevice.
+func (b *blockdevice) Format(args FormatStorageDeviceArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("fstype", args.FSType)
+	params.MaybeAdd("uuid", args.UUID)
+	return errors.Trace(b.postOp("format", params))
+}
+
+// Unformat implements BlockDevice.
+func (b *blockdevice) Unformat() error {
+	return errors.Trace(b.postOp("unformat", NewURLParams()))
+}
+
+// Mount implements BlockDevice.
+func (b *blockdevice) Mount(args MountStorageDeviceArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("mount_point", args.MountPoint)
+	params.MaybeAdd("mount_options", args.MountOptions)
+	return errors.Trace(b.postOp("mount", params))
+}
+
+// Unmount implements BlockDevice.
+func (b *blockdevice) Unmount() error {
+	return errors.Trace(b.postOp("unmount", NewURLParams()))
+}
+
+// SetBootDisk implements BlockDevice.
+func (b *blockdevice) SetBootDisk() error {
+	// MAAS just responds with "OK" rather than the block device, so
+	// there is nothing to refresh.
+	_, err := b.controller._postRaw(b.resourceURI, "set_boot_disk", nil, nil)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// Delete implements BlockDevice.
+func (b *blockdevice) Delete() error {
+	err := b.controller.delete(b.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// CreatePartitionArgs is an argument struct for passing parameters to the
+// BlockDevice.CreatePartition method.
+type CreatePartitionArgs struct {
+	// Size of the partition in bytes. If not specified, the partition uses
+	// all the remaining space on the block device.
+	Size uint64
+	// Bootable marks the partition as bootable.
+	Bootable bool
+}
+
+// partitionsURI is where partitions are created for the block device.
+func (b *blockdevice) partitionsURI() string {
+	return EnsureTrailingSlash(b.resourceURI) + "partitions/"
+}
+
+// CreatePartition implements BlockDevice.
+func (b *blockdevice) CreatePartition(args CreatePartitionArgs) (Partition, error) {
+	params := NewURLParams()
+	maybeAddSize(params, "size", args.Size)
+	params.MaybeAddBool("bootable", args.Bootable)
+	source, err := b.controller.post(b.partitionsURI(), "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	partition, err := readPartition(b.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	partition.controller = b.controller
+	return partition, nil
+}
+
+// postOp calls the specified operation on the block device and refreshes
+// the block device from the response.
+func (b *blockdevice) postOp(op string, params *URLParams) error {
+	source, err := b.controller.post(b.resourceURI, op, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readBlockDevice(b.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b.updateFrom(response)
+	return nil
+}
+
+func readBlockDevice(controllerVersion version.Number, source interface{}) (*blockdevice, error) {
+	readFunc, err := getBlockDeviceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "blockdevice base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readBlockDevices(controllerVersion version.Number, source interface{}) ([]*blockdevice, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -111,6 +283,14 @@ func readBlockDevices(controllerVersion version.Number, source interface{}) ([]*
 	}
 	valid := coerced.([]interface{})
 
+	readFunc, err := getBlockDeviceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readBlockDeviceList(valid, readFunc)
+}
+
+func getBlockDeviceDeserializationFunc(controllerVersion version.Number) (blockdeviceDeserializationFunc, error) {
 	var deserialisationVersion version.Number
 	for v := range blockdeviceDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
@@ -120,8 +300,7 @@ func readBlockDevices(controllerVersion version.Number, source interface{}) ([]*
 	if deserialisationVersion == version.Zero {
 		return nil, NewUnsupportedVersionError("no blockdevice read func for version %s", controllerVersion)
 	}
-	readFunc := blockdeviceDeserializationFuncs[deserialisationVersion]
-	return readBlockDeviceList(valid, readFunc)
+	return blockdeviceDeserializationFuncs[deserialisationVersion], nil
 }
 
 // readBlockDeviceList expects the values of the sourceList to be string maps.
@@ -211,3 +390,73 @@ func blockdevice_2_0(source map[string]interface{}) (*blockdevice, error) {
 	}
 	return result, nil
 }
+
+// storageDevice_2_0 reads a value that is either a block device or a
+// partition, which are told apart by their type. Exactly one of the
+// results is non-nil on success.
+func storageDevice_2_0(source map[string]interface{}) (*blockdevice, *partition, error) {
+	if source["type"] == "partition" {
+		partition, err := partition_2_0(source)
+		if err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		return nil, partition, nil
+	}
+	blockDevice, err := blockdevice_2_0(source)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return blockDevice, nil, nil
+}
+
+// storageDeviceList_2_0 reads a list containing a mix of block devices and
+// partitions. The values of the sourceList are expected to be string maps.
+func storageDeviceList_2_0(sourceList []interface{}) ([]*blockdevice, []*partition, error) {
+	var blockDevices []*blockdevice
+	var partitions []*partition
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, nil, NewDeserializationError("unexpected value for device %d, %T", i, value)
+		}
+		blockDevice, partition, err := storageDevice_2_0(source)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "device %d", i)
+		}
+		if partition != nil {
+			partitions = append(partitions, partition)
+		} else {
+			blockDevices = append(blockDevices, blockDevice)
+		}
+	}
+	return blockDevices, partitions, nil
+}
+
+// storageDevices returns the block devices followed by the partitions as
+// StorageDevices that can be operated on through the controller.
+func storageDevices(controller *controller, blockDevices []*blockdevice, partitions []*partition) []StorageDevice {
+	result := make([]StorageDevice, 0, len(blockDevices)+len(partitions))
+	for _, b := range blockDevices {
+		b.controller = controller
+		result = append(result, b)
+	}
+	for _, p := range partitions {
+		p.controller = controller
+		result = append(result, p)
+	}
+	return result
+}
+
+// storageDevice returns whichever of the block device or partition is set
+// as a StorageDevice that can be operated on through the controller.
+func storageDevice(controller *controller, blockDevice *blockdevice, partition *partition) StorageDevice {
+	if partition != nil {
+		partition.controller = controller
+		return partition
+	}
+	if blockDevice != nil {
+		blockDevice.controller = controller
+		return blockDevice
+	}
+	return nil
+}
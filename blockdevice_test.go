@@ -4,12 +4,18 @@
 package gomaasapi
 
 import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 )
 
-type blockdeviceSuite struct{}
+type blockdeviceSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&blockdeviceSuite{})
 
@@ -71,8 +77,182 @@ func (*blockdeviceSuite) TestHighVersion(c *gc.C) {
 	c.Assert(blockdevices, gc.HasLen, 1)
 }
 
-var blockdevicesResponse = `
-[
+func (s *blockdeviceSuite) getServerAndBlockDevice(c *gc.C) (*SimpleTestServer, *blockdevice) {
+	server, controller := createTestServerController(c, s)
+	server.AddGetResponse("/api/2.0/machines/", http.StatusOK, "["+machineResponse+"]")
+	machines, err := controller.Machines(MachinesArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+	blockDevice := machines[0].BlockDevice(34).(*blockdevice)
+	server.ResetRequests()
+	return server, blockDevice
+}
+
+func (s *blockdeviceSuite) TestFormat(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	response := updateJSONMap(c, blockdeviceResponse, map[string]interface{}{
+		"filesystem": map[string]interface{}{
+			"fstype": "xfs",
+			"uuid":   "d2c0b4a5-0e9e-4c10-9c5b-77e1e6c08f6b",
+		},
+	})
+	server.AddPostResponse(blockDevice.resourceURI+"?op=format", http.StatusOK, response)
+
+	err := blockDevice.Format(FormatStorageDeviceArgs{FSType: "xfs", UUID: "d2c0b4a5-0e9e-4c10-9c5b-77e1e6c08f6b"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockDevice.FileSystem().Type(), gc.Equals, "xfs")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 2)
+	c.Check(form.Get("fstype"), gc.Equals, "xfs")
+	c.Check(form.Get("uuid"), gc.Equals, "d2c0b4a5-0e9e-4c10-9c5b-77e1e6c08f6b")
+}
+
+func (s *blockdeviceSuite) TestFormatValidates(c *gc.C) {
+	_, blockDevice := s.getServerAndBlockDevice(c)
+	err := blockDevice.Format(FormatStorageDeviceArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing FSType not valid")
+}
+
+func (s *blockdeviceSuite) TestFormatConflict(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	server.AddPostResponse(blockDevice.resourceURI+"?op=format", http.StatusConflict, "machine not ready")
+	err := blockDevice.Format(FormatStorageDeviceArgs{FSType: "ext4"})
+	c.Assert(err, jc.Satisfies, IsCannotCompleteError)
+	c.Assert(err.Error(), gc.Equals, "machine not ready")
+}
+
+func (s *blockdeviceSuite) TestUnformat(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	response := updateJSONMap(c, blockdeviceResponse, map[string]interface{}{
+		"filesystem": nil,
+	})
+	server.AddPostResponse(blockDevice.resourceURI+"?op=unformat", http.StatusOK, response)
+
+	err := blockDevice.Unformat()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockDevice.filesystem, gc.IsNil)
+}
+
+func (s *blockdeviceSuite) TestMount(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	response := updateJSONMap(c, blockdeviceResponse, map[string]interface{}{
+		"filesystem": map[string]interface{}{
+			"fstype":      "ext4",
+			"mount_point": "/var/lib/data",
+			"uuid":        "fcd7745e-f1b5-4f5d-9575-9b0bb796b752",
+		},
+	})
+	server.AddPostResponse(blockDevice.resourceURI+"?op=mount", http.StatusOK, response)
+
+	err := blockDevice.Mount(MountStorageDeviceArgs{MountPoint: "/var/lib/data", MountOptions: "noatime"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockDevice.FileSystem().MountPoint(), gc.Equals, "/var/lib/data")
+
+	form := server.LastRequest().PostForm
+	c.Check(form.Get("mount_point"), gc.Equals, "/var/lib/data")
+	c.Check(form.Get("mount_options"), gc.Equals, "noatime")
+}
+
+func (s *blockdeviceSuite) TestMountValidates(c *gc.C) {
+	_, blockDevice := s.getServerAndBlockDevice(c)
+	err := blockDevice.Mount(MountStorageDeviceArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing MountPoint not valid")
+}
+
+func (s *blockdeviceSuite) TestUnmount(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	response := updateJSONMap(c, blockdeviceResponse, map[string]interface{}{
+		"filesystem": map[string]interface{}{
+			"fstype": "ext4",
+			"uuid":   "fcd7745e-f1b5-4f5d-9575-9b0bb796b752",
+		},
+	})
+	server.AddPostResponse(blockDevice.resourceURI+"?op=unmount", http.StatusOK, response)
+
+	err := blockDevice.Unmount()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(blockDevice.FileSystem().MountPoint(), gc.Equals, "")
+}
+
+func (s *blockdeviceSuite) TestSetBootDisk(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	server.AddPostResponse(blockDevice.resourceURI+"?op=set_boot_disk", http.StatusOK, "OK")
+	err := blockDevice.SetBootDisk()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *blockdeviceSuite) TestSetBootDiskBadRequest(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	server.AddPostResponse(blockDevice.resourceURI+"?op=set_boot_disk", http.StatusBadRequest, "virtual disk")
+	err := blockDevice.SetBootDisk()
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "virtual disk")
+}
+
+func (s *blockdeviceSuite) TestDelete(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	server.AddDeleteResponse(blockDevice.resourceURI, http.StatusNoContent, "")
+	err := blockDevice.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *blockdeviceSuite) TestDelete404(c *gc.C) {
+	_, blockDevice := s.getServerAndBlockDevice(c)
+	err := blockDevice.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *blockdeviceSuite) TestDeleteForbidden(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	server.AddDeleteResponse(blockDevice.resourceURI, http.StatusForbidden, "")
+	err := blockDevice.Delete()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+func (s *blockdeviceSuite) TestCreatePartition(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	response := updateJSONMap(c, partitionResponse, map[string]interface{}{
+		"id":         2,
+		"size":       1073741824,
+		"filesystem": nil,
+	})
+	server.AddPostResponse(blockDevice.resourceURI+"partitions/?op=", http.StatusOK, response)
+
+	partition, err := blockDevice.CreatePartition(CreatePartitionArgs{Size: 1073741824, Bootable: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(partition.ID(), gc.Equals, 2)
+	c.Assert(partition.Size(), gc.Equals, uint64(1073741824))
+	c.Assert(blockDevice.Partitions(), gc.HasLen, 1)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 2)
+	c.Check(form.Get("size"), gc.Equals, "1073741824")
+	c.Check(form.Get("bootable"), gc.Equals, "true")
+}
+
+func (s *blockdeviceSuite) TestCreatePartitionRemainingSpace(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	server.AddPostResponse(blockDevice.resourceURI+"partitions/?op=", http.StatusOK, partitionResponse)
+
+	_, err := blockDevice.CreatePartition(CreatePartitionArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.LastRequest().PostForm, gc.HasLen, 0)
+}
+
+func (s *blockdeviceSuite) TestCreatePartitionNoSpace(c *gc.C) {
+	server, blockDevice := s.getServerAndBlockDevice(c)
+	server.AddPostResponse(blockDevice.resourceURI+"partitions/?op=", http.StatusBadRequest, "not enough space")
+	_, err := blockDevice.CreatePartition(CreatePartitionArgs{Size: 1 << 40})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "not enough space")
+}
+
+var (
+	blockdevicesResponse = "[" + blockdeviceResponse + "]"
+	blockdeviceResponse  = `
     {
         "path": "/dev/disk/by-dname/sda",
         "name": "sda",
@@ -119,8 +299,8 @@ var blockdevicesResponse = `
             "rotary"
         ]
     }
-]
 `
+)
 
 var blockdevicesWithNullsResponse = `
 [
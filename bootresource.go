@@ -4,6 +4,7 @@
 package gomaasapi
 
 import (
+	"sort"
 	"strings"
 
 	"github.com/juju/collections/set"
@@ -24,6 +25,22 @@ type bootResource struct {
 	architecture string
 	subArches    string
 	kernelFlavor string
+	lastDeployed string
+
+	sets []BootResourceSet
+}
+
+// BootResourceSet is a version of the files of a boot resource, along with
+// how much of it the region controller has synced.
+type BootResourceSet struct {
+	Version string
+	Label   string
+	// Size is the total size of the files in the set, in bytes.
+	Size int
+	// Complete is true when all the files of the set have been synced.
+	Complete bool
+	// Progress is the percentage of the set that has been synced.
+	Progress float64
 }
 
 // ID implements BootResource.
@@ -36,12 +53,12 @@ func (b *bootResource) Name() string {
 	return b.name
 }
 
-// Name implements BootResource.
+// Type implements BootResource.
 func (b *bootResource) Type() string {
 	return b.type_
 }
 
-// Name implements BootResource.
+// Architecture implements BootResource.
 func (b *bootResource) Architecture() string {
 	return b.architecture
 }
@@ -56,6 +73,39 @@ func (b *bootResource) KernelFlavor() string {
 	return b.kernelFlavor
 }
 
+// LastDeployed implements BootResource.
+func (b *bootResource) LastDeployed() string {
+	return b.lastDeployed
+}
+
+// Sets implements BootResource.
+func (b *bootResource) Sets() []BootResourceSet {
+	return b.sets
+}
+
+// Size implements BootResource.
+func (b *bootResource) Size() int {
+	if len(b.sets) == 0 {
+		return 0
+	}
+	return b.sets[len(b.sets)-1].Size
+}
+
+func readBootResource(controllerVersion version.Number, source interface{}) (*bootResource, error) {
+	readFunc, err := getBootResourceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "boot resource base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readBootResources(controllerVersion version.Number, source interface{}) ([]*bootResource, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -64,6 +114,14 @@ func readBootResources(controllerVersion version.Number, source interface{}) ([]
 	}
 	valid := coerced.([]interface{})
 
+	readFunc, err := getBootResourceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readBootResourceList(valid, readFunc)
+}
+
+func getBootResourceDeserializationFunc(controllerVersion version.Number) (bootResourceDeserializationFunc, error) {
 	var deserialisationVersion version.Number
 	for v := range bootResourceDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
@@ -73,8 +131,7 @@ func readBootResources(controllerVersion version.Number, source interface{}) ([]
 	if deserialisationVersion == version.Zero {
 		return nil, NewUnsupportedVersionError("no boot resource read func for version %s", controllerVersion)
 	}
-	readFunc := bootResourceDeserializationFuncs[deserialisationVersion]
-	return readBootResourceList(valid, readFunc)
+	return bootResourceDeserializationFuncs[deserialisationVersion], nil
 }
 
 // readBootResourceList expects the values of the sourceList to be string maps.
@@ -109,10 +166,16 @@ func bootResource_2_0(source map[string]interface{}) (*bootResource, error) {
 		"architecture": schema.String(),
 		"subarches":    schema.String(),
 		"kflavor":      schema.String(),
+		// The last deployed date and the sets are only returned when
+		// reading a single boot resource.
+		"last_deployed": schema.OneOf(schema.Nil(""), schema.String()),
+		"sets":          schema.StringMap(schema.StringMap(schema.Any())),
 	}
 	defaults := schema.Defaults{
-		"subarches": "",
-		"kflavor":   "",
+		"subarches":     "",
+		"kflavor":       "",
+		"last_deployed": "",
+		"sets":          schema.Omit,
 	}
 	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(source, nil)
@@ -123,6 +186,14 @@ func bootResource_2_0(source map[string]interface{}) (*bootResource, error) {
 	// From here we know that the map returned from the schema coercion
 	// contains fields of the right type.
 
+	var sets []BootResourceSet
+	if setsMap, ok := valid["sets"].(map[string]interface{}); ok {
+		if sets, err = readBootResourceSets(setsMap); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	lastDeployed, _ := valid["last_deployed"].(string)
 	result := &bootResource{
 		resourceURI:  valid["resource_uri"].(string),
 		id:           valid["id"].(int),
@@ -131,6 +202,52 @@ func bootResource_2_0(source map[string]interface{}) (*bootResource, error) {
 		architecture: valid["architecture"].(string),
 		subArches:    valid["subarches"].(string),
 		kernelFlavor: valid["kflavor"].(string),
+		lastDeployed: lastDeployed,
+
+		sets: sets,
+	}
+	return result, nil
+}
+
+// readBootResourceSets reads the sets of a boot resource, which are keyed
+// by version, and returns them ordered from oldest to newest.
+func readBootResourceSets(source map[string]interface{}) ([]BootResourceSet, error) {
+	fields := schema.Fields{
+		"version":  schema.String(),
+		"label":    schema.String(),
+		"size":     schema.ForceInt(),
+		"complete": schema.Bool(),
+		"progress": schema.Float(),
+	}
+	defaults := schema.Defaults{
+		"version":  "",
+		"label":    "",
+		"size":     0,
+		"complete": false,
+		"progress": float64(0),
+	}
+	checker := schema.FieldMap(fields, defaults)
+	result := make([]BootResourceSet, 0, len(source))
+	for key, value := range source {
+		coerced, err := checker.Coerce(value, nil)
+		if err != nil {
+			return nil, WrapWithDeserializationError(err, "boot resource set %s schema check failed", key)
+		}
+		valid := coerced.(map[string]interface{})
+		setVersion := valid["version"].(string)
+		if setVersion == "" {
+			setVersion = key
+		}
+		result = append(result, BootResourceSet{
+			Version:  setVersion,
+			Label:    valid["label"].(string),
+			Size:     valid["size"].(int),
+			Complete: valid["complete"].(bool),
+			Progress: valid["progress"].(float64),
+		})
 	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
 	return result, nil
 }
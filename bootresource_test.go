@@ -33,6 +33,47 @@ func (*bootResourceSuite) TestReadBootResources(c *gc.C) {
 	c.Assert(trusty.Architecture(), gc.Equals, "amd64/hwe-t")
 	c.Assert(trusty.SubArchitectures(), jc.DeepEquals, subarches)
 	c.Assert(trusty.KernelFlavor(), gc.Equals, "generic")
+	c.Assert(trusty.LastDeployed(), gc.Equals, "")
+	c.Assert(trusty.Sets(), gc.HasLen, 0)
+	c.Assert(trusty.Size(), gc.Equals, 0)
+}
+
+func (*bootResourceSuite) TestReadBootResourceBadSchema(c *gc.C) {
+	_, err := readBootResource(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `boot resource base schema check failed: expected map, got string("wat?")`)
+}
+
+func (*bootResourceSuite) TestReadBootResourceWithSets(c *gc.C) {
+	resource, err := readBootResource(twoDotOh, parseJSON(c, bootResourceResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resource.ID(), gc.Equals, 7)
+	c.Check(resource.Type(), gc.Equals, "Synced")
+	c.Check(resource.LastDeployed(), gc.Equals, "Mon, 03 Oct. 2022 10:12:05")
+	c.Check(resource.Sets(), jc.DeepEquals, []BootResourceSet{{
+		Version:  "20220901",
+		Label:    "stable",
+		Size:     1024,
+		Complete: true,
+		Progress: 100,
+	}, {
+		Version:  "20221001",
+		Label:    "stable",
+		Size:     2048,
+		Complete: false,
+		Progress: 42.5,
+	}})
+	c.Check(resource.Size(), gc.Equals, 2048)
+}
+
+func (*bootResourceSuite) TestReadBootResourceSetBadSchema(c *gc.C) {
+	source := parseJSON(c, bootResourceResponse)
+	source.(map[string]interface{})["sets"] = map[string]interface{}{
+		"20221001": map[string]interface{}{"size": "huge"},
+	}
+	_, err := readBootResource(twoDotOh, source)
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err, gc.ErrorMatches, `boot resource set 20221001 schema check failed: size: .*`)
 }
 
 func (*bootResourceSuite) TestLowVersion(c *gc.C) {
@@ -46,6 +87,36 @@ func (*bootResourceSuite) TestHighVersion(c *gc.C) {
 	c.Assert(bootResources, gc.HasLen, 5)
 }
 
+var bootResourceResponse = `
+{
+    "architecture": "amd64/ga-22.04",
+    "type": "Synced",
+    "subarches": "generic,hwe-p,hwe-q",
+    "kflavor": "generic",
+    "name": "ubuntu/jammy",
+    "id": 7,
+    "last_deployed": "Mon, 03 Oct. 2022 10:12:05",
+    "sets": {
+        "20221001": {
+            "version": "20221001",
+            "label": "stable",
+            "size": 2048,
+            "complete": false,
+            "progress": 42.5,
+            "files": {}
+        },
+        "20220901": {
+            "label": "stable",
+            "size": 1024,
+            "complete": true,
+            "progress": 100,
+            "files": {}
+        }
+    },
+    "resource_uri": "/MAAS/api/2.0/boot-resources/7/"
+}
+`
+
 var bootResourcesResponse = `
 [
     {
@@ -0,0 +1,185 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+)
+
+// bootResourceUploadChunkSize is the default size of the chunks that boot
+// resource content is uploaded in, which is the size the MAAS CLI uses.
+const bootResourceUploadChunkSize = 4 * 1024 * 1024
+
+// bootResourceUploadRetries is the number of times in a row that uploading
+// a chunk may fail before the upload is abandoned.
+const bootResourceUploadRetries = 3
+
+// uploadContent is boot resource content that can be read from any offset,
+// along with its size and SHA256.
+type uploadContent struct {
+	io.ReadSeeker
+	start  int64
+	size   int64
+	sha256 string
+	// cleanup removes the spooled copy of the content, if there is one.
+	cleanup func()
+}
+
+// seek moves to the offset from the start of the content.
+func (u *uploadContent) seek(offset int64) error {
+	_, err := u.Seek(u.start+offset, io.SeekStart)
+	return errors.Trace(err)
+}
+
+// prepareUploadContent computes the SHA256 of the reader while streaming
+// it. Readers that can't seek are spooled to a temporary file so that their
+// content can be read again, in chunks, to upload it.
+func prepareUploadContent(reader io.Reader) (*uploadContent, error) {
+	hash := sha256.New()
+	if seeker, ok := reader.(io.ReadSeeker); ok {
+		start, err := seeker.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		size, err := io.Copy(hash, seeker)
+		if err != nil {
+			return nil, errors.Annotate(err, "reading content")
+		}
+		content := &uploadContent{
+			ReadSeeker: seeker,
+			start:      start,
+			size:       size,
+			sha256:     hex.EncodeToString(hash.Sum(nil)),
+			cleanup:    func() {},
+		}
+		return content, errors.Trace(content.seek(0))
+	}
+
+	file, err := os.CreateTemp("", "gomaasapi-boot-resource-")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cleanup := func() {
+		file.Close()
+		os.Remove(file.Name())
+	}
+	size, err := io.Copy(io.MultiWriter(file, hash), reader)
+	if err != nil {
+		cleanup()
+		return nil, errors.Annotate(err, "reading content")
+	}
+	content := &uploadContent{
+		ReadSeeker: file,
+		size:       size,
+		sha256:     hex.EncodeToString(hash.Sum(nil)),
+		cleanup:    cleanup,
+	}
+	if err := content.seek(0); err != nil {
+		cleanup()
+		return nil, errors.Trace(err)
+	}
+	return content, nil
+}
+
+// bootResourceUpload is the state of the upload of the content of a boot
+// resource, as reported by MAAS.
+type bootResourceUpload struct {
+	id        int
+	uploadURI string
+	size      int64
+	complete  bool
+	// uploaded is only meaningful when uploadedKnown is true, as MAAS
+	// doesn't report the number of bytes it has.
+	uploaded      int64
+	uploadedKnown bool
+}
+
+// readBootResourceUpload finds the file of the given type in the newest set
+// of the boot resource. Incomplete files have an upload URI, and a progress
+// that is the fraction of the file that MAAS has, as the number of bytes it
+// has divided by the size. This isn't the percentage that sets report.
+func readBootResourceUpload(source interface{}, filetype string) (*bootResourceUpload, error) {
+	resourceChecker := schema.FieldMap(schema.Fields{
+		"id":   schema.ForceInt(),
+		"sets": schema.StringMap(schema.StringMap(schema.Any())),
+	}, nil)
+	coerced, err := resourceChecker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "boot resource upload schema check failed")
+	}
+	resource := coerced.(map[string]interface{})
+	sets := resource["sets"].(map[string]interface{})
+	if len(sets) == 0 {
+		return nil, NewDeserializationError("boot resource has no sets")
+	}
+	var versions []string
+	for setVersion := range sets {
+		versions = append(versions, setVersion)
+	}
+	sort.Strings(versions)
+	newest := sets[versions[len(versions)-1]].(map[string]interface{})
+
+	filesChecker := schema.StringMap(schema.FieldMap(schema.Fields{
+		"filetype":   schema.String(),
+		"size":       schema.ForceInt(),
+		"complete":   schema.Bool(),
+		"progress":   schema.Float(),
+		"upload_uri": schema.String(),
+	}, schema.Defaults{
+		"filetype":   "",
+		"complete":   false,
+		"progress":   float64(0),
+		"upload_uri": "",
+	}))
+	coerced, err = filesChecker.Coerce(newest["files"], nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "boot resource files schema check failed")
+	}
+	files := coerced.(map[string]interface{})
+	for _, value := range files {
+		file := value.(map[string]interface{})
+		if file["filetype"] != filetype && len(files) > 1 {
+			continue
+		}
+		size := int64(file["size"].(int))
+		upload := &bootResourceUpload{
+			id:        resource["id"].(int),
+			uploadURI: file["upload_uri"].(string),
+			size:      size,
+			complete:  file["complete"].(bool),
+		}
+		upload.uploaded, upload.uploadedKnown = uploadedBytes(file["progress"].(float64), size)
+		if upload.complete {
+			upload.uploaded, upload.uploadedKnown = size, true
+		} else if upload.uploadURI == "" {
+			return nil, NewDeserializationError("boot resource file has no upload uri")
+		}
+		return upload, nil
+	}
+	return nil, NewDeserializationError("boot resource has no %s file", filetype)
+}
+
+// uploadedBytes returns the number of bytes that the progress of a file is
+// the fraction of. It returns false unless that number divided by the size
+// is exactly the progress, as anything else means that the progress isn't
+// the fraction that MAAS computes and resuming from it would corrupt the
+// content.
+func uploadedBytes(progress float64, size int64) (int64, bool) {
+	if size <= 0 {
+		return 0, progress == 0
+	}
+	uploaded := int64(math.Round(progress * float64(size)))
+	if uploaded < 0 || uploaded > size || float64(uploaded)/float64(size) != progress {
+		return 0, false
+	}
+	return uploaded, true
+}
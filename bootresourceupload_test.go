@@ -0,0 +1,168 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type bootResourceUploadSuite struct{}
+
+var _ = gc.Suite(&bootResourceUploadSuite{})
+
+func sha256String(content string) string {
+	hash := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+func (*bootResourceUploadSuite) TestPrepareUploadContentSeeker(c *gc.C) {
+	reader := strings.NewReader("skip0123456789")
+	_, err := reader.Seek(4, io.SeekStart)
+	c.Assert(err, jc.ErrorIsNil)
+
+	content, err := prepareUploadContent(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	defer content.cleanup()
+	c.Check(content.size, gc.Equals, int64(10))
+	c.Check(content.sha256, gc.Equals, sha256String("0123456789"))
+
+	// Offsets are from where the reader was when the content was prepared.
+	c.Assert(content.seek(6), jc.ErrorIsNil)
+	rest, err := io.ReadAll(content)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(rest), gc.Equals, "6789")
+}
+
+func (*bootResourceUploadSuite) TestPrepareUploadContentSpools(c *gc.C) {
+	// A MultiReader can't seek, so the content is copied to a file.
+	content, err := prepareUploadContent(io.MultiReader(strings.NewReader("0123456789")))
+	c.Assert(err, jc.ErrorIsNil)
+	file := content.ReadSeeker.(*os.File)
+	c.Check(content.size, gc.Equals, int64(10))
+	c.Check(content.sha256, gc.Equals, sha256String("0123456789"))
+
+	c.Assert(content.seek(8), jc.ErrorIsNil)
+	rest, err := io.ReadAll(content)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(rest), gc.Equals, "89")
+
+	content.cleanup()
+	_, err = os.Stat(file.Name())
+	c.Check(os.IsNotExist(err), jc.IsTrue)
+}
+
+func (*bootResourceUploadSuite) TestReadBootResourceUpload(c *gc.C) {
+	upload, err := readBootResourceUpload(parseJSON(c, bootResourceUploadResponse(0.4)), "tgz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(upload, jc.DeepEquals, &bootResourceUpload{
+		id:        9,
+		uploadURI: "/MAAS/api/2.0/boot-resources/9/upload/12/",
+		size:      10,
+
+		uploaded:      4,
+		uploadedKnown: true,
+	})
+}
+
+func (*bootResourceUploadSuite) TestReadBootResourceUploadPercentage(c *gc.C) {
+	// A percentage isn't the fraction of the bytes that MAAS has.
+	upload, err := readBootResourceUpload(parseJSON(c, bootResourceUploadResponse(40)), "tgz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(upload.uploadedKnown, jc.IsFalse)
+}
+
+func (*bootResourceUploadSuite) TestUploadedBytes(c *gc.C) {
+	for i, test := range []struct {
+		progress float64
+		size     int64
+		uploaded int64
+		known    bool
+	}{
+		{0, 10, 0, true},
+		{0.4, 10, 4, true},
+		{1, 10, 10, true},
+		{4194304.0 / 3221225472.0, 3221225472, 4194304, true},
+		{0, 0, 0, true},
+		// 0.41 of 10 bytes isn't a whole number of bytes.
+		{0.41, 10, 0, false},
+		// A progress that was rounded before it was reported.
+		{0.00130208, 3221225472, 0, false},
+		{40, 10, 0, false},
+		{-0.1, 10, 0, false},
+		{0.5, 0, 0, false},
+	} {
+		c.Logf("test %d: %v of %d", i, test.progress, test.size)
+		uploaded, known := uploadedBytes(test.progress, test.size)
+		c.Check(uploaded, gc.Equals, test.uploaded)
+		c.Check(known, gc.Equals, test.known)
+	}
+}
+
+func (*bootResourceUploadSuite) TestReadBootResourceUploadComplete(c *gc.C) {
+	source := parseJSON(c, bootResourceUploadResponse(0))
+	file := source.(map[string]interface{})["sets"].(map[string]interface{})["20221017"].(map[string]interface{})["files"].(map[string]interface{})["root-tgz"].(map[string]interface{})
+	file["complete"] = true
+	delete(file, "upload_uri")
+	delete(file, "progress")
+	upload, err := readBootResourceUpload(source, "tgz")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(upload.complete, jc.IsTrue)
+	c.Check(upload.uploaded, gc.Equals, int64(10))
+}
+
+func (*bootResourceUploadSuite) TestReadBootResourceUploadNoSets(c *gc.C) {
+	_, err := readBootResourceUpload(parseJSON(c, `{"id": 9, "sets": {}}`), "tgz")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err.Error(), gc.Equals, "boot resource has no sets")
+}
+
+func (*bootResourceUploadSuite) TestReadBootResourceUploadMissingFile(c *gc.C) {
+	source := parseJSON(c, bootResourceUploadResponse(0))
+	files := source.(map[string]interface{})["sets"].(map[string]interface{})["20221017"].(map[string]interface{})["files"].(map[string]interface{})
+	files["root-dd"] = map[string]interface{}{"filetype": "ddraw", "size": 10}
+	_, err := readBootResourceUpload(source, "ddtgz")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err.Error(), gc.Equals, "boot resource has no ddtgz file")
+}
+
+// bootResourceUploadResponse returns a boot resource with a 10 byte file,
+// of which the given fraction has been uploaded.
+func bootResourceUploadResponse(progress float64) string {
+	return `{
+        "architecture": "amd64/generic",
+        "type": "Uploaded",
+        "subarches": "generic",
+        "name": "custom/golden",
+        "id": 9,
+        "sets": {
+            "20221017": {
+                "version": "20221017",
+                "label": "uploaded",
+                "size": 10,
+                "complete": false,
+                "progress": 0,
+                "files": {
+                    "root-tgz": {
+                        "filename": "root-tgz",
+                        "filetype": "tgz",
+                        "sha256": "` + sha256String("0123456789") + `",
+                        "size": 10,
+                        "complete": false,
+                        "progress": ` + fmt.Sprint(progress) + `,
+                        "upload_uri": "/MAAS/api/2.0/boot-resources/9/upload/12/"
+                    }
+                }
+            }
+        },
+        "resource_uri": "/MAAS/api/2.0/boot-resources/9/"
+    }`
+}
@@ -0,0 +1,283 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"encoding/base64"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type bootSource struct {
+	controller *controller
+
+	resourceURI string
+
+	id              int
+	url             string
+	keyringFilename string
+	keyringData     []byte
+	created         string
+	updated         string
+}
+
+func (b *bootSource) updateFrom(other *bootSource) {
+	b.resourceURI = other.resourceURI
+	b.id = other.id
+	b.url = other.url
+	b.keyringFilename = other.keyringFilename
+	b.keyringData = other.keyringData
+	b.created = other.created
+	b.updated = other.updated
+}
+
+// ID implements BootSource.
+func (b *bootSource) ID() int {
+	return b.id
+}
+
+// URL implements BootSource.
+func (b *bootSource) URL() string {
+	return b.url
+}
+
+// KeyringFilename implements BootSource.
+func (b *bootSource) KeyringFilename() string {
+	return b.keyringFilename
+}
+
+// KeyringData implements BootSource.
+func (b *bootSource) KeyringData() []byte {
+	return b.keyringData
+}
+
+// Created implements BootSource.
+func (b *bootSource) Created() string {
+	return b.created
+}
+
+// Updated implements BootSource.
+func (b *bootSource) Updated() string {
+	return b.updated
+}
+
+// UpdateBootSourceArgs is an argument struct for passing parameters to the
+// BootSource.Update method. Only the values that are specified are changed.
+type UpdateBootSourceArgs struct {
+	URL string
+	// KeyringFilename is the path of the keyring on the region
+	// controller. The keyring data of a boot source can only be set when
+	// the boot source is created.
+	KeyringFilename string
+}
+
+// Update implements BootSource.
+func (b *bootSource) Update(args UpdateBootSourceArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("url", args.URL)
+	params.MaybeAdd("keyring_filename", args.KeyringFilename)
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := b.controller.put(b.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readBootSource(b.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	b.updateFrom(response)
+	return nil
+}
+
+// Delete implements BootSource.
+func (b *bootSource) Delete() error {
+	err := b.controller.delete(b.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// Selections implements BootSource.
+func (b *bootSource) Selections() ([]BootSourceSelection, error) {
+	source, err := b.controller.get(b.resourceURI + "selections")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	selections, err := readBootSourceSelections(b.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []BootSourceSelection
+	for _, s := range selections {
+		s.controller = b.controller
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// CreateBootSourceSelectionArgs is an argument struct for passing parameters
+// to the BootSource.CreateSelection method.
+type CreateBootSourceSelectionArgs struct {
+	// OS and Release are required, for example "ubuntu" and "jammy".
+	OS      string
+	Release string
+
+	// Arches, Subarches and Labels select all of the values available
+	// from the boot source if they are not specified.
+	Arches    []string
+	Subarches []string
+	Labels    []string
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *CreateBootSourceSelectionArgs) Validate() error {
+	if a.OS == "" {
+		return errors.NotValidf("missing OS")
+	}
+	if a.Release == "" {
+		return errors.NotValidf("missing Release")
+	}
+	return nil
+}
+
+// CreateSelection implements BootSource.
+func (b *bootSource) CreateSelection(args CreateBootSourceSelectionArgs) (BootSourceSelection, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("os", args.OS)
+	params.Values.Add("release", args.Release)
+	params.MaybeAddMany("arches", args.Arches)
+	params.MaybeAddMany("subarches", args.Subarches)
+	params.MaybeAddMany("labels", args.Labels)
+	source, err := b.controller.post(EnsureTrailingSlash(b.resourceURI)+"selections/", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	selection, err := readBootSourceSelection(b.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	selection.controller = b.controller
+	return selection, nil
+}
+
+func readBootSource(controllerVersion version.Number, source interface{}) (*bootSource, error) {
+	readFunc, err := getBootSourceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "boot source base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readBootSources(controllerVersion version.Number, source interface{}) ([]*bootSource, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "boot source base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getBootSourceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readBootSourceList(valid, readFunc)
+}
+
+func getBootSourceDeserializationFunc(controllerVersion version.Number) (bootSourceDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range bootSourceDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no boot source read func for version %s", controllerVersion)
+	}
+	return bootSourceDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readBootSourceList expects the values of the sourceList to be string maps.
+func readBootSourceList(sourceList []interface{}, readFunc bootSourceDeserializationFunc) ([]*bootSource, error) {
+	result := make([]*bootSource, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for boot source %d, %T", i, value)
+		}
+		bootSource, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "boot source %d", i)
+		}
+		result = append(result, bootSource)
+	}
+	return result, nil
+}
+
+type bootSourceDeserializationFunc func(map[string]interface{}) (*bootSource, error)
+
+var bootSourceDeserializationFuncs = map[version.Number]bootSourceDeserializationFunc{
+	twoDotOh: bootSource_2_0,
+}
+
+func bootSource_2_0(source map[string]interface{}) (*bootSource, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":               schema.ForceInt(),
+		"url":              schema.String(),
+		"keyring_filename": schema.String(),
+		// The keyring data is base64 encoded.
+		"keyring_data": schema.String(),
+		"created":      schema.String(),
+		"updated":      schema.String(),
+	}
+	defaults := schema.Defaults{
+		"keyring_filename": "",
+		"keyring_data":     "",
+		"created":          "",
+		"updated":          "",
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "boot source 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	keyringData, err := base64.StdEncoding.DecodeString(valid["keyring_data"].(string))
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "boot source keyring data")
+	}
+
+	result := &bootSource{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:              valid["id"].(int),
+		url:             valid["url"].(string),
+		keyringFilename: valid["keyring_filename"].(string),
+		keyringData:     keyringData,
+		created:         valid["created"].(string),
+		updated:         valid["updated"].(string),
+	}
+	return result, nil
+}
@@ -0,0 +1,180 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type bootSourceSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&bootSourceSuite{})
+
+func (*bootSourceSuite) TestReadBootSourcesBadSchema(c *gc.C) {
+	_, err := readBootSources(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `boot source base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*bootSourceSuite) TestReadBootSources(c *gc.C) {
+	bootSources, err := readBootSources(twoDotOh, parseJSON(c, bootSourcesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bootSources, gc.HasLen, 2)
+
+	stable := bootSources[0]
+	c.Check(stable.ID(), gc.Equals, 1)
+	c.Check(stable.URL(), gc.Equals, "http://images.maas.io/ephemeral-v3/stable/")
+	c.Check(stable.KeyringFilename(), gc.Equals, "/usr/share/keyrings/ubuntu-cloudimage-keyring.gpg")
+	c.Check(stable.KeyringData(), gc.HasLen, 0)
+	c.Check(stable.Created(), gc.Equals, "2022-10-01T10:00:00.000")
+	c.Check(stable.Updated(), gc.Equals, "2022-10-02T10:00:00.000")
+
+	mirror := bootSources[1]
+	c.Check(mirror.KeyringFilename(), gc.Equals, "")
+	c.Check(string(mirror.KeyringData()), gc.Equals, "keyring")
+}
+
+func (*bootSourceSuite) TestReadBootSourceBadKeyringData(c *gc.C) {
+	source := parseJSON(c, bootSourceResponse)
+	source.(map[string]interface{})["keyring_data"] = "not base64!"
+	_, err := readBootSource(twoDotOh, source)
+	c.Assert(err, jc.Satisfies, IsDeserializationError)
+}
+
+func (*bootSourceSuite) TestLowVersion(c *gc.C) {
+	_, err := readBootSources(version.MustParse("1.9.0"), parseJSON(c, bootSourcesResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*bootSourceSuite) TestHighVersion(c *gc.C) {
+	bootSources, err := readBootSources(version.MustParse("2.1.9"), parseJSON(c, bootSourcesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bootSources, gc.HasLen, 2)
+}
+
+func (s *bootSourceSuite) getServerAndBootSource(c *gc.C) (*SimpleTestServer, *bootSource) {
+	server, ctrl := createTestServerController(c, s)
+	bootSources, err := readBootSources(twoDotOh, parseJSON(c, bootSourcesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	bootSources[0].controller = ctrl.(*controller)
+	return server, bootSources[0]
+}
+
+func (s *bootSourceSuite) TestUpdate(c *gc.C) {
+	server, bootSource := s.getServerAndBootSource(c)
+	response := updateJSONMap(c, bootSourceResponse, map[string]interface{}{
+		"url": "http://images.maas.io/ephemeral-v3/candidate/",
+	})
+	server.AddPutResponse(bootSource.resourceURI, http.StatusOK, response)
+	err := bootSource.Update(UpdateBootSourceArgs{
+		URL: "http://images.maas.io/ephemeral-v3/candidate/",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bootSource.URL(), gc.Equals, "http://images.maas.io/ephemeral-v3/candidate/")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 1)
+	c.Check(form.Get("url"), gc.Equals, "http://images.maas.io/ephemeral-v3/candidate/")
+}
+
+func (s *bootSourceSuite) TestUpdateNothing(c *gc.C) {
+	server, bootSource := s.getServerAndBootSource(c)
+	count := server.RequestCount()
+	err := bootSource.Update(UpdateBootSourceArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *bootSourceSuite) TestUpdateBadRequest(c *gc.C) {
+	server, bootSource := s.getServerAndBootSource(c)
+	server.AddPutResponse(bootSource.resourceURI, http.StatusBadRequest, "url in use")
+	err := bootSource.Update(UpdateBootSourceArgs{URL: "http://mirror.example.com/images/"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "url in use")
+}
+
+func (s *bootSourceSuite) TestDelete(c *gc.C) {
+	server, bootSource := s.getServerAndBootSource(c)
+	server.AddDeleteResponse(bootSource.resourceURI, http.StatusNoContent, "")
+	err := bootSource.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bootSourceSuite) TestDeleteMissing(c *gc.C) {
+	_, bootSource := s.getServerAndBootSource(c)
+	err := bootSource.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *bootSourceSuite) TestSelections(c *gc.C) {
+	server, bootSource := s.getServerAndBootSource(c)
+	server.AddGetResponse(bootSource.resourceURI+"selections/", http.StatusOK, bootSourceSelectionsResponse)
+	selections, err := bootSource.Selections()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(selections, gc.HasLen, 2)
+	c.Check(selections[0].(*bootSourceSelection).controller, gc.Equals, bootSource.controller)
+}
+
+func (s *bootSourceSuite) TestCreateSelection(c *gc.C) {
+	server, bootSource := s.getServerAndBootSource(c)
+	server.AddPostResponse(bootSource.resourceURI+"selections/?op=", http.StatusOK, bootSourceSelectionResponse)
+	selection, err := bootSource.CreateSelection(CreateBootSourceSelectionArgs{
+		OS:      "ubuntu",
+		Release: "jammy",
+		Arches:  []string{"amd64", "arm64"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(selection.Release(), gc.Equals, "jammy")
+	c.Check(selection.(*bootSourceSelection).controller, gc.Equals, bootSource.controller)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 3)
+	c.Check(form.Get("os"), gc.Equals, "ubuntu")
+	c.Check(form.Get("release"), gc.Equals, "jammy")
+	c.Check(form["arches"], jc.DeepEquals, []string{"amd64", "arm64"})
+}
+
+func (s *bootSourceSuite) TestCreateSelectionValidates(c *gc.C) {
+	_, bootSource := s.getServerAndBootSource(c)
+	_, err := bootSource.CreateSelection(CreateBootSourceSelectionArgs{Release: "jammy"})
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err.Error(), gc.Equals, "missing OS not valid")
+	_, err = bootSource.CreateSelection(CreateBootSourceSelectionArgs{OS: "ubuntu"})
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err.Error(), gc.Equals, "missing Release not valid")
+}
+
+var bootSourceResponse = `
+{
+    "id": 1,
+    "url": "http://images.maas.io/ephemeral-v3/stable/",
+    "keyring_filename": "/usr/share/keyrings/ubuntu-cloudimage-keyring.gpg",
+    "keyring_data": "",
+    "created": "2022-10-01T10:00:00.000",
+    "updated": "2022-10-02T10:00:00.000",
+    "resource_uri": "/MAAS/api/2.0/boot-sources/1/"
+}
+`
+
+var bootSourcesResponse = `
+[` + bootSourceResponse + `,
+    {
+        "id": 2,
+        "url": "http://mirror.example.com/images/",
+        "keyring_filename": "",
+        "keyring_data": "a2V5cmluZw==",
+        "created": "2022-10-03T10:00:00.000",
+        "updated": "2022-10-03T10:00:00.000",
+        "resource_uri": "/MAAS/api/2.0/boot-sources/2/"
+    }
+]
+`
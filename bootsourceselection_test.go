@@ -0,0 +1,135 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type bootSourceSelectionSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&bootSourceSelectionSuite{})
+
+func (*bootSourceSelectionSuite) TestReadBootSourceSelectionsBadSchema(c *gc.C) {
+	_, err := readBootSourceSelections(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `boot source selection base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*bootSourceSelectionSuite) TestReadBootSourceSelections(c *gc.C) {
+	selections, err := readBootSourceSelections(twoDotOh, parseJSON(c, bootSourceSelectionsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(selections, gc.HasLen, 2)
+
+	selection := selections[0]
+	c.Check(selection.ID(), gc.Equals, 3)
+	c.Check(selection.BootSourceID(), gc.Equals, 1)
+	c.Check(selection.OS(), gc.Equals, "ubuntu")
+	c.Check(selection.Release(), gc.Equals, "jammy")
+	c.Check(selection.Arches(), jc.DeepEquals, []string{"amd64", "arm64"})
+	c.Check(selection.Subarches(), jc.DeepEquals, []string{"*"})
+	c.Check(selection.Labels(), jc.DeepEquals, []string{"*"})
+
+	selection = selections[1]
+	c.Check(selection.Release(), gc.Equals, "focal")
+	c.Check(selection.Subarches(), gc.HasLen, 0)
+}
+
+func (*bootSourceSelectionSuite) TestLowVersion(c *gc.C) {
+	_, err := readBootSourceSelections(version.MustParse("1.9.0"), parseJSON(c, bootSourceSelectionsResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*bootSourceSelectionSuite) TestHighVersion(c *gc.C) {
+	selections, err := readBootSourceSelections(version.MustParse("2.1.9"), parseJSON(c, bootSourceSelectionsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(selections, gc.HasLen, 2)
+}
+
+func (s *bootSourceSelectionSuite) getServerAndSelection(c *gc.C) (*SimpleTestServer, *bootSourceSelection) {
+	server, ctrl := createTestServerController(c, s)
+	selections, err := readBootSourceSelections(twoDotOh, parseJSON(c, bootSourceSelectionsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	selections[0].controller = ctrl.(*controller)
+	return server, selections[0]
+}
+
+func (s *bootSourceSelectionSuite) TestUpdate(c *gc.C) {
+	server, selection := s.getServerAndSelection(c)
+	response := updateJSONMap(c, bootSourceSelectionResponse, map[string]interface{}{
+		"arches": []interface{}{"amd64"},
+	})
+	server.AddPutResponse(selection.resourceURI, http.StatusOK, response)
+	err := selection.Update(UpdateBootSourceSelectionArgs{Arches: []string{"amd64"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(selection.Arches(), jc.DeepEquals, []string{"amd64"})
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 1)
+	c.Check(form["arches"], jc.DeepEquals, []string{"amd64"})
+}
+
+func (s *bootSourceSelectionSuite) TestUpdateNothing(c *gc.C) {
+	server, selection := s.getServerAndSelection(c)
+	count := server.RequestCount()
+	err := selection.Update(UpdateBootSourceSelectionArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *bootSourceSelectionSuite) TestUpdateBadRequest(c *gc.C) {
+	server, selection := s.getServerAndSelection(c)
+	server.AddPutResponse(selection.resourceURI, http.StatusBadRequest, "release already selected")
+	err := selection.Update(UpdateBootSourceSelectionArgs{Release: "focal"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "release already selected")
+}
+
+func (s *bootSourceSelectionSuite) TestDelete(c *gc.C) {
+	server, selection := s.getServerAndSelection(c)
+	server.AddDeleteResponse(selection.resourceURI, http.StatusNoContent, "")
+	err := selection.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *bootSourceSelectionSuite) TestDeleteMissing(c *gc.C) {
+	_, selection := s.getServerAndSelection(c)
+	err := selection.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+var bootSourceSelectionResponse = `
+{
+    "id": 3,
+    "boot_source_id": 1,
+    "os": "ubuntu",
+    "release": "jammy",
+    "arches": ["amd64", "arm64"],
+    "subarches": ["*"],
+    "labels": ["*"],
+    "resource_uri": "/MAAS/api/2.0/boot-sources/1/selections/3/"
+}
+`
+
+var bootSourceSelectionsResponse = `
+[` + bootSourceSelectionResponse + `,
+    {
+        "id": 4,
+        "boot_source_id": 1,
+        "os": "ubuntu",
+        "release": "focal",
+        "arches": ["amd64"],
+        "subarches": [],
+        "labels": ["*"],
+        "resource_uri": "/MAAS/api/2.0/boot-sources/1/selections/4/"
+    }
+]
+`
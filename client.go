@@ -252,6 +252,19 @@ func (client Client) Put(uri *url.URL, parameters url.Values) ([]byte, error) {
 	return client.nonIdempotentRequest("PUT", uri, parameters)
 }
 
+// PutContent sends the content as the body of an HTTP "PUT" request,
+// rather than as form values. It is used to upload data that is too large
+// to send in a single request.
+func (client Client) PutContent(uri *url.URL, content []byte) ([]byte, error) {
+	url := client.GetURL(uri)
+	request, err := http.NewRequest("PUT", url.String(), bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("Content-Type", "application/octet-stream")
+	return client.dispatchRequest(request)
+}
+
 // Delete deletes an object on the API, using an HTTP "DELETE" request.
 func (client Client) Delete(uri *url.URL) error {
 	url := client.GetURL(uri)
@@ -335,6 +335,23 @@ func (suite *ClientSuite) TestClientPutSendsRequest(c *gc.C) {
 	c.Check(*server.requestContent, gc.Equals, "test=123")
 }
 
+func (suite *ClientSuite) TestClientPutContentSendsRequest(c *gc.C) {
+	URI, err := url.Parse("/some/url")
+	c.Assert(err, jc.ErrorIsNil)
+	expectedResult := "expected:result"
+	server := newSingleServingServer(URI.String(), expectedResult, http.StatusOK, -1)
+	defer server.Close()
+	client, err := NewAnonymousClient(server.URL, "1.0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := client.PutContent(URI, []byte("some content"))
+
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(string(result), gc.Equals, expectedResult)
+	c.Check(*server.requestContent, gc.Equals, "some content")
+	c.Check((*server.requestHeader).Get("Content-Type"), gc.Equals, "application/octet-stream")
+}
+
 func (suite *ClientSuite) TestClientDeleteSendsRequest(c *gc.C) {
 	URI, err := url.Parse("/some/url")
 	c.Assert(err, jc.ErrorIsNil)
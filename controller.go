@@ -14,6 +14,7 @@ import (
 	"strconv"
 	"strings"
 	"sync/atomic"
+	"time"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
@@ -157,6 +158,604 @@ func (c *controller) BootResources() ([]BootResource, error) {
 	return result, nil
 }
 
+// GetBootResource implements Controller.
+func (c *controller) GetBootResource(id int) (BootResource, error) {
+	source, err := c.get(fmt.Sprintf("boot-resources/%d", id))
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	resource, err := readBootResource(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return resource, nil
+}
+
+// ImportBootResources implements Controller.
+func (c *controller) ImportBootResources() error {
+	// The response is just a message saying that the import has started.
+	_, err := c._postRaw("boot-resources", "import", nil, nil)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// StopImport implements Controller.
+func (c *controller) StopImport() error {
+	_, err := c._postRaw("boot-resources", "stop_import", nil, nil)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// IsImporting implements Controller.
+func (c *controller) IsImporting() (bool, error) {
+	source, err := c.getOp("boot-resources", "is_importing")
+	if err != nil {
+		return false, translateServerError(err)
+	}
+	importing, ok := source.(bool)
+	if !ok {
+		return false, NewDeserializationError("unexpected is_importing response %T", source)
+	}
+	return importing, nil
+}
+
+// UploadBootResourceArgs is an argument struct for passing parameters to
+// the Controller.UploadBootResource method.
+type UploadBootResourceArgs struct {
+	// Name, Architecture and Content are required. Custom images have
+	// names like "custom/golden", and architectures like "amd64/generic".
+	Name         string
+	Title        string
+	Architecture string
+
+	// Filetype is the format of the content, such as "tgz" or "ddraw". If
+	// not specified, the content must be a "tgz" root filesystem.
+	Filetype string
+	Content  io.Reader
+
+	// ChunkSize is the number of bytes sent in each request. If not
+	// specified, the content is sent in 4MiB chunks.
+	ChunkSize int
+
+	// Progress, if specified, is called as the upload proceeds with the
+	// number of bytes uploaded and the total size of the content.
+	Progress func(uploaded, total int64)
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *UploadBootResourceArgs) Validate() error {
+	if a.Name == "" {
+		return errors.NotValidf("missing Name")
+	}
+	if a.Architecture == "" {
+		return errors.NotValidf("missing Architecture")
+	}
+	if a.Content == nil {
+		return errors.NotValidf("missing Content")
+	}
+	if a.ChunkSize < 0 {
+		return errors.NotValidf("negative ChunkSize")
+	}
+	return nil
+}
+
+// UploadBootResource implements Controller.
+func (c *controller) UploadBootResource(args UploadBootResourceArgs) (BootResource, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	filetype := args.Filetype
+	if filetype == "" {
+		filetype = "tgz"
+	}
+	chunkSize := args.ChunkSize
+	if chunkSize == 0 {
+		chunkSize = bootResourceUploadChunkSize
+	}
+	progress := args.Progress
+	if progress == nil {
+		progress = func(uploaded, total int64) {}
+	}
+
+	content, err := prepareUploadContent(args.Content)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer content.cleanup()
+
+	// Creating the resource with the SHA256 and size, but without the
+	// content, lets the content be uploaded in chunks afterwards.
+	params := NewURLParams()
+	params.Values.Add("name", args.Name)
+	params.MaybeAdd("title", args.Title)
+	params.Values.Add("architecture", args.Architecture)
+	params.Values.Add("filetype", filetype)
+	params.Values.Add("sha256", content.sha256)
+	params.Values.Add("size", fmt.Sprint(content.size))
+	create := func() (*bootResourceUpload, error) {
+		source, err := c.post("boot-resources", "", params.Values)
+		if err != nil {
+			return nil, translateServerError(err)
+		}
+		upload, err := readBootResourceUpload(source, filetype)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if upload.size != content.size {
+			return nil, errors.Errorf("boot resource %d expects %d bytes, not %d", upload.id, upload.size, content.size)
+		}
+		return upload, nil
+	}
+	upload, err := create()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	upload, err = c.uploadBootResourceContent(upload, content, filetype, chunkSize, progress, create)
+	if err != nil {
+		return nil, errors.Annotatef(err, "uploading boot resource %d", upload.id)
+	}
+	return c.GetBootResource(upload.id)
+}
+
+// uploadBootResourceContent uploads the content from the point that MAAS
+// has got to. When a chunk fails to upload, MAAS is asked how much of the
+// content it has so that the upload resumes from there. MAAS appends each
+// chunk to what it has, so when the number of bytes it has can't be told
+// from its progress, the resource is deleted and created again to restart
+// the upload. The upload that was finished, or got furthest, is returned.
+func (c *controller) uploadBootResourceContent(upload *bootResourceUpload, content *uploadContent, filetype string, chunkSize int, progress func(uploaded, total int64), create func() (*bootResourceUpload, error)) (*bootResourceUpload, error) {
+	buffer := make([]byte, chunkSize)
+	failures := 0
+	for !upload.complete && upload.uploaded < content.size {
+		if err := content.seek(upload.uploaded); err != nil {
+			return upload, errors.Trace(err)
+		}
+		n, err := io.ReadFull(content, buffer)
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return upload, errors.Annotate(err, "reading content")
+		}
+		err = c.putContent(upload.uploadURI, buffer[:n])
+		if err == nil {
+			failures = 0
+			upload.uploaded += int64(n)
+			progress(upload.uploaded, content.size)
+			continue
+		}
+		// Only failures that might not happen again are retried.
+		if serverErr, ok := GetServerError(err); ok && serverErr.StatusCode < http.StatusInternalServerError {
+			return upload, translateServerError(err)
+		}
+		failures++
+		if failures > bootResourceUploadRetries {
+			return upload, errors.Annotatef(translateServerError(err), "giving up after %d failures", failures)
+		}
+		logger.Debugf("uploading boot resource %d failed, resuming: %v", upload.id, err)
+		path := fmt.Sprintf("boot-resources/%d", upload.id)
+		source, err := c.get(path)
+		if err != nil {
+			return upload, translateServerError(err)
+		}
+		resumed, err := readBootResourceUpload(source, filetype)
+		if err != nil {
+			return upload, errors.Trace(err)
+		}
+		if !resumed.uploadedKnown {
+			logger.Debugf("can't tell how much of boot resource %d MAAS has, restarting the upload", upload.id)
+			if err := c.delete(path); err != nil {
+				return upload, translateServerError(err)
+			}
+			if resumed, err = create(); err != nil {
+				return upload, errors.Annotate(err, "restarting upload")
+			}
+		}
+		upload = resumed
+		progress(upload.uploaded, content.size)
+	}
+	return upload, nil
+}
+
+// BootSources implements Controller.
+func (c *controller) BootSources() ([]BootSource, error) {
+	source, err := c.get("boot-sources")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	bootSources, err := readBootSources(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []BootSource
+	for _, b := range bootSources {
+		b.controller = c
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+// CreateBootSourceArgs is an argument struct for passing parameters to the
+// Controller.CreateBootSource method.
+type CreateBootSourceArgs struct {
+	// URL is required, and is the URL of a simplestreams mirror.
+	URL string
+
+	// Exactly one of KeyringFilename or KeyringData must be specified to
+	// verify the images. KeyringFilename is the path of a keyring on the
+	// region controller, and KeyringData is the content of a keyring.
+	KeyringFilename string
+	KeyringData     []byte
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *CreateBootSourceArgs) Validate() error {
+	if a.URL == "" {
+		return errors.NotValidf("missing URL")
+	}
+	if a.KeyringFilename == "" && len(a.KeyringData) == 0 {
+		return errors.NotValidf("missing KeyringFilename or KeyringData")
+	}
+	if a.KeyringFilename != "" && len(a.KeyringData) != 0 {
+		return errors.NotValidf("both KeyringFilename and KeyringData")
+	}
+	return nil
+}
+
+// CreateBootSource implements Controller.
+func (c *controller) CreateBootSource(args CreateBootSourceArgs) (BootSource, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("url", args.URL)
+	params.MaybeAdd("keyring_filename", args.KeyringFilename)
+	var files map[string][]byte
+	if len(args.KeyringData) != 0 {
+		// MAAS expects the keyring data to be uploaded as a file.
+		files = map[string][]byte{"keyring_data": args.KeyringData}
+	}
+	bytes, err := c._postRaw("boot-sources", "", params.Values, files)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	var parsed interface{}
+	if err := json.Unmarshal(bytes, &parsed); err != nil {
+		return nil, errors.Trace(err)
+	}
+	bootSource, err := readBootSource(c.apiVersion, parsed)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	bootSource.controller = c
+	return bootSource, nil
+}
+
+// RackControllers implements Controller.
+func (c *controller) RackControllers() ([]RackController, error) {
+	source, err := c.get("rackcontrollers")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	nodes, err := readControllerNodes(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []RackController
+	for _, n := range nodes {
+		n.controller = c
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// RegionControllers implements Controller.
+func (c *controller) RegionControllers() ([]RegionController, error) {
+	source, err := c.get("regioncontrollers")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	nodes, err := readControllerNodes(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []RegionController
+	for _, n := range nodes {
+		n.controller = c
+		result = append(result, n)
+	}
+	return result, nil
+}
+
+// ResolveRack implements Controller.
+func (c *controller) ResolveRack(systemID string) (RackController, error) {
+	if systemID == "" {
+		return nil, errors.NotValidf("missing systemID")
+	}
+	source, err := c.get("rackcontrollers/" + systemID)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	node, err := readControllerNode(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	node.controller = c
+	return node, nil
+}
+
+// VMHosts implements Controller.
+func (c *controller) VMHosts() ([]VMHost, error) {
+	source, err := c.get(vmHostsURI)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	hosts, err := readVMHosts(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []VMHost
+	for _, h := range hosts {
+		h.controller = c
+		result = append(result, h)
+	}
+	return result, nil
+}
+
+// vmHostsURI is the pods endpoint, rather than vm-hosts, because only MAAS
+// 2.9 and later have the vm-hosts endpoint.
+const vmHostsURI = "pods"
+
+// CreateVMHostArgs is an argument struct for passing parameters to the
+// Controller.CreateVMHost method.
+type CreateVMHostArgs struct {
+	// Type is "lxd" or "virsh" (required).
+	Type string
+	// PowerAddress is the address of the LXD server, or the virsh URI, of
+	// the VM host (required).
+	PowerAddress string
+
+	// PowerUser and PowerPassword are the credentials for virsh.
+	PowerUser     string
+	PowerPassword string
+
+	// Password is the trust password for LXD, which is used to add the
+	// certificate of MAAS, or the specified certificate and key, to the
+	// trusted certificates of the LXD server.
+	Password    string
+	Certificate string
+	Key         string
+	// Project is the LXD project that MAAS manages.
+	Project string
+
+	Name string
+	Zone string
+	Pool string
+	Tags []string
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *CreateVMHostArgs) Validate() error {
+	if a.Type == "" {
+		return errors.NotValidf("missing Type")
+	}
+	if a.PowerAddress == "" {
+		return errors.NotValidf("missing PowerAddress")
+	}
+	return nil
+}
+
+// CreateVMHost implements Controller.
+func (c *controller) CreateVMHost(args CreateVMHostArgs) (VMHost, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("type", args.Type)
+	params.Values.Add("power_address", args.PowerAddress)
+	params.MaybeAdd("power_user", args.PowerUser)
+	params.MaybeAdd("power_pass", args.PowerPassword)
+	params.MaybeAdd("password", args.Password)
+	params.MaybeAdd("certificate", args.Certificate)
+	params.MaybeAdd("key", args.Key)
+	params.MaybeAdd("project", args.Project)
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("zone", args.Zone)
+	params.MaybeAdd("pool", args.Pool)
+	params.MaybeAdd("tags", strings.Join(args.Tags, ","))
+	source, err := c.post(vmHostsURI, "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	host, err := readVMHost(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	host.controller = c
+	return host, nil
+}
+
+// EventsArgs is an argument struct for selecting Events. Only events that
+// match the specified criteria are returned.
+type EventsArgs struct {
+	Hostnames []string
+	SystemIDs []string
+	Zone      string
+	// Level is the minimum level of the events, one of "DEBUG", "INFO",
+	// "WARNING", "ERROR" or "CRITICAL".
+	Level string
+
+	// After and Before select the events with IDs greater or less than
+	// the specified IDs.
+	After  int
+	Before int
+
+	// Limit is the maximum number of events to return. MAAS returns 100
+	// events if it is not specified.
+	Limit int
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *EventsArgs) Validate() error {
+	if a.After < 0 {
+		return errors.NotValidf("negative After")
+	}
+	if a.Before < 0 {
+		return errors.NotValidf("negative Before")
+	}
+	if a.Limit < 0 {
+		return errors.NotValidf("negative Limit")
+	}
+	return nil
+}
+
+func (a *EventsArgs) params() url.Values {
+	params := NewURLParams()
+	params.MaybeAddMany("hostname", a.Hostnames)
+	params.MaybeAddMany("id", a.SystemIDs)
+	params.MaybeAdd("zone", a.Zone)
+	params.MaybeAdd("level", a.Level)
+	params.MaybeAddInt("after", a.After)
+	params.MaybeAddInt("before", a.Before)
+	params.MaybeAddInt("limit", a.Limit)
+	return params.Values
+}
+
+// Events implements Controller.
+func (c *controller) Events(args EventsArgs) ([]Event, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	page, err := c.queryEvents(args.params())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []Event
+	for _, e := range page.events {
+		result = append(result, e)
+	}
+	return result, nil
+}
+
+// EventHistory implements Controller.
+func (c *controller) EventHistory(args EventsArgs) EventIterator {
+	if err := args.Validate(); err != nil {
+		return &eventIterator{err: errors.Trace(err)}
+	}
+	return &eventIterator{
+		controller: c,
+		params:     args.params(),
+	}
+}
+
+// eventFollowLimit is the number of events requested by each poll of
+// FollowEvents when the caller doesn't specify a limit.
+const eventFollowLimit = 100
+
+// FollowEvents implements Controller.
+func (c *controller) FollowEvents(args EventsArgs, interval time.Duration, stop <-chan struct{}) (<-chan Event, <-chan error) {
+	events := make(chan Event)
+	errs := make(chan error, 1)
+	fail := func(err error) {
+		errs <- err
+		close(events)
+		close(errs)
+	}
+	if err := args.Validate(); err != nil {
+		fail(errors.Trace(err))
+		return events, errs
+	}
+	// Older events are never delivered, only those that are newer than
+	// the events that already exist.
+	args.Before = 0
+	if args.Limit == 0 {
+		args.Limit = eventFollowLimit
+	}
+
+	go func() {
+		if args.After == 0 {
+			latest := args
+			latest.Limit = 1
+			page, err := c.queryEvents(latest.params())
+			if err != nil {
+				fail(errors.Trace(err))
+				return
+			}
+			if len(page.events) > 0 {
+				args.After = page.events[0].id
+			}
+		}
+		for {
+			page, err := c.queryEvents(args.params())
+			if err != nil {
+				fail(errors.Trace(err))
+				return
+			}
+			// The events of a page are newest first, so deliver them in
+			// reverse.
+			for i := len(page.events) - 1; i >= 0; i-- {
+				select {
+				case events <- page.events[i]:
+				case <-stop:
+					close(events)
+					close(errs)
+					return
+				}
+				args.After = page.events[i].id
+			}
+			// A full page means that more events may be waiting already.
+			if len(page.events) == args.Limit {
+				continue
+			}
+			select {
+			case <-time.After(interval):
+			case <-stop:
+				close(events)
+				close(errs)
+				return
+			}
+		}
+	}()
+	return events, errs
+}
+
+func (c *controller) queryEvents(params url.Values) (*eventPage, error) {
+	source, err := c._get("events", "query", params)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	page, err := readEventPage(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return page, nil
+}
+
+// getEventPage gets the events page with the specified URI, as returned in
+// the prev_uri or next_uri of another page.
+func (c *controller) getEventPage(uri string) (*eventPage, error) {
+	parsed, err := url.Parse(uri)
+	if err != nil {
+		return nil, NewDeserializationError("invalid event page uri %q", uri)
+	}
+	query := parsed.Query()
+	op := query.Get("op")
+	query.Del("op")
+	source, err := c._get(parsed.Path, op, query)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	page, err := readEventPage(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return page, nil
+}
+
 // Fabrics implements Controller.
 func (c *controller) Fabrics() ([]Fabric, error) {
 	source, err := c.get("fabrics")
@@ -169,11 +768,40 @@ func (c *controller) Fabrics() ([]Fabric, error) {
 	}
 	var result []Fabric
 	for _, f := range fabrics {
+		f.controller = c
 		result = append(result, f)
 	}
 	return result, nil
 }
 
+// CreateFabricArgs is an argument struct for passing parameters to the
+// Controller.CreateFabric method. All the values are optional, and MAAS
+// generates a name for the fabric if one isn't specified.
+type CreateFabricArgs struct {
+	Name        string
+	Description string
+	ClassType   string
+}
+
+// CreateFabric implements Controller.
+func (c *controller) CreateFabric(args CreateFabricArgs) (Fabric, error) {
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("description", args.Description)
+	params.MaybeAdd("class_type", args.ClassType)
+	source, err := c.post("fabrics", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	fabric, err := readFabric(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	fabric.controller = c
+	return fabric, nil
+}
+
 // Spaces implements Controller.
 func (c *controller) Spaces() ([]Space, error) {
 	source, err := c.get("spaces")
@@ -186,80 +814,757 @@ func (c *controller) Spaces() ([]Space, error) {
 	}
 	var result []Space
 	for _, space := range spaces {
+		space.controller = c
 		result = append(result, space)
 	}
 	return result, nil
 }
 
-// StaticRoutes implements Controller.
-func (c *controller) StaticRoutes() ([]StaticRoute, error) {
-	source, err := c.get("static-routes")
+// CreateSpace implements Controller.
+func (c *controller) CreateSpace(name, description string) (Space, error) {
+	params := NewURLParams()
+	params.MaybeAdd("name", name)
+	params.MaybeAdd("description", description)
+	source, err := c.post("spaces", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	space, err := readSpace(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	space.controller = c
+	return space, nil
+}
+
+// Subnets implements Controller.
+func (c *controller) Subnets() ([]Subnet, error) {
+	source, err := c.get("subnets")
 	if err != nil {
 		return nil, NewUnexpectedError(err)
 	}
-	staticRoutes, err := readStaticRoutes(c.apiVersion, source)
+	subnets, err := readSubnets(c.apiVersion, source)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	var result []StaticRoute
-	for _, staticRoute := range staticRoutes {
-		result = append(result, staticRoute)
+	var result []Subnet
+	for _, s := range subnets {
+		s.controller = c
+		result = append(result, s)
 	}
 	return result, nil
 }
 
-// Zones implements Controller.
-func (c *controller) Zones() ([]Zone, error) {
+// GetSubnet implements Controller.
+func (c *controller) GetSubnet(id int) (Subnet, error) {
+	source, err := c.get(fmt.Sprintf("subnets/%d", id))
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	subnet, err := readSubnet(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnet.controller = c
+	return subnet, nil
+}
+
+// CreateSubnetArgs is an argument struct for passing parameters to the
+// Controller.CreateSubnet method.
+type CreateSubnetArgs struct {
+	// CIDR is required.
+	CIDR string
+
+	// Name is optional, and MAAS uses the CIDR if it isn't specified.
+	Name string
+	// VLAN is optional, and MAAS uses the default VLAN of the default
+	// fabric if it isn't specified.
+	VLAN VLAN
+	// Space is the name of the space for the subnet, and is optional.
+	Space      string
+	GatewayIP  string
+	DNSServers []string
+
+	// AllowProxy, Managed and RDNSMode use the MAAS defaults if they
+	// aren't specified.
+	AllowProxy *bool
+	Managed    *bool
+	RDNSMode   *RDNSMode
+}
+
+// Validate ensures that the CIDR is specified.
+func (a *CreateSubnetArgs) Validate() error {
+	if a.CIDR == "" {
+		return errors.NotValidf("missing CIDR")
+	}
+	return nil
+}
+
+// CreateSubnet implements Controller.
+func (c *controller) CreateSubnet(args CreateSubnetArgs) (Subnet, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("cidr", args.CIDR)
+	params.MaybeAdd("name", args.Name)
+	if args.VLAN != nil {
+		params.Values.Add("vlan", fmt.Sprint(args.VLAN.ID()))
+	}
+	params.MaybeAdd("space", args.Space)
+	params.MaybeAdd("gateway_ip", args.GatewayIP)
+	if len(args.DNSServers) > 0 {
+		params.Values.Add("dns_servers", strings.Join(args.DNSServers, ","))
+	}
+	addSubnetOptions(params, args.AllowProxy, args.Managed, args.RDNSMode)
+	source, err := c.post("subnets", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	subnet, err := readSubnet(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	subnet.controller = c
+	return subnet, nil
+}
+
+// IPRanges implements Controller.
+func (c *controller) IPRanges() ([]IPRange, error) {
+	source, err := c.get("ipranges")
+	if err != nil {
+		return nil, NewUnexpectedError(err)
+	}
+	ipRanges, err := readIPRanges(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []IPRange
+	for _, r := range ipRanges {
+		r.controller = c
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// CreateIPRangeArgs is an argument struct for passing parameters to the
+// Controller.CreateIPRange method.
+type CreateIPRangeArgs struct {
+	// Type, StartIP and EndIP are required.
+	Type    IPRangeType
+	StartIP string
+	EndIP   string
+
+	// Subnet is optional, and MAAS determines it from the addresses if it
+	// isn't specified.
+	Subnet  Subnet
+	Comment string
+}
+
+// Validate ensures that the Type is known and that both ends of the range
+// are specified.
+func (a *CreateIPRangeArgs) Validate() error {
+	switch a.Type {
+	case IPRangeDynamic, IPRangeReserved:
+	case "":
+		return errors.NotValidf("missing Type")
+	default:
+		return errors.NotValidf("unknown Type value (%q)", a.Type)
+	}
+	if a.StartIP == "" {
+		return errors.NotValidf("missing StartIP")
+	}
+	if a.EndIP == "" {
+		return errors.NotValidf("missing EndIP")
+	}
+	return nil
+}
+
+// CreateIPRange implements Controller.
+func (c *controller) CreateIPRange(args CreateIPRangeArgs) (IPRange, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("type", string(args.Type))
+	params.Values.Add("start_ip", args.StartIP)
+	params.Values.Add("end_ip", args.EndIP)
+	if args.Subnet != nil {
+		params.Values.Add("subnet", fmt.Sprint(args.Subnet.ID()))
+	}
+	params.MaybeAdd("comment", args.Comment)
+	source, err := c.post("ipranges", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	ipRange, err := readIPRange(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ipRange.controller = c
+	return ipRange, nil
+}
+
+// IPAddressesArgs is an argument struct for selecting IPAddresses.
+// Only the addresses that match the specified criteria are returned.
+type IPAddressesArgs struct {
+	IPs []string
+	// All includes the addresses of all users, rather than just those of
+	// the calling user. Only administrators may specify All.
+	All bool
+	// Owner restricts the addresses to those of the specified user. Only
+	// administrators may specify Owner.
+	Owner string
+}
+
+// IPAddresses implements Controller.
+func (c *controller) IPAddresses(args IPAddressesArgs) ([]IPAddress, error) {
+	params := NewURLParams()
+	params.MaybeAddMany("ip", args.IPs)
+	params.MaybeAddBool("all", args.All)
+	params.MaybeAdd("owner", args.Owner)
+	source, err := c.getQuery("ipaddresses", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	ipAddresses, err := readIPAddresses(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []IPAddress
+	for _, a := range ipAddresses {
+		a.controller = c
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+// ReserveIPArgs is an argument struct for passing parameters to the
+// Controller.ReserveIPAddress method.
+type ReserveIPArgs struct {
+	// At least one of Subnet or IP must be specified. If only the Subnet is
+	// specified, MAAS picks a free address in the subnet.
+	Subnet Subnet
+	IP     string
+
+	// Hostname and MACAddress are optional. If a Hostname is specified
+	// MAAS creates a DNS record for the address.
+	Hostname   string
+	MACAddress string
+}
+
+// Validate ensures that either the Subnet or the IP is specified.
+func (a *ReserveIPArgs) Validate() error {
+	if a.Subnet == nil && a.IP == "" {
+		return errors.NotValidf("missing Subnet or IP")
+	}
+	return nil
+}
+
+// ReserveIPAddress implements Controller.
+func (c *controller) ReserveIPAddress(args ReserveIPArgs) (IPAddress, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	if args.Subnet != nil {
+		params.Values.Add("subnet", fmt.Sprint(args.Subnet.ID()))
+	}
+	params.MaybeAdd("ip", args.IP)
+	params.MaybeAdd("hostname", args.Hostname)
+	params.MaybeAdd("mac", args.MACAddress)
+	source, err := c.post("ipaddresses", "reserve", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	ipAddress, err := readIPAddress(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ipAddress.controller = c
+	return ipAddress, nil
+}
+
+// ReleaseIPAddress implements Controller.
+func (c *controller) ReleaseIPAddress(ip string, force bool) error {
+	if ip == "" {
+		return errors.NotValidf("missing ip")
+	}
+	params := NewURLParams()
+	params.Values.Add("ip", ip)
+	params.MaybeAddBool("force", force)
+	// The response of a release is empty, so there is nothing to read.
+	_, err := c._postRaw("ipaddresses", "release", params.Values, nil)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// StaticRoutes implements Controller.
+func (c *controller) StaticRoutes() ([]StaticRoute, error) {
+	source, err := c.get("static-routes")
+	if err != nil {
+		return nil, NewUnexpectedError(err)
+	}
+	staticRoutes, err := readStaticRoutes(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []StaticRoute
+	for _, staticRoute := range staticRoutes {
+		result = append(result, staticRoute)
+	}
+	return result, nil
+}
+
+// Zones implements Controller.
+func (c *controller) Zones() ([]Zone, error) {
 	source, err := c.get("zones")
 	if err != nil {
-		return nil, NewUnexpectedError(err)
+		return nil, NewUnexpectedError(err)
+	}
+	zones, err := readZones(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []Zone
+	for _, z := range zones {
+		z.controller = c
+		result = append(result, z)
+	}
+	return result, nil
+}
+
+// CreateZone implements Controller.
+func (c *controller) CreateZone(name, description string) (Zone, error) {
+	if name == "" {
+		return nil, errors.NotValidf("missing name")
+	}
+	params := NewURLParams()
+	params.Values.Add("name", name)
+	params.MaybeAdd("description", description)
+	source, err := c.post("zones", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	zone, err := readZone(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	zone.controller = c
+	return zone, nil
+}
+
+// ZoneSummary is a Zone along with the number of machines and devices in it.
+type ZoneSummary struct {
+	Zone     Zone
+	Machines int
+	Devices  int
+}
+
+// ZoneSummaries implements Controller.
+func (c *controller) ZoneSummaries() ([]ZoneSummary, error) {
+	source, err := c.get("zones")
+	if err != nil {
+		return nil, NewUnexpectedError(err)
+	}
+	zones, err := readZones(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var counts map[string]*nodeCounts
+	for _, z := range zones {
+		if !z.hasCounts {
+			counts, err = c.countNodes(func(z Zone, _ Pool) string {
+				if z == nil {
+					return ""
+				}
+				return z.Name()
+			})
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			break
+		}
+	}
+	var result []ZoneSummary
+	for _, z := range zones {
+		z.controller = c
+		summary := ZoneSummary{Zone: z, Machines: z.machines, Devices: z.devices}
+		if counts != nil {
+			summary.Machines, summary.Devices = counts[z.name].get()
+		}
+		result = append(result, summary)
+	}
+	return result, nil
+}
+
+// Pools implements Controller.
+func (c *controller) Pools() ([]Pool, error) {
+	var result []Pool
+
+	source, err := c.get("pools")
+	if err != nil {
+		return nil, NewUnexpectedError(err)
+	}
+
+	pools, err := readPools(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	for _, p := range pools {
+		p.controller = c
+		result = append(result, p)
+	}
+	return result, nil
+}
+
+// CreatePool implements Controller.
+func (c *controller) CreatePool(name, description string) (Pool, error) {
+	if name == "" {
+		return nil, errors.NotValidf("missing name")
+	}
+	params := NewURLParams()
+	params.Values.Add("name", name)
+	params.MaybeAdd("description", description)
+	source, err := c.post("pools", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	pool, err := readPool(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pool.controller = c
+	return pool, nil
+}
+
+// PoolSummary is a Pool along with the number of machines and devices in it.
+type PoolSummary struct {
+	Pool     Pool
+	Machines int
+	Devices  int
+}
+
+// PoolSummaries implements Controller.
+func (c *controller) PoolSummaries() ([]PoolSummary, error) {
+	source, err := c.get("pools")
+	if err != nil {
+		return nil, NewUnexpectedError(err)
+	}
+	pools, err := readPools(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var counts map[string]*nodeCounts
+	for _, p := range pools {
+		if !p.hasCounts {
+			counts, err = c.countNodes(func(_ Zone, p Pool) string {
+				if p == nil {
+					return ""
+				}
+				return p.Name()
+			})
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			break
+		}
+	}
+	var result []PoolSummary
+	for _, p := range pools {
+		p.controller = c
+		summary := PoolSummary{Pool: p, Machines: p.machines, Devices: p.devices}
+		if counts != nil {
+			summary.Machines, summary.Devices = counts[p.name].get()
+		}
+		result = append(result, summary)
+	}
+	return result, nil
+}
+
+type nodeCounts struct {
+	machines int
+	devices  int
+}
+
+func (n *nodeCounts) get() (machines, devices int) {
+	if n == nil {
+		return 0, 0
+	}
+	return n.machines, n.devices
+}
+
+// countNodes counts the machines and devices by the key returned for the
+// zone and pool of each node.
+func (c *controller) countNodes(key func(Zone, Pool) string) (map[string]*nodeCounts, error) {
+	machines, err := c.Machines(MachinesArgs{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	devices, err := c.Devices(DevicesArgs{})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	counts := make(map[string]*nodeCounts)
+	countsFor := func(name string) *nodeCounts {
+		if counts[name] == nil {
+			counts[name] = &nodeCounts{}
+		}
+		return counts[name]
+	}
+	for _, m := range machines {
+		countsFor(key(m.Zone(), m.Pool())).machines++
+	}
+	for _, d := range devices {
+		countsFor(key(d.Zone(), d.Pool())).devices++
+	}
+	return counts, nil
+}
+
+// Domains implements Controller
+func (c *controller) Domains() ([]Domain, error) {
+	source, err := c.get("domains")
+	if err != nil {
+		return nil, NewUnexpectedError(err)
+	}
+	domains, err := readDomains(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []Domain
+	for _, domain := range domains {
+		domain.controller = c
+		result = append(result, domain)
+	}
+	return result, nil
+}
+
+// CreateDomainArgs is an argument struct for passing parameters to the
+// Controller.CreateDomain method.
+type CreateDomainArgs struct {
+	// Name is required.
+	Name string
+	// Authoritative defaults to true if not specified.
+	Authoritative *bool
+	// TTL is the default TTL in seconds of the records in the domain. If
+	// not specified, the global default TTL is used.
+	TTL int
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *CreateDomainArgs) Validate() error {
+	if a.Name == "" {
+		return errors.NotValidf("missing Name")
+	}
+	if a.TTL < 0 {
+		return errors.NotValidf("negative TTL")
+	}
+	return nil
+}
+
+// CreateDomain implements Controller.
+func (c *controller) CreateDomain(args CreateDomainArgs) (Domain, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("name", args.Name)
+	if args.Authoritative != nil {
+		params.Values.Add("authoritative", fmt.Sprint(*args.Authoritative))
+	}
+	params.MaybeAddInt("ttl", args.TTL)
+	source, err := c.post("domains", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	domain, err := readDomain(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	domain.controller = c
+	return domain, nil
+}
+
+// DNSResourcesArgs is an argument struct for selecting DNSResources.
+// Only the resources that match the specified criteria are returned.
+type DNSResourcesArgs struct {
+	Domain string
+	Name   string
+	// Type restricts the resources to those with records of the type.
+	Type DNSRecordType
+}
+
+// DNSResources implements Controller.
+func (c *controller) DNSResources(args DNSResourcesArgs) ([]DNSResource, error) {
+	params := NewURLParams()
+	params.MaybeAdd("domain", args.Domain)
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("rrtype", string(args.Type))
+	source, err := c.getQuery("dnsresources", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	resources, err := readDNSResources(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []DNSResource
+	for _, r := range resources {
+		r.controller = c
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// CreateDNSResourceArgs is an argument struct for passing parameters to
+// the Controller.CreateDNSResource method.
+type CreateDNSResourceArgs struct {
+	// Either the FQDN, or both the Name and the Domain, must be specified.
+	FQDN   string
+	Name   string
+	Domain string
+
+	// IPAddresses are required. MAAS creates an A record for each IPv4
+	// address and an AAAA record for each IPv6 address.
+	IPAddresses []string
+
+	// AddressTTL is the TTL in seconds of the address records. If not
+	// specified, the TTL of the domain is used.
+	AddressTTL int
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *CreateDNSResourceArgs) Validate() error {
+	if a.FQDN == "" && (a.Name == "" || a.Domain == "") {
+		return errors.NotValidf("missing FQDN, or Name and Domain")
+	}
+	if len(a.IPAddresses) == 0 {
+		return errors.NotValidf("missing IPAddresses")
+	}
+	if a.AddressTTL < 0 {
+		return errors.NotValidf("negative AddressTTL")
+	}
+	return nil
+}
+
+// CreateDNSResource implements Controller.
+func (c *controller) CreateDNSResource(args CreateDNSResourceArgs) (DNSResource, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("fqdn", args.FQDN)
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("domain", args.Domain)
+	params.Values.Add("ip_addresses", strings.Join(args.IPAddresses, " "))
+	params.MaybeAddInt("address_ttl", args.AddressTTL)
+	source, err := c.post("dnsresources", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	resource, err := readDNSResource(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resource.controller = c
+	return resource, nil
+}
+
+// DNSResourceRecordsArgs is an argument struct for selecting
+// DNSResourceRecords. Only the records that match the specified criteria
+// are returned.
+type DNSResourceRecordsArgs struct {
+	Domain string
+	Name   string
+	Type   DNSRecordType
+}
+
+// DNSResourceRecords implements Controller.
+func (c *controller) DNSResourceRecords(args DNSResourceRecordsArgs) ([]DNSResourceRecord, error) {
+	params := NewURLParams()
+	params.MaybeAdd("domain", args.Domain)
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("rrtype", string(args.Type))
+	source, err := c.getQuery("dnsresourcerecords", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
 	}
-	zones, err := readZones(c.apiVersion, source)
+	records, err := readDNSResourceRecords(c.apiVersion, source)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	var result []Zone
-	for _, z := range zones {
-		result = append(result, z)
+	var result []DNSResourceRecord
+	for _, r := range records {
+		r.controller = c
+		result = append(result, r)
 	}
 	return result, nil
 }
 
-// Pools implements Controller.
-func (c *controller) Pools() ([]Pool, error) {
-	var result []Pool
-
-	source, err := c.get("pools")
-	if err != nil {
-		return nil, NewUnexpectedError(err)
-	}
+// CreateDNSResourceRecordArgs is an argument struct for passing parameters
+// to the Controller.CreateDNSResourceRecord method.
+type CreateDNSResourceRecordArgs struct {
+	// Either the FQDN, or both the Name and the Domain, must be specified.
+	FQDN   string
+	Name   string
+	Domain string
+
+	// Type and Data are required. A and AAAA records cannot be created
+	// this way, they are managed through the IP addresses of a
+	// DNSResource.
+	Type DNSRecordType
+	Data string
+
+	// TTL is the TTL of the record in seconds. If not specified, the TTL
+	// of the domain is used.
+	TTL int
+}
 
-	pools, err := readPools(c.apiVersion, source)
-	if err != nil {
-		return nil, errors.Trace(err)
+// Validate ensures the arguments are acceptable.
+func (a *CreateDNSResourceRecordArgs) Validate() error {
+	if a.FQDN == "" && (a.Name == "" || a.Domain == "") {
+		return errors.NotValidf("missing FQDN, or Name and Domain")
 	}
-
-	for _, p := range pools {
-		result = append(result, p)
+	if err := validateDNSRecord(a.Type, a.Data, a.TTL); err != nil {
+		return errors.Trace(err)
 	}
-	return result, nil
+	return nil
 }
 
-// Domains implements Controller
-func (c *controller) Domains() ([]Domain, error) {
-	source, err := c.get("domains")
+// CreateDNSResourceRecord implements Controller.
+func (c *controller) CreateDNSResourceRecord(args CreateDNSResourceRecordArgs) (DNSResourceRecord, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("fqdn", args.FQDN)
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("domain", args.Domain)
+	params.Values.Add("rrtype", string(args.Type))
+	params.Values.Add("rrdata", args.Data)
+	params.MaybeAddInt("ttl", args.TTL)
+	source, err := c.post("dnsresourcerecords", "", params.Values)
 	if err != nil {
-		return nil, NewUnexpectedError(err)
+		return nil, translateServerError(err)
 	}
-	domains, err := readDomains(c.apiVersion, source)
+
+	record, err := readDNSResourceRecord(c.apiVersion, source)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	var result []Domain
-	for _, domain := range domains {
-		result = append(result, domain)
-	}
-	return result, nil
+	record.controller = c
+	return record, nil
 }
 
 // DevicesArgs is a argument struct for selecting Devices.
@@ -396,7 +1701,8 @@ func ownerDataMatches(ownerData, filter map[string]string) bool {
 // to be satisfied to allocate a machine.
 type StorageSpec struct {
 	// Label is optional and an arbitrary string. Labels need to be unique
-	// across the StorageSpec elements specified in the AllocateMachineArgs.
+	// across the StorageSpec elements specified in the AllocateMachineArgs
+	// or ComposeArgs.
 	Label string
 	// Size is required and refers to the required minimum size in GB.
 	Size int
@@ -434,7 +1740,8 @@ func (s *StorageSpec) String() string {
 // InterfaceSpec represents one element of network related constraints.
 type InterfaceSpec struct {
 	// Label is required and an arbitrary string. Labels need to be unique
-	// across the InterfaceSpec elements specified in the AllocateMachineArgs.
+	// across the InterfaceSpec elements specified in the AllocateMachineArgs
+	// or ComposeArgs.
 	// The label is returned in the ConstraintMatches response from
 	// AllocateMachine.
 	Label string
@@ -501,47 +1808,77 @@ type AllocateMachineArgs struct {
 // are unique, and that the required specifications are valid. It
 // also makes sure that any pools specified exist.
 func (a *AllocateMachineArgs) Validate() error {
-	storageLabels := set.NewStrings()
-	for _, spec := range a.Storage {
+	if err := validateStorageSpecs(a.Storage); err != nil {
+		return errors.Trace(err)
+	}
+	if err := validateInterfaceSpecs(a.Interfaces); err != nil {
+		return errors.Trace(err)
+	}
+	for _, v := range a.NotSpace {
+		if v == "" {
+			return errors.NotValidf("empty NotSpace constraint")
+		}
+	}
+	return nil
+}
+
+// validateStorageSpecs validates each of the specs, and makes sure that
+// their labels are unique.
+func validateStorageSpecs(specs []StorageSpec) error {
+	labels := set.NewStrings()
+	for _, spec := range specs {
 		if err := spec.Validate(); err != nil {
 			return errors.Annotate(err, "Storage")
 		}
 		if spec.Label != "" {
-			if storageLabels.Contains(spec.Label) {
+			if labels.Contains(spec.Label) {
 				return errors.NotValidf("reusing storage label %q", spec.Label)
 			}
-			storageLabels.Add(spec.Label)
+			labels.Add(spec.Label)
 		}
 	}
-	interfaceLabels := set.NewStrings()
-	for _, spec := range a.Interfaces {
+	return nil
+}
+
+// validateInterfaceSpecs validates each of the specs, and makes sure that
+// their labels are unique.
+func validateInterfaceSpecs(specs []InterfaceSpec) error {
+	labels := set.NewStrings()
+	for _, spec := range specs {
 		if err := spec.Validate(); err != nil {
 			return errors.Annotate(err, "Interfaces")
 		}
-		if interfaceLabels.Contains(spec.Label) {
+		if labels.Contains(spec.Label) {
 			return errors.NotValidf("reusing interface label %q", spec.Label)
 		}
-		interfaceLabels.Add(spec.Label)
-	}
-	for _, v := range a.NotSpace {
-		if v == "" {
-			return errors.NotValidf("empty NotSpace constraint")
-		}
+		labels.Add(spec.Label)
 	}
 	return nil
 }
 
 func (a *AllocateMachineArgs) storage() string {
+	return storageSpecsString(a.Storage)
+}
+
+func (a *AllocateMachineArgs) interfaces() string {
+	return interfaceSpecsString(a.Interfaces)
+}
+
+// storageSpecsString returns the storage constraint that MAAS accepts when
+// allocating and composing machines.
+func storageSpecsString(specs []StorageSpec) string {
 	var values []string
-	for _, spec := range a.Storage {
+	for _, spec := range specs {
 		values = append(values, spec.String())
 	}
 	return strings.Join(values, ",")
 }
 
-func (a *AllocateMachineArgs) interfaces() string {
+// interfaceSpecsString returns the interfaces constraint that MAAS accepts
+// when allocating and composing machines.
+func interfaceSpecsString(specs []InterfaceSpec) string {
 	var values []string
-	for _, spec := range a.Interfaces {
+	for _, spec := range specs {
 		values = append(values, spec.String())
 	}
 	return strings.Join(values, ";")
@@ -654,6 +1991,86 @@ func (c *controller) ReleaseMachines(args ReleaseMachinesArgs) error {
 	return nil
 }
 
+// PowerParameters holds the configuration for the power driver of a
+// machine. The keys depend on the power type, for example "power_address"
+// and "power_user" for IPMI.
+type PowerParameters map[string]string
+
+// addTo adds each of the parameters to the params using the
+// power_parameters_ prefix that MAAS expects. Empty values are added too,
+// as they clear the parameter.
+func (p PowerParameters) addTo(params *URLParams) {
+	for key, value := range p {
+		params.Values.Add("power_parameters_"+key, value)
+	}
+}
+
+// CreateMachineArgs is an argument struct for passing information into
+// CreateMachine. Architecture and at least one MAC address are required.
+type CreateMachineArgs struct {
+	Hostname     string
+	Architecture string
+	MACAddresses []string
+	Domain       string
+	Zone         string
+	Pool         string
+	Description  string
+
+	// PowerType is the name of the power driver, such as "ipmi" or "virsh".
+	PowerType       string
+	PowerParameters PowerParameters
+}
+
+// Validate ensures that the architecture and MAC addresses are specified.
+func (a *CreateMachineArgs) Validate() error {
+	if a.Architecture == "" {
+		return errors.NotValidf("missing Architecture")
+	}
+	if len(a.MACAddresses) == 0 {
+		return errors.NotValidf("missing MACAddresses")
+	}
+	if len(a.PowerParameters) > 0 && a.PowerType == "" {
+		return errors.NotValidf("PowerParameters without PowerType")
+	}
+	return nil
+}
+
+// CreateMachine implements Controller.
+func (c *controller) CreateMachine(args CreateMachineArgs) (Machine, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("hostname", args.Hostname)
+	params.MaybeAdd("architecture", args.Architecture)
+	params.MaybeAddMany("mac_addresses", args.MACAddresses)
+	params.MaybeAdd("domain", args.Domain)
+	params.MaybeAdd("zone", args.Zone)
+	params.MaybeAdd("pool", args.Pool)
+	params.MaybeAdd("description", args.Description)
+	params.MaybeAdd("power_type", args.PowerType)
+	args.PowerParameters.addTo(params)
+	result, err := c.post("machines", "", params.Values)
+	if err != nil {
+		if svrErr, ok := errors.Cause(err).(ServerError); ok {
+			switch svrErr.StatusCode {
+			case http.StatusBadRequest:
+				return nil, errors.Wrap(err, NewBadRequestError(svrErr.BodyMessage))
+			case http.StatusForbidden:
+				return nil, errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
+			}
+		}
+		return nil, NewUnexpectedError(err)
+	}
+
+	machine, err := readMachine(c.apiVersion, result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine.controller = c
+	return machine, nil
+}
+
 // Files implements Controller.
 func (c *controller) Files(prefix string) ([]File, error) {
 	params := NewURLParams()
@@ -759,16 +2176,266 @@ func (c *controller) AddFile(args AddFileArgs) error {
 	return nil
 }
 
+// Users implements Controller.
+func (c *controller) Users() ([]User, error) {
+	source, err := c.get("users")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	users, err := readUsers(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []User
+	for _, u := range users {
+		result = append(result, u)
+	}
+	return result, nil
+}
+
+// WhoAmI implements Controller.
+func (c *controller) WhoAmI() (User, error) {
+	source, err := c.whoAmI()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	user, err := readUser(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return user, nil
+}
+
+// CreateUserArgs is an argument struct for passing parameters to the
+// Controller.CreateUser method.
+type CreateUserArgs struct {
+	Username string
+	Email    string
+	// Password is required unless MAAS uses external authentication.
+	Password    string
+	IsSuperuser bool
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *CreateUserArgs) Validate() error {
+	if a.Username == "" {
+		return errors.NotValidf("missing Username")
+	}
+	if a.Email == "" {
+		return errors.NotValidf("missing Email")
+	}
+	return nil
+}
+
+// CreateUser implements Controller.
+func (c *controller) CreateUser(args CreateUserArgs) (User, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("username", args.Username)
+	params.Values.Add("email", args.Email)
+	params.MaybeAdd("password", args.Password)
+	params.Values.Add("is_superuser", fmt.Sprint(args.IsSuperuser))
+	source, err := c.post("users", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	user, err := readUser(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return user, nil
+}
+
+// DeleteUser implements Controller.
+func (c *controller) DeleteUser(username string) error {
+	if username == "" {
+		return errors.NotValidf("missing username")
+	}
+	if err := c.delete("users/" + username); err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// SSHKeys implements Controller.
+func (c *controller) SSHKeys() ([]SSHKey, error) {
+	source, err := c.get("account/prefs/sshkeys")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	keys, err := readSSHKeys(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []SSHKey
+	for _, k := range keys {
+		result = append(result, k)
+	}
+	return result, nil
+}
+
+// AddSSHKey implements Controller.
+func (c *controller) AddSSHKey(key string) (SSHKey, error) {
+	if key == "" {
+		return nil, errors.NotValidf("missing key")
+	}
+	params := url.Values{"key": {key}}
+	source, err := c.post("account/prefs/sshkeys", "new", params)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	sshKey, err := readSSHKey(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return sshKey, nil
+}
+
+// ImportSSHKeys implements Controller.
+func (c *controller) ImportSSHKeys(keySource string) ([]SSHKey, error) {
+	parts := strings.SplitN(keySource, ":", 2)
+	if len(parts) != 2 || (parts[0] != "lp" && parts[0] != "gh") || parts[1] == "" {
+		return nil, errors.NotValidf("key source %q", keySource)
+	}
+	params := url.Values{"keysource": {keySource}}
+	source, err := c.post("account/prefs/sshkeys", "import", params)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	keys, err := readSSHKeys(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []SSHKey
+	for _, k := range keys {
+		result = append(result, k)
+	}
+	return result, nil
+}
+
+// DeleteSSHKey implements Controller.
+func (c *controller) DeleteSSHKey(id int) error {
+	if err := c.delete(fmt.Sprintf("account/prefs/sshkeys/%d", id)); err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// SSLKeys implements Controller.
+func (c *controller) SSLKeys() ([]SSLKey, error) {
+	source, err := c.get("account/prefs/sslkeys")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	keys, err := readSSLKeys(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []SSLKey
+	for _, k := range keys {
+		result = append(result, k)
+	}
+	return result, nil
+}
+
+// AddSSLKey implements Controller.
+func (c *controller) AddSSLKey(key string) (SSLKey, error) {
+	if key == "" {
+		return nil, errors.NotValidf("missing key")
+	}
+	params := url.Values{"key": {key}}
+	source, err := c.post("account/prefs/sslkeys", "new", params)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	sslKey, err := readSSLKey(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return sslKey, nil
+}
+
+// DeleteSSLKey implements Controller.
+func (c *controller) DeleteSSLKey(id int) error {
+	if err := c.delete(fmt.Sprintf("account/prefs/sslkeys/%d", id)); err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// Config implements Controller.
+func (c *controller) Config(name string) (interface{}, error) {
+	if name == "" {
+		return nil, errors.NotValidf("missing name")
+	}
+	params := url.Values{"name": {name}}
+	source, err := c._get("maas", "get_config", params)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	return source, nil
+}
+
+// SetConfig implements Controller.
+func (c *controller) SetConfig(name string, value interface{}) error {
+	if name == "" {
+		return errors.NotValidf("missing name")
+	}
+	valueString, err := configValueString(value)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	params := url.Values{
+		"name":  {name},
+		"value": {valueString},
+	}
+	if _, err := c._postRaw("maas", "set_config", params, nil); err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// MAASConfig implements Controller.
+func (c *controller) MAASConfig() (MAASConfig, error) {
+	values := make(map[string]interface{})
+	for _, name := range maasConfigNames {
+		value, err := c.Config(name)
+		if err != nil {
+			return MAASConfig{}, errors.Annotatef(err, "getting %s", name)
+		}
+		values[name] = value
+	}
+	return readMAASConfig(values)
+}
+
 func (c *controller) checkCreds() error {
-	if _, err := c.getOp("users", "whoami"); err != nil {
+	source, err := c.whoAmI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	// Only the status of the response decides whether the credentials are
+	// good, the user is logged when the response is one.
+	if user, err := readUser(c.apiVersion, source); err == nil {
+		logger.Debugf("authenticated as %q", user.Username())
+	}
+	return nil
+}
+
+// whoAmI returns the response from the users whoami operation, translating
+// a 401 into a PermissionError.
+func (c *controller) whoAmI() (interface{}, error) {
+	source, err := c.getOp("users", "whoami")
+	if err != nil {
 		if svrErr, ok := errors.Cause(err).(ServerError); ok {
 			if svrErr.StatusCode == http.StatusUnauthorized {
-				return errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
+				return nil, errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
 			}
 		}
-		return NewUnexpectedError(err)
+		return nil, NewUnexpectedError(err)
 	}
-	return nil
+	return source, nil
 }
 
 func (c *controller) put(path string, params url.Values) (interface{}, error) {
@@ -791,6 +2458,20 @@ func (c *controller) put(path string, params url.Values) (interface{}, error) {
 	return parsed, nil
 }
 
+func (c *controller) putContent(path string, content []byte) error {
+	path = EnsureTrailingSlash(path)
+	requestID := nextRequestID()
+	logger.Tracef("request %x: PUT %s%s, %d bytes", requestID, c.client.APIURL, path, len(content))
+	bytes, err := c.client.PutContent(&url.URL{Path: path}, content)
+	if err != nil {
+		logger.Tracef("response %x: error: %q", requestID, err.Error())
+		logger.Tracef("error detail: %#v", err)
+		return errors.Trace(err)
+	}
+	logger.Tracef("response %x: %s", requestID, string(bytes))
+	return nil
+}
+
 func (c *controller) post(path, op string, params url.Values) (interface{}, error) {
 	bytes, err := c._postRaw(path, op, params, nil)
 	if err != nil {
@@ -1076,8 +2757,32 @@ func (c *controller) Tags() ([]Tag, error) {
 
 	result := make([]Tag, len(tags))
 	for i, tag := range tags {
+		tag.controller = c
 		result[i] = tag
 	}
 
 	return result, nil
 }
+
+// CreateTag implements Controller.
+func (c *controller) CreateTag(name, comment, definition, kernelOpts string) (Tag, error) {
+	if name == "" {
+		return nil, errors.NotValidf("missing name")
+	}
+	params := NewURLParams()
+	params.Values.Add("name", name)
+	params.MaybeAdd("comment", comment)
+	params.MaybeAdd("definition", definition)
+	params.MaybeAdd("kernel_opts", kernelOpts)
+	source, err := c.post("tags", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	tag, err := readTag(c.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	tag.controller = c
+	return tag, nil
+}
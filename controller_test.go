@@ -5,9 +5,13 @@ package gomaasapi
 
 import (
 	"bytes"
+	"encoding/json"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/juju/collections/set"
 	"github.com/juju/errors"
@@ -50,6 +54,8 @@ func (s *controllerSuite) SetUpTest(c *gc.C) {
 	server.AddGetResponse("/api/2.0/machines/?hostname=untasted-markita", http.StatusOK, "["+machineResponse+"]")
 	server.AddGetResponse("/api/2.0/spaces/", http.StatusOK, spacesResponse)
 	server.AddGetResponse("/api/2.0/static-routes/", http.StatusOK, staticRoutesResponse)
+	server.AddGetResponse("/api/2.0/subnets/", http.StatusOK, subnetResponse)
+	server.AddGetResponse("/api/2.0/ipranges/", http.StatusOK, ipRangesResponse)
 	server.AddGetResponse("/api/2.0/users/?op=whoami", http.StatusOK, `"captain awesome"`)
 	server.AddGetResponse("/api/2.0/version/", http.StatusOK, versionResponse)
 	server.AddGetResponse("/api/2.0/zones/", http.StatusOK, zoneResponse)
@@ -246,97 +252,1374 @@ func (s *controllerSuite) TestBootResources(c *gc.C) {
 	c.Assert(resources, gc.HasLen, 5)
 }
 
+func (s *controllerSuite) TestGetBootResource(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/boot-resources/7/", http.StatusOK, bootResourceResponse)
+	controller := s.getController(c)
+	resource, err := controller.GetBootResource(7)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resource.Name(), gc.Equals, "ubuntu/jammy")
+	c.Check(resource.Sets(), gc.HasLen, 2)
+}
+
+func (s *controllerSuite) TestGetBootResourceMissing(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.GetBootResource(42)
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *controllerSuite) TestImportBootResources(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=import", http.StatusOK, "Import of boot resources started")
+	controller := s.getController(c)
+	err := controller.ImportBootResources()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *controllerSuite) TestImportBootResourcesForbidden(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=import", http.StatusForbidden, "admins only")
+	controller := s.getController(c)
+	err := controller.ImportBootResources()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+func (s *controllerSuite) TestStopImport(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=stop_import", http.StatusOK, "Import of boot resources is being stopped")
+	controller := s.getController(c)
+	err := controller.StopImport()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *controllerSuite) TestIsImporting(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/boot-resources/?op=is_importing", http.StatusOK, "true")
+	controller := s.getController(c)
+	importing, err := controller.IsImporting()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(importing, jc.IsTrue)
+}
+
+func (s *controllerSuite) TestIsImportingBadResponse(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/boot-resources/?op=is_importing", http.StatusOK, `"maybe"`)
+	controller := s.getController(c)
+	_, err := controller.IsImporting()
+	c.Assert(err, jc.Satisfies, IsDeserializationError)
+}
+
+func (s *controllerSuite) TestUploadBootResourceValidates(c *gc.C) {
+	controller := s.getController(c)
+	content := strings.NewReader("0123456789")
+	for i, test := range []struct {
+		args    UploadBootResourceArgs
+		message string
+	}{{
+		args:    UploadBootResourceArgs{Architecture: "amd64/generic", Content: content},
+		message: "missing Name not valid",
+	}, {
+		args:    UploadBootResourceArgs{Name: "custom/golden", Content: content},
+		message: "missing Architecture not valid",
+	}, {
+		args:    UploadBootResourceArgs{Name: "custom/golden", Architecture: "amd64/generic"},
+		message: "missing Content not valid",
+	}, {
+		args:    UploadBootResourceArgs{Name: "custom/golden", Architecture: "amd64/generic", Content: content, ChunkSize: -1},
+		message: "negative ChunkSize not valid",
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.UploadBootResource(test.args)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err.Error(), gc.Equals, test.message)
+	}
+}
+
+const bootResourceUploadURI = "/MAAS/api/2.0/boot-resources/9/upload/12/"
+
+// uploadBootResource uploads ten bytes of content in chunks of four bytes,
+// and returns the chunks that were sent along with the progress reported.
+func (s *controllerSuite) uploadBootResource(c *gc.C, content io.Reader) (BootResource, []string, []int64, error) {
+	controller := s.getController(c)
+	s.server.ResetRequests()
+	var progress []int64
+	resource, err := controller.UploadBootResource(UploadBootResourceArgs{
+		Name:         "custom/golden",
+		Title:        "Golden",
+		Architecture: "amd64/generic",
+		Content:      content,
+		ChunkSize:    4,
+		Progress: func(uploaded, total int64) {
+			c.Check(total, gc.Equals, int64(10))
+			progress = append(progress, uploaded)
+		},
+	})
+	var chunks []string
+	for _, request := range s.server.LastNRequests(s.server.RequestCount()) {
+		if request.Method == "PUT" {
+			chunk, err := io.ReadAll(request.Body)
+			c.Assert(err, jc.ErrorIsNil)
+			chunks = append(chunks, string(chunk))
+		}
+	}
+	return resource, chunks, progress, err
+}
+
+func (s *controllerSuite) TestUploadBootResource(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	for i := 0; i < 3; i++ {
+		s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	}
+	s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(1))
+
+	resource, chunks, progress, err := s.uploadBootResource(c, strings.NewReader("0123456789"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resource.ID(), gc.Equals, 9)
+	c.Check(chunks, jc.DeepEquals, []string{"0123", "4567", "89"})
+	c.Check(progress, jc.DeepEquals, []int64{4, 8, 10})
+
+	create := s.server.LastNRequests(s.server.RequestCount())[0]
+	c.Check(create.PostForm, gc.HasLen, 6)
+	c.Check(create.PostForm.Get("name"), gc.Equals, "custom/golden")
+	c.Check(create.PostForm.Get("title"), gc.Equals, "Golden")
+	c.Check(create.PostForm.Get("architecture"), gc.Equals, "amd64/generic")
+	c.Check(create.PostForm.Get("filetype"), gc.Equals, "tgz")
+	c.Check(create.PostForm.Get("sha256"), gc.Equals, sha256String("0123456789"))
+	c.Check(create.PostForm.Get("size"), gc.Equals, "10")
+}
+
+func (s *controllerSuite) TestUploadBootResourceFromStream(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	for i := 0; i < 3; i++ {
+		s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	}
+	s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(1))
+
+	// A MultiReader can't seek, so the content is spooled before uploading.
+	_, chunks, _, err := s.uploadBootResource(c, io.MultiReader(strings.NewReader("0123456789")))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(chunks, jc.DeepEquals, []string{"0123", "4567", "89"})
+}
+
+func (s *controllerSuite) TestUploadBootResourceRetriesChunk(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusInternalServerError, "boom")
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	// MAAS only has the first chunk.
+	s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(0.4))
+	s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(1))
+
+	_, chunks, progress, err := s.uploadBootResource(c, strings.NewReader("0123456789"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(chunks, jc.DeepEquals, []string{"0123", "4567", "4567", "89"})
+	c.Check(progress, jc.DeepEquals, []int64{4, 4, 8, 10})
+}
+
+func (s *controllerSuite) TestUploadBootResourceResumesFromServerProgress(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusBadGateway, "timed out")
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	// MAAS saved the second chunk even though the request failed.
+	s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(0.8))
+	s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(1))
+
+	_, chunks, progress, err := s.uploadBootResource(c, strings.NewReader("0123456789"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(chunks, jc.DeepEquals, []string{"0123", "4567", "89"})
+	c.Check(progress, jc.DeepEquals, []int64{4, 8, 10})
+}
+
+func (s *controllerSuite) TestUploadBootResourceRestartsWhenProgressIsInexact(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusBadGateway, "timed out")
+	// 0.55 of 10 bytes isn't a number of bytes to resume from, so the upload
+	// starts again.
+	s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(0.55))
+	s.server.AddDeleteResponse("/api/2.0/boot-resources/9/", http.StatusNoContent, "")
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	for i := 0; i < 3; i++ {
+		s.server.AddPutResponse(bootResourceUploadURI, http.StatusOK, "OK")
+	}
+	s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(1))
+
+	_, chunks, progress, err := s.uploadBootResource(c, strings.NewReader("0123456789"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(chunks, jc.DeepEquals, []string{"0123", "4567", "0123", "4567", "89"})
+	c.Check(progress, jc.DeepEquals, []int64{4, 0, 4, 8, 10})
+}
+
+func (s *controllerSuite) TestUploadBootResourceGivesUp(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	for i := 0; i <= bootResourceUploadRetries; i++ {
+		s.server.AddPutResponse(bootResourceUploadURI, http.StatusInternalServerError, "boom")
+		s.server.AddGetResponse("/api/2.0/boot-resources/9/", http.StatusOK, bootResourceUploadResponse(0))
+	}
+
+	_, chunks, _, err := s.uploadBootResource(c, strings.NewReader("0123456789"))
+	c.Assert(err, jc.Satisfies, IsUnexpectedError)
+	c.Check(err, gc.ErrorMatches, "uploading boot resource 9: giving up after 4 failures: .*boom.*")
+	c.Check(chunks, gc.HasLen, bootResourceUploadRetries+1)
+}
+
+func (s *controllerSuite) TestUploadBootResourceBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	s.server.AddPutResponse(bootResourceUploadURI, http.StatusBadRequest, "too much content")
+
+	_, chunks, _, err := s.uploadBootResource(c, strings.NewReader("0123456789"))
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Check(chunks, gc.HasLen, 1)
+}
+
+func (s *controllerSuite) TestUploadBootResourceSizeMismatch(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-resources/?op=", http.StatusCreated, bootResourceUploadResponse(0))
+	_, _, _, err := s.uploadBootResource(c, strings.NewReader("01234"))
+	c.Assert(err, gc.ErrorMatches, "boot resource 9 expects 10 bytes, not 5")
+}
+
+func (s *controllerSuite) TestBootSources(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/boot-sources/", http.StatusOK, bootSourcesResponse)
+	controller := s.getController(c)
+	bootSources, err := controller.BootSources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bootSources, gc.HasLen, 2)
+	c.Check(bootSources[0].(*bootSource).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestCreateBootSourceKeyringFilename(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-sources/?op=", http.StatusOK, bootSourceResponse)
+	controller := s.getController(c)
+	result, err := controller.CreateBootSource(CreateBootSourceArgs{
+		URL:             "http://images.maas.io/ephemeral-v3/stable/",
+		KeyringFilename: "/usr/share/keyrings/ubuntu-cloudimage-keyring.gpg",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ID(), gc.Equals, 1)
+	c.Check(result.(*bootSource).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("url"), gc.Equals, "http://images.maas.io/ephemeral-v3/stable/")
+	c.Check(form.Get("keyring_filename"), gc.Equals, "/usr/share/keyrings/ubuntu-cloudimage-keyring.gpg")
+}
+
+func (s *controllerSuite) TestCreateBootSourceKeyringData(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/boot-sources/?op=", http.StatusOK, bootSourceResponse)
+	controller := s.getController(c)
+	_, err := controller.CreateBootSource(CreateBootSourceArgs{
+		URL:         "http://mirror.example.com/images/",
+		KeyringData: []byte("keyring"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	request := s.server.LastRequest()
+	c.Check(request.Form.Get("url"), gc.Equals, "http://mirror.example.com/images/")
+	fileHeader := request.MultipartForm.File["keyring_data"][0]
+	f, err := fileHeader.Open()
+	c.Assert(err, jc.ErrorIsNil)
+	bytes, err := ioutil.ReadAll(f)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(bytes), gc.Equals, "keyring")
+}
+
+func (s *controllerSuite) TestCreateBootSourceValidates(c *gc.C) {
+	controller := s.getController(c)
+	for i, test := range []struct {
+		args    CreateBootSourceArgs
+		message string
+	}{{
+		args:    CreateBootSourceArgs{KeyringFilename: "keyring.gpg"},
+		message: "missing URL not valid",
+	}, {
+		args:    CreateBootSourceArgs{URL: "http://mirror.example.com/images/"},
+		message: "missing KeyringFilename or KeyringData not valid",
+	}, {
+		args: CreateBootSourceArgs{
+			URL:             "http://mirror.example.com/images/",
+			KeyringFilename: "keyring.gpg",
+			KeyringData:     []byte("keyring"),
+		},
+		message: "both KeyringFilename and KeyringData not valid",
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.CreateBootSource(test.args)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err.Error(), gc.Equals, test.message)
+	}
+}
+
+func (s *controllerSuite) TestRackControllers(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/rackcontrollers/", http.StatusOK, rackControllersResponse)
+	controller := s.getController(c)
+	racks, err := controller.RackControllers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(racks, gc.HasLen, 2)
+	c.Check(racks[1].SystemID(), gc.Equals, "8cwnrp")
+	c.Check(racks[1].(*controllerNode).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestRackControllersForbidden(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/rackcontrollers/", http.StatusForbidden, "admins only")
+	controller := s.getController(c)
+	_, err := controller.RackControllers()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+func (s *controllerSuite) TestRegionControllers(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/regioncontrollers/", http.StatusOK, regionControllersResponse)
+	controller := s.getController(c)
+	regions, err := controller.RegionControllers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(regions, gc.HasLen, 2)
+	c.Check(regions[1].IsRackController(), jc.IsFalse)
+	c.Check(regions[1].IsRegionController(), jc.IsTrue)
+	c.Check(regions[1].(*controllerNode).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestResolveRack(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/rackcontrollers/4y3h7n/", http.StatusOK, rackControllerResponse)
+	controller := s.getController(c)
+	fabrics, err := controller.Fabrics()
+	c.Assert(err, jc.ErrorIsNil)
+	primary := fabrics[0].VLANs()[0].PrimaryRack()
+	rack, err := controller.ResolveRack(primary)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(rack.Hostname(), gc.Equals, "region")
+	c.Check(rack.(*controllerNode).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestResolveRackMissing(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.ResolveRack("8cwnrp")
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *controllerSuite) TestResolveRackValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.ResolveRack("")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing systemID not valid")
+}
+
+func (s *controllerSuite) TestConfig(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/maas/?name=enable_http_proxy&op=get_config", http.StatusOK, "true")
+	s.server.AddGetResponse("/api/2.0/maas/?name=default_distro_series&op=get_config", http.StatusOK, `"jammy"`)
+	s.server.AddGetResponse("/api/2.0/maas/?name=http_proxy&op=get_config", http.StatusOK, "null")
+	controller := s.getController(c)
+	value, err := controller.Config("enable_http_proxy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(value, gc.Equals, true)
+	value, err = controller.Config("default_distro_series")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(value, gc.Equals, "jammy")
+	value, err = controller.Config("http_proxy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(value, gc.IsNil)
+}
+
+func (s *controllerSuite) TestConfigUnknown(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/maas/?name=wat&op=get_config", http.StatusBadRequest, "wat is not a valid config setting")
+	controller := s.getController(c)
+	_, err := controller.Config("wat")
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+}
+
+func (s *controllerSuite) TestSetConfig(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/maas/?op=set_config", http.StatusOK, "OK")
+	controller := s.getController(c)
+	err := controller.SetConfig("upstream_dns", []string{"8.8.8.8", "8.8.4.4"})
+	c.Assert(err, jc.ErrorIsNil)
+	form := s.server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{
+		"name":  {"upstream_dns"},
+		"value": {"8.8.8.8 8.8.4.4"},
+	})
+}
+
+func (s *controllerSuite) TestSetConfigValidates(c *gc.C) {
+	controller := s.getController(c)
+	err := controller.SetConfig("", "jammy")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = controller.SetConfig("default_distro_series", struct{}{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *controllerSuite) TestMAASConfig(c *gc.C) {
+	var values map[string]interface{}
+	err := json.Unmarshal([]byte(maasConfigResponse), &values)
+	c.Assert(err, jc.ErrorIsNil)
+	for name, value := range values {
+		encoded, err := json.Marshal(value)
+		c.Assert(err, jc.ErrorIsNil)
+		s.server.AddGetResponse("/api/2.0/maas/?name="+name+"&op=get_config", http.StatusOK, string(encoded))
+	}
+	controller := s.getController(c)
+	config, err := controller.MAASConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(config.DefaultDistroSeries, gc.Equals, "jammy")
+	c.Check(config.NTPServers, jc.DeepEquals, []string{"ntp.ubuntu.com", "pool.ntp.org"})
+	c.Check(config.EnableHTTPProxy, jc.IsTrue)
+}
+
+func (s *controllerSuite) TestMAASConfigError(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.MAASConfig()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+	c.Assert(err, gc.ErrorMatches, `(?s)getting maas_name: .*`)
+}
+
+func (s *controllerSuite) TestNewControllerWhoAmIUser(c *gc.C) {
+	server := NewSimpleServer()
+	server.AddGetResponse("/api/2.0/users/?op=whoami", http.StatusOK, whoAmIResponse)
+	server.AddGetResponse("/api/2.0/version/", http.StatusOK, versionResponse)
+	server.Start()
+	defer server.Close()
+	_, err := NewController(ControllerArgs{
+		BaseURL: server.URL,
+		APIKey:  "fake:as:key",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(c.GetTestLog(), jc.Contains, `authenticated as "admin"`)
+}
+
+func (s *controllerSuite) TestWhoAmINotAUser(c *gc.C) {
+	controller := s.getController(c)
+	s.server.AddGetResponse("/api/2.0/users/?op=whoami", http.StatusOK, `"captain awesome"`)
+	_, err := controller.WhoAmI()
+	c.Assert(err, jc.Satisfies, IsDeserializationError)
+}
+
+func (s *controllerSuite) TestUsers(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/users/", http.StatusOK, usersResponse)
+	controller := s.getController(c)
+	users, err := controller.Users()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, gc.HasLen, 2)
+	c.Check(users[1].Username(), gc.Equals, "jane")
+}
+
+func (s *controllerSuite) TestUsersNotAdmin(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/users/", http.StatusForbidden, "admins only")
+	controller := s.getController(c)
+	_, err := controller.Users()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+func (s *controllerSuite) TestWhoAmI(c *gc.C) {
+	controller := s.getController(c)
+	s.server.AddGetResponse("/api/2.0/users/?op=whoami", http.StatusOK, whoAmIResponse)
+	user, err := controller.WhoAmI()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(user.Username(), gc.Equals, "admin")
+	c.Check(user.IsSuperuser(), jc.IsTrue)
+}
+
+func (s *controllerSuite) TestCreateUser(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/users/?op=", http.StatusOK, `{
+        "is_superuser": false,
+        "username": "jane",
+        "email": "jane@example.com",
+        "is_local": true,
+        "resource_uri": "/MAAS/api/2.0/users/jane/"
+    }`)
+	controller := s.getController(c)
+	user, err := controller.CreateUser(CreateUserArgs{
+		Username: "jane",
+		Email:    "jane@example.com",
+		Password: "sekrit",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(user.Username(), gc.Equals, "jane")
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{
+		"username":     {"jane"},
+		"email":        {"jane@example.com"},
+		"password":     {"sekrit"},
+		"is_superuser": {"false"},
+	})
+}
+
+func (s *controllerSuite) TestCreateUserValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreateUser(CreateUserArgs{Username: "jane"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing Email not valid")
+}
+
+func (s *controllerSuite) TestCreateUserExists(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/users/?op=", http.StatusBadRequest, "A user with that username already exists.")
+	controller := s.getController(c)
+	_, err := controller.CreateUser(CreateUserArgs{Username: "jane", Email: "jane@example.com"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+}
+
+func (s *controllerSuite) TestDeleteUser(c *gc.C) {
+	s.server.AddDeleteResponse("/api/2.0/users/jane/", http.StatusNoContent, "")
+	controller := s.getController(c)
+	err := controller.DeleteUser("jane")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *controllerSuite) TestDeleteUserMissing(c *gc.C) {
+	controller := s.getController(c)
+	err := controller.DeleteUser("jane")
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *controllerSuite) TestSSHKeys(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/account/prefs/sshkeys/", http.StatusOK, sshKeysResponse)
+	controller := s.getController(c)
+	keys, err := controller.SSHKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 2)
+	c.Check(keys[1].KeySource(), gc.Equals, "lp:jane")
+}
+
+func (s *controllerSuite) TestAddSSHKey(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/account/prefs/sshkeys/?op=new", http.StatusOK, sshKeyResponse)
+	controller := s.getController(c)
+	key, err := controller.AddSSHKey("ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBEX admin@laptop")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(key.ID(), gc.Equals, 1)
+	form := s.server.LastRequest().PostForm
+	c.Check(form.Get("key"), gc.Equals, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBEX admin@laptop")
+}
+
+func (s *controllerSuite) TestAddSSHKeyInvalid(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/account/prefs/sshkeys/?op=new", http.StatusBadRequest, "Invalid SSH public key.")
+	controller := s.getController(c)
+	_, err := controller.AddSSHKey("not a key")
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+}
+
+func (s *controllerSuite) TestImportSSHKeys(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/account/prefs/sshkeys/?op=import", http.StatusOK, sshKeysResponse)
+	controller := s.getController(c)
+	keys, err := controller.ImportSSHKeys("lp:jane")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 2)
+	form := s.server.LastRequest().PostForm
+	c.Check(form.Get("keysource"), gc.Equals, "lp:jane")
+}
+
+func (s *controllerSuite) TestImportSSHKeysValidates(c *gc.C) {
+	controller := s.getController(c)
+	for _, keySource := range []string{"", "jane", "lp:", "bb:jane"} {
+		_, err := controller.ImportSSHKeys(keySource)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *controllerSuite) TestDeleteSSHKey(c *gc.C) {
+	s.server.AddDeleteResponse("/api/2.0/account/prefs/sshkeys/2/", http.StatusNoContent, "")
+	controller := s.getController(c)
+	err := controller.DeleteSSHKey(2)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *controllerSuite) TestSSLKeys(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/account/prefs/sslkeys/", http.StatusOK, sslKeysResponse)
+	controller := s.getController(c)
+	keys, err := controller.SSLKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 1)
+}
+
+func (s *controllerSuite) TestAddSSLKey(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/account/prefs/sslkeys/?op=new", http.StatusOK, sslKeyResponse)
+	controller := s.getController(c)
+	key, err := controller.AddSSLKey("-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(key.ID(), gc.Equals, 3)
+}
+
+func (s *controllerSuite) TestDeleteSSLKeyMissing(c *gc.C) {
+	controller := s.getController(c)
+	err := controller.DeleteSSLKey(3)
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *controllerSuite) TestVMHosts(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/pods/", http.StatusOK, vmHostsResponse)
+	controller := s.getController(c)
+	hosts, err := controller.VMHosts()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hosts, gc.HasLen, 2)
+	c.Check(hosts[0].Name(), gc.Equals, "lxd-1")
+	c.Check(hosts[0].(*vmHost).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestCreateVMHost(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/pods/?op=", http.StatusOK, vmHostResponse)
+	controller := s.getController(c)
+	host, err := controller.CreateVMHost(CreateVMHostArgs{
+		Type:         "lxd",
+		PowerAddress: "10.0.0.5:8443",
+		Password:     "secret",
+		Project:      "maas",
+		Name:         "lxd-1",
+		Tags:         []string{"pod-console-logging", "fast"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(host.ID(), gc.Equals, 1)
+	c.Check(host.(*vmHost).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{
+		"type":          {"lxd"},
+		"power_address": {"10.0.0.5:8443"},
+		"password":      {"secret"},
+		"project":       {"maas"},
+		"name":          {"lxd-1"},
+		"tags":          {"pod-console-logging,fast"},
+	})
+}
+
+func (s *controllerSuite) TestCreateVMHostValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreateVMHost(CreateVMHostArgs{Type: "virsh"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing PowerAddress not valid")
+}
+
+func (s *controllerSuite) TestEvents(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/events/?op=query", http.StatusOK, eventsResponse)
+	controller := s.getController(c)
+	events, err := controller.Events(EventsArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(events, gc.HasLen, 2)
+	c.Check(events[0].ID(), gc.Equals, 5)
+	c.Check(events[1].ID(), gc.Equals, 4)
+}
+
+func (s *controllerSuite) TestEventsArgs(c *gc.C) {
+	controller := s.getController(c)
+	// This will fail with a 404 due to the test server not having something at
+	// that address, but we don't care, all we want to do is capture the request
+	// and make sure that all the values were set.
+	controller.Events(EventsArgs{
+		Hostnames: []string{"untasted-markita", "lucky-carp"},
+		SystemIDs: []string{"4y3ha6"},
+		Zone:      "foo",
+		Level:     "WARNING",
+		After:     3,
+		Before:    10,
+		Limit:     5,
+	})
+	request := s.server.LastRequest()
+	c.Assert(request.URL.Query(), jc.DeepEquals, url.Values{
+		"op":       {"query"},
+		"hostname": {"untasted-markita", "lucky-carp"},
+		"id":       {"4y3ha6"},
+		"zone":     {"foo"},
+		"level":    {"WARNING"},
+		"after":    {"3"},
+		"before":   {"10"},
+		"limit":    {"5"},
+	})
+}
+
+func (s *controllerSuite) TestEventsValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.Events(EventsArgs{Limit: -1})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "negative Limit not valid")
+}
+
+func (s *controllerSuite) TestEventHistory(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/events/?limit=2&op=query", http.StatusOK, eventsResponse)
+	s.server.AddGetResponse("/MAAS/api/2.0/events/?before=4&limit=2&op=query", http.StatusOK, olderEventsResponse)
+	s.server.AddGetResponse("/MAAS/api/2.0/events/?before=2&limit=2&op=query", http.StatusOK, noEventsResponse)
+	controller := s.getController(c)
+	history := controller.EventHistory(EventsArgs{Limit: 2})
+	var ids []int
+	for history.Next() {
+		for _, event := range history.Events() {
+			ids = append(ids, event.ID())
+		}
+	}
+	c.Assert(history.Err(), jc.ErrorIsNil)
+	c.Assert(ids, jc.DeepEquals, []int{5, 4, 2})
+	c.Assert(history.Next(), jc.IsFalse)
+}
+
+func (s *controllerSuite) TestEventHistoryError(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/events/?limit=2&op=query", http.StatusOK, eventsResponse)
+	controller := s.getController(c)
+	history := controller.EventHistory(EventsArgs{Limit: 2})
+	c.Assert(history.Next(), jc.IsTrue)
+	c.Assert(history.Events(), gc.HasLen, 2)
+	c.Assert(history.Next(), jc.IsFalse)
+	c.Assert(history.Events(), gc.HasLen, 0)
+	c.Assert(history.Err(), jc.Satisfies, IsNoMatchError)
+}
+
+func (s *controllerSuite) TestEventHistoryValidates(c *gc.C) {
+	controller := s.getController(c)
+	history := controller.EventHistory(EventsArgs{After: -1})
+	c.Assert(history.Next(), jc.IsFalse)
+	c.Assert(history.Err(), jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *controllerSuite) TestFollowEvents(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/events/?limit=1&op=query", http.StatusOK, eventsResponse)
+	s.server.AddGetResponse("/api/2.0/events/?after=5&limit=100&op=query", http.StatusOK, newEventsResponse)
+	for i := 0; i < 10; i++ {
+		s.server.AddGetResponse("/api/2.0/events/?after=7&limit=100&op=query", http.StatusOK, noEventsResponse)
+	}
+	controller := s.getController(c)
+	stop := make(chan struct{})
+	events, errs := controller.FollowEvents(EventsArgs{}, time.Millisecond, stop)
+	var ids []int
+	for len(ids) < 2 {
+		select {
+		case event := <-events:
+			ids = append(ids, event.ID())
+		case err := <-errs:
+			c.Fatalf("unexpected error: %v", err)
+		case <-time.After(testing.LongWait):
+			c.Fatalf("timed out waiting for events")
+		}
+	}
+	close(stop)
+	c.Assert(ids, jc.DeepEquals, []int{6, 7})
+	for range events {
+	}
+	c.Assert(<-errs, jc.ErrorIsNil)
+}
+
+func (s *controllerSuite) TestFollowEventsError(c *gc.C) {
+	controller := s.getController(c)
+	stop := make(chan struct{})
+	defer close(stop)
+	events, errs := controller.FollowEvents(EventsArgs{After: 5}, time.Millisecond, stop)
+	select {
+	case err := <-errs:
+		c.Assert(err, jc.Satisfies, IsNoMatchError)
+	case <-time.After(testing.LongWait):
+		c.Fatalf("timed out waiting for error")
+	}
+	_, ok := <-events
+	c.Assert(ok, jc.IsFalse)
+}
+
+const newEventsResponse = `
+{
+    "count": 2,
+    "events": [
+        {
+            "username": "admin",
+            "node": "4y3ha6",
+            "hostname": "untasted-markita",
+            "id": 7,
+            "level": "INFO",
+            "created": "Tue, 11 Oct. 2022 10:14:53",
+            "type": "Deployed",
+            "description": ""
+        },
+        {
+            "username": null,
+            "node": "4y3ha6",
+            "hostname": "untasted-markita",
+            "id": 6,
+            "level": "INFO",
+            "created": "Tue, 11 Oct. 2022 10:13:40",
+            "type": "Rebooting",
+            "description": ""
+        }
+    ],
+    "next_uri": "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=100&after=7",
+    "prev_uri": "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=100&before=6"
+}
+`
+
 func (s *controllerSuite) TestAPIVersionInfo(c *gc.C) {
 	s.server.AddGetResponse("/api/2.0/version/", http.StatusOK, versionResponse)
 	controller := s.getController(c)
-	version, subversion, err := controller.APIVersionInfo()
+	version, subversion, err := controller.APIVersionInfo()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(version, gc.Equals, "2.5.0 from source")
+	c.Assert(subversion, gc.Equals, "git+2f25a2cc0930c0e411106f119bc455c161d75b1a")
+}
+
+func (s *controllerSuite) TestDevices(c *gc.C) {
+	controller := s.getController(c)
+	devices, err := controller.Devices(DevicesArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, gc.HasLen, 1)
+}
+
+func (s *controllerSuite) TestDevicesArgs(c *gc.C) {
+	controller := s.getController(c)
+	// This will fail with a 404 due to the test server not having something  at
+	// that address, but we don't care, all we want to do is capture the request
+	// and make sure that all the values were set.
+	controller.Devices(DevicesArgs{
+		Hostname:     []string{"untasted-markita"},
+		MACAddresses: []string{"something"},
+		SystemIDs:    []string{"something-else"},
+		Domain:       "magic",
+		Zone:         "foo",
+		AgentName:    "agent 42",
+	})
+	request := s.server.LastRequest()
+	// There should be one entry in the form values for each of the args.
+	c.Assert(request.URL.Query(), gc.HasLen, 6)
+}
+
+func (s *controllerSuite) TestCreateDevice(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/devices/?op=", http.StatusOK, deviceResponse)
+	controller := s.getController(c)
+	device, err := controller.CreateDevice(CreateDeviceArgs{
+		MACAddresses: []string{"a-mac-address"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(device.SystemID(), gc.Equals, "4y3haf")
+}
+
+func (s *controllerSuite) TestCreateDeviceMissingAddress(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreateDevice(CreateDeviceArgs{})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "at least one MAC address must be specified")
+}
+
+func (s *controllerSuite) TestCreateDeviceBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/devices/?op=", http.StatusBadRequest, "some error")
+	controller := s.getController(c)
+	_, err := controller.CreateDevice(CreateDeviceArgs{
+		MACAddresses: []string{"a-mac-address"},
+	})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "some error")
+}
+
+func (s *controllerSuite) TestCreateDeviceArgs(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/devices/?op=", http.StatusOK, deviceResponse)
+	controller := s.getController(c)
+	// Create an arg structure that sets all the values.
+	args := CreateDeviceArgs{
+		Hostname:     "foobar",
+		MACAddresses: []string{"an-address"},
+		Domain:       "a domain",
+		Parent:       "parent",
+	}
+	_, err := controller.CreateDevice(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	request := s.server.LastRequest()
+	// There should be one entry in the form values for each of the args.
+	c.Assert(request.PostForm, gc.HasLen, 4)
+}
+
+func (s *controllerSuite) TestFabrics(c *gc.C) {
+	controller := s.getController(c)
+	fabrics, err := controller.Fabrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fabrics, gc.HasLen, 2)
+}
+
+func (s *controllerSuite) TestFabricsHaveController(c *gc.C) {
+	controller := s.getController(c)
+	fabrics, err := controller.Fabrics()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(fabrics[0].(*fabric).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestCreateFabric(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/fabrics/?op=", http.StatusOK, fabricItemResponse)
+	controller := s.getController(c)
+	result, err := controller.CreateFabric(CreateFabricArgs{
+		Name:        "fabric-1",
+		Description: "London DC",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.ID(), gc.Equals, 1)
+	c.Assert(result.Name(), gc.Equals, "fabric-1")
+	c.Assert(result.(*fabric).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("name"), gc.Equals, "fabric-1")
+	c.Check(form.Get("description"), gc.Equals, "London DC")
+}
+
+func (s *controllerSuite) TestCreateFabricBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/fabrics/?op=", http.StatusBadRequest, "name in use")
+	controller := s.getController(c)
+	_, err := controller.CreateFabric(CreateFabricArgs{Name: "fabric-0"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "name in use")
+}
+
+func (s *controllerSuite) TestSpaces(c *gc.C) {
+	controller := s.getController(c)
+	spaces, err := controller.Spaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaces, gc.HasLen, 1)
+}
+
+func (s *controllerSuite) TestCreateSpace(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/spaces/?op=", http.StatusOK, spaceResponse)
+	controller := s.getController(c)
+	result, err := controller.CreateSpace("storage", "storage network")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Name(), gc.Equals, "storage")
+	c.Assert(result.Description(), gc.Equals, "storage network")
+	c.Assert(result.(*space).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("name"), gc.Equals, "storage")
+	c.Check(form.Get("description"), gc.Equals, "storage network")
+}
+
+func (s *controllerSuite) TestCreateSpaceBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/spaces/?op=", http.StatusBadRequest, "name in use")
+	controller := s.getController(c)
+	_, err := controller.CreateSpace("space-0", "")
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "name in use")
+}
+
+func (s *controllerSuite) TestSubnets(c *gc.C) {
+	controller := s.getController(c)
+	subnets, err := controller.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.HasLen, 2)
+	c.Assert(subnets[0].(*subnet).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestGetSubnet(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/subnets/1/", http.StatusOK, subnetItemResponse)
+	controller := s.getController(c)
+	subnet, err := controller.GetSubnet(1)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.ID(), gc.Equals, 1)
+	c.Assert(subnet.CIDR(), gc.Equals, "192.168.100.0/24")
+}
+
+func (s *controllerSuite) TestGetSubnetMissing(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.GetSubnet(42)
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *controllerSuite) TestCreateSubnetValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreateSubnet(CreateSubnetArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing CIDR not valid")
+}
+
+func (s *controllerSuite) TestCreateSubnet(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/subnets/?op=", http.StatusOK, subnetItemResponse)
+	controller := s.getController(c)
+	managed := false
+	rdnsMode := RDNSEnabled
+	result, err := controller.CreateSubnet(CreateSubnetArgs{
+		CIDR:       "192.168.100.0/24",
+		Name:       "a subnet",
+		VLAN:       &fakeVLAN{id: 5},
+		Space:      "space-0",
+		GatewayIP:  "192.168.100.1",
+		DNSServers: []string{"8.8.8.8", "8.8.4.4"},
+		Managed:    &managed,
+		RDNSMode:   &rdnsMode,
+	})
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(version, gc.Equals, "2.5.0 from source")
-	c.Assert(subversion, gc.Equals, "git+2f25a2cc0930c0e411106f119bc455c161d75b1a")
+	c.Assert(result.ID(), gc.Equals, 1)
+	c.Assert(result.(*subnet).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 8)
+	c.Check(form.Get("cidr"), gc.Equals, "192.168.100.0/24")
+	c.Check(form.Get("name"), gc.Equals, "a subnet")
+	c.Check(form.Get("vlan"), gc.Equals, "5")
+	c.Check(form.Get("space"), gc.Equals, "space-0")
+	c.Check(form.Get("gateway_ip"), gc.Equals, "192.168.100.1")
+	c.Check(form.Get("dns_servers"), gc.Equals, "8.8.8.8,8.8.4.4")
+	c.Check(form.Get("managed"), gc.Equals, "false")
+	c.Check(form.Get("rdns_mode"), gc.Equals, "1")
 }
 
-func (s *controllerSuite) TestDevices(c *gc.C) {
+func (s *controllerSuite) TestCreateSubnetMinimal(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/subnets/?op=", http.StatusOK, subnetItemResponse)
 	controller := s.getController(c)
-	devices, err := controller.Devices(DevicesArgs{})
+	_, err := controller.CreateSubnet(CreateSubnetArgs{CIDR: "192.168.100.0/24"})
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(devices, gc.HasLen, 1)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 1)
 }
 
-func (s *controllerSuite) TestDevicesArgs(c *gc.C) {
+func (s *controllerSuite) TestCreateSubnetBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/subnets/?op=", http.StatusBadRequest, "bad cidr")
 	controller := s.getController(c)
-	// This will fail with a 404 due to the test server not having something  at
+	_, err := controller.CreateSubnet(CreateSubnetArgs{CIDR: "wat"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "bad cidr")
+}
+
+func (s *controllerSuite) TestIPRanges(c *gc.C) {
+	controller := s.getController(c)
+	ipRanges, err := controller.IPRanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipRanges, gc.HasLen, 2)
+	c.Assert(ipRanges[0].(*ipRange).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestCreateIPRangeArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    CreateIPRangeArgs
+		errText string
+	}{{
+		errText: "missing Type not valid",
+	}, {
+		args:    CreateIPRangeArgs{Type: "wat"},
+		errText: `unknown Type value ("wat") not valid`,
+	}, {
+		args:    CreateIPRangeArgs{Type: IPRangeDynamic},
+		errText: "missing StartIP not valid",
+	}, {
+		args:    CreateIPRangeArgs{Type: IPRangeDynamic, StartIP: "10.0.0.1"},
+		errText: "missing EndIP not valid",
+	}, {
+		args: CreateIPRangeArgs{Type: IPRangeReserved, StartIP: "10.0.0.1", EndIP: "10.0.0.9"},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *controllerSuite) TestCreateIPRangeValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreateIPRange(CreateIPRangeArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *controllerSuite) TestCreateIPRange(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/ipranges/?op=", http.StatusOK, ipRangeResponse)
+	controller := s.getController(c)
+	subnets, err := controller.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := controller.CreateIPRange(CreateIPRangeArgs{
+		Type:    IPRangeDynamic,
+		StartIP: "192.168.100.100",
+		EndIP:   "192.168.100.200",
+		Subnet:  subnets[0],
+		Comment: "dhcp",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.ID(), gc.Equals, 1)
+	c.Assert(result.(*ipRange).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 5)
+	c.Check(form.Get("type"), gc.Equals, "dynamic")
+	c.Check(form.Get("start_ip"), gc.Equals, "192.168.100.100")
+	c.Check(form.Get("end_ip"), gc.Equals, "192.168.100.200")
+	c.Check(form.Get("subnet"), gc.Equals, "1")
+	c.Check(form.Get("comment"), gc.Equals, "dhcp")
+}
+
+func (s *controllerSuite) TestCreateIPRangeBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/ipranges/?op=", http.StatusBadRequest, "overlaps")
+	controller := s.getController(c)
+	_, err := controller.CreateIPRange(CreateIPRangeArgs{
+		Type:    IPRangeReserved,
+		StartIP: "192.168.100.1",
+		EndIP:   "192.168.100.9",
+	})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "overlaps")
+}
+
+func (s *controllerSuite) TestIPAddresses(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/ipaddresses/", http.StatusOK, ipAddressesResponse)
+	controller := s.getController(c)
+	ipAddresses, err := controller.IPAddresses(IPAddressesArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipAddresses, gc.HasLen, 2)
+	c.Assert(ipAddresses[0].(*ipAddress).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestIPAddressesArgs(c *gc.C) {
+	controller := s.getController(c)
+	// This will fail with a 404 due to the test server not having something at
 	// that address, but we don't care, all we want to do is capture the request
 	// and make sure that all the values were set.
-	controller.Devices(DevicesArgs{
-		Hostname:     []string{"untasted-markita"},
-		MACAddresses: []string{"something"},
-		SystemIDs:    []string{"something-else"},
-		Domain:       "magic",
-		Zone:         "foo",
-		AgentName:    "agent 42",
+	controller.IPAddresses(IPAddressesArgs{
+		IPs:   []string{"192.168.100.4", "10.0.0.4"},
+		All:   true,
+		Owner: "admin",
 	})
-	request := s.server.LastRequest()
-	// There should be one entry in the form values for each of the args.
-	c.Assert(request.URL.Query(), gc.HasLen, 6)
+	query := s.server.LastRequest().URL.Query()
+	c.Assert(query, gc.HasLen, 3)
+	c.Assert(query["ip"], jc.DeepEquals, []string{"192.168.100.4", "10.0.0.4"})
+	c.Assert(query.Get("all"), gc.Equals, "true")
+	c.Assert(query.Get("owner"), gc.Equals, "admin")
 }
 
-func (s *controllerSuite) TestCreateDevice(c *gc.C) {
-	s.server.AddPostResponse("/api/2.0/devices/?op=", http.StatusOK, deviceResponse)
+func (s *controllerSuite) TestIPAddressesForbidden(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/ipaddresses/?all=true", http.StatusForbidden, "admins only")
 	controller := s.getController(c)
-	device, err := controller.CreateDevice(CreateDeviceArgs{
-		MACAddresses: []string{"a-mac-address"},
+	_, err := controller.IPAddresses(IPAddressesArgs{All: true})
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+func (s *controllerSuite) TestReserveIPAddressValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.ReserveIPAddress(ReserveIPArgs{Hostname: "foo"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing Subnet or IP not valid")
+}
+
+func (s *controllerSuite) TestReserveIPAddress(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/ipaddresses/?op=reserve", http.StatusOK, ipAddressResponse)
+	controller := s.getController(c)
+	subnets, err := controller.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	address, err := controller.ReserveIPAddress(ReserveIPArgs{
+		Subnet:     subnets[0],
+		IP:         "192.168.100.4",
+		Hostname:   "foo",
+		MACAddress: "52:54:00:c9:6a:45",
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(device.SystemID(), gc.Equals, "4y3haf")
+	c.Assert(address.IP(), gc.Equals, "192.168.100.4")
+	c.Assert(address.(*ipAddress).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 4)
+	c.Check(form.Get("subnet"), gc.Equals, "1")
+	c.Check(form.Get("ip"), gc.Equals, "192.168.100.4")
+	c.Check(form.Get("hostname"), gc.Equals, "foo")
+	c.Check(form.Get("mac"), gc.Equals, "52:54:00:c9:6a:45")
 }
 
-func (s *controllerSuite) TestCreateDeviceMissingAddress(c *gc.C) {
+func (s *controllerSuite) TestReserveIPAddressInUse(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/ipaddresses/?op=reserve", http.StatusNotFound, "address in use")
 	controller := s.getController(c)
-	_, err := controller.CreateDevice(CreateDeviceArgs{})
-	c.Assert(err, jc.Satisfies, IsBadRequestError)
-	c.Assert(err.Error(), gc.Equals, "at least one MAC address must be specified")
+	_, err := controller.ReserveIPAddress(ReserveIPArgs{IP: "192.168.100.4"})
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+	c.Assert(err.Error(), gc.Equals, "address in use")
 }
 
-func (s *controllerSuite) TestCreateDeviceBadRequest(c *gc.C) {
-	s.server.AddPostResponse("/api/2.0/devices/?op=", http.StatusBadRequest, "some error")
+func (s *controllerSuite) TestReleaseIPAddress(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/ipaddresses/?op=release", http.StatusNoContent, "")
 	controller := s.getController(c)
-	_, err := controller.CreateDevice(CreateDeviceArgs{
-		MACAddresses: []string{"a-mac-address"},
+	err := controller.ReleaseIPAddress("192.168.100.4", true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("ip"), gc.Equals, "192.168.100.4")
+	c.Check(form.Get("force"), gc.Equals, "true")
+}
+
+func (s *controllerSuite) TestReleaseIPAddressMissingIP(c *gc.C) {
+	controller := s.getController(c)
+	err := controller.ReleaseIPAddress("", false)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *controllerSuite) TestReleaseIPAddressNotFound(c *gc.C) {
+	controller := s.getController(c)
+	err := controller.ReleaseIPAddress("192.168.100.4", false)
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *controllerSuite) TestDomains(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/domains/", http.StatusOK, domainResponse)
+	controller := s.getController(c)
+	domains, err := controller.Domains()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(domains, gc.HasLen, 2)
+	c.Check(domains[0].(*domain).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestCreateDomain(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/domains/?op=", http.StatusOK, domainItemResponse)
+	controller := s.getController(c)
+	authoritative := true
+	result, err := controller.CreateDomain(CreateDomainArgs{
+		Name:          "anotherDomain.com",
+		Authoritative: &authoritative,
+		TTL:           10,
 	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ID(), gc.Equals, 1)
+	c.Check(result.Name(), gc.Equals, "anotherDomain.com")
+	c.Check(result.(*domain).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 3)
+	c.Check(form.Get("name"), gc.Equals, "anotherDomain.com")
+	c.Check(form.Get("authoritative"), gc.Equals, "true")
+	c.Check(form.Get("ttl"), gc.Equals, "10")
+}
+
+func (s *controllerSuite) TestCreateDomainValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreateDomain(CreateDomainArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing Name not valid")
+	_, err = controller.CreateDomain(CreateDomainArgs{Name: "example.com", TTL: -1})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *controllerSuite) TestCreateDomainBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/domains/?op=", http.StatusBadRequest, "name in use")
+	controller := s.getController(c)
+	_, err := controller.CreateDomain(CreateDomainArgs{Name: "maas"})
 	c.Assert(err, jc.Satisfies, IsBadRequestError)
-	c.Assert(err.Error(), gc.Equals, "some error")
+	c.Assert(err.Error(), gc.Equals, "name in use")
 }
 
-func (s *controllerSuite) TestCreateDeviceArgs(c *gc.C) {
-	s.server.AddPostResponse("/api/2.0/devices/?op=", http.StatusOK, deviceResponse)
+func (s *controllerSuite) TestDNSResources(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/dnsresources/", http.StatusOK, dnsResourcesResponse)
 	controller := s.getController(c)
-	// Create an arg structure that sets all the values.
-	args := CreateDeviceArgs{
-		Hostname:     "foobar",
-		MACAddresses: []string{"an-address"},
-		Domain:       "a domain",
-		Parent:       "parent",
-	}
-	_, err := controller.CreateDevice(args)
+	resources, err := controller.DNSResources(DNSResourcesArgs{})
 	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 2)
+	c.Check(resources[0].(*dnsResource).controller, gc.NotNil)
+}
 
-	request := s.server.LastRequest()
-	// There should be one entry in the form values for each of the args.
-	c.Assert(request.PostForm, gc.HasLen, 4)
+func (s *controllerSuite) TestDNSResourcesArgs(c *gc.C) {
+	controller := s.getController(c)
+	// This will fail with a 404 due to the test server not having something at
+	// that address, but we don't care, all we want to do is capture the request
+	// and make sure that all the values were set.
+	controller.DNSResources(DNSResourcesArgs{
+		Domain: "maas",
+		Name:   "ldap",
+		Type:   DNSRecordTXT,
+	})
+	query := s.server.LastRequest().URL.Query()
+	c.Assert(query, gc.HasLen, 3)
+	c.Assert(query.Get("domain"), gc.Equals, "maas")
+	c.Assert(query.Get("name"), gc.Equals, "ldap")
+	c.Assert(query.Get("rrtype"), gc.Equals, "TXT")
 }
 
-func (s *controllerSuite) TestFabrics(c *gc.C) {
+func (s *controllerSuite) TestCreateDNSResource(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/dnsresources/?op=", http.StatusOK, dnsResourceResponse)
 	controller := s.getController(c)
-	fabrics, err := controller.Fabrics()
+	result, err := controller.CreateDNSResource(CreateDNSResourceArgs{
+		Name:        "ldap",
+		Domain:      "maas",
+		IPAddresses: []string{"192.168.100.20", "2001:db8::20"},
+		AddressTTL:  60,
+	})
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(fabrics, gc.HasLen, 2)
+	c.Check(result.FQDN(), gc.Equals, "ldap.maas")
+	c.Check(result.(*dnsResource).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 4)
+	c.Check(form.Get("name"), gc.Equals, "ldap")
+	c.Check(form.Get("domain"), gc.Equals, "maas")
+	c.Check(form.Get("ip_addresses"), gc.Equals, "192.168.100.20 2001:db8::20")
+	c.Check(form.Get("address_ttl"), gc.Equals, "60")
 }
 
-func (s *controllerSuite) TestSpaces(c *gc.C) {
+func (s *controllerSuite) TestCreateDNSResourceValidates(c *gc.C) {
 	controller := s.getController(c)
-	spaces, err := controller.Spaces()
+	for i, test := range []struct {
+		args    CreateDNSResourceArgs
+		message string
+	}{{
+		args:    CreateDNSResourceArgs{Name: "ldap", IPAddresses: []string{"10.0.0.1"}},
+		message: "missing FQDN, or Name and Domain not valid",
+	}, {
+		args:    CreateDNSResourceArgs{FQDN: "ldap.maas"},
+		message: "missing IPAddresses not valid",
+	}, {
+		args:    CreateDNSResourceArgs{FQDN: "ldap.maas", IPAddresses: []string{"10.0.0.1"}, AddressTTL: -1},
+		message: "negative AddressTTL not valid",
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.CreateDNSResource(test.args)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err.Error(), gc.Equals, test.message)
+	}
+}
+
+func (s *controllerSuite) TestDNSResourceRecords(c *gc.C) {
+	s.server.AddGetResponse("/api/2.0/dnsresourcerecords/?rrtype=SRV", http.StatusOK, dnsResourceRecordsResponse)
+	controller := s.getController(c)
+	records, err := controller.DNSResourceRecords(DNSResourceRecordsArgs{Type: DNSRecordSRV})
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(spaces, gc.HasLen, 1)
+	c.Assert(records, gc.HasLen, 2)
+	c.Check(records[0].(*dnsResourceRecord).controller, gc.NotNil)
+}
+
+func (s *controllerSuite) TestCreateDNSResourceRecord(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/dnsresourcerecords/?op=", http.StatusOK, dnsResourceRecordResponse)
+	controller := s.getController(c)
+	result, err := controller.CreateDNSResourceRecord(CreateDNSResourceRecordArgs{
+		FQDN: "_ldap._tcp.maas",
+		Type: DNSRecordSRV,
+		Data: "0 5 389 ldap.maas",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ID(), gc.Equals, 7)
+	c.Check(result.(*dnsResourceRecord).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 3)
+	c.Check(form.Get("fqdn"), gc.Equals, "_ldap._tcp.maas")
+	c.Check(form.Get("rrtype"), gc.Equals, "SRV")
+	c.Check(form.Get("rrdata"), gc.Equals, "0 5 389 ldap.maas")
+}
+
+func (s *controllerSuite) TestCreateDNSResourceRecordValidates(c *gc.C) {
+	controller := s.getController(c)
+	for i, test := range []struct {
+		args    CreateDNSResourceRecordArgs
+		message string
+	}{{
+		args:    CreateDNSResourceRecordArgs{Domain: "maas", Type: DNSRecordTXT, Data: "hello"},
+		message: "missing FQDN, or Name and Domain not valid",
+	}, {
+		args:    CreateDNSResourceRecordArgs{FQDN: "www.maas", Data: "web.maas"},
+		message: "missing Type not valid",
+	}, {
+		args:    CreateDNSResourceRecordArgs{FQDN: "www.maas", Type: DNSRecordCNAME},
+		message: "missing Data not valid",
+	}, {
+		args:    CreateDNSResourceRecordArgs{FQDN: "www.maas", Type: DNSRecordCNAME, Data: "web.maas", TTL: -1},
+		message: "negative TTL not valid",
+	}, {
+		args:    CreateDNSResourceRecordArgs{FQDN: "maas", Type: DNSRecordMX, Data: "mail.maas"},
+		message: `MX data "mail.maas" not valid`,
+	}, {
+		args:    CreateDNSResourceRecordArgs{FQDN: "www.maas", Type: DNSRecordA, Data: "10.0.0.1"},
+		message: "A records are managed through the IP addresses of a DNSResource",
+	}} {
+		c.Logf("test %d", i)
+		_, err := controller.CreateDNSResourceRecord(test.args)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err.Error(), gc.Equals, test.message)
+	}
+}
+
+func (s *controllerSuite) TestCreateDNSResourceRecordBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/dnsresourcerecords/?op=", http.StatusBadRequest, "no such domain")
+	controller := s.getController(c)
+	_, err := controller.CreateDNSResourceRecord(CreateDNSResourceRecordArgs{
+		Name:   "www",
+		Domain: "missing",
+		Type:   DNSRecordCNAME,
+		Data:   "web.maas",
+	})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "no such domain")
 }
 
 func (s *controllerSuite) TestStaticRoutes(c *gc.C) {
@@ -353,6 +1636,65 @@ func (s *controllerSuite) TestZones(c *gc.C) {
 	c.Assert(zones, gc.HasLen, 2)
 }
 
+func (s *controllerSuite) TestZoneSummaries(c *gc.C) {
+	controller := s.getController(c)
+	summaries, err := controller.ZoneSummaries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summaries, gc.HasLen, 2)
+	c.Check(summaries[0].Zone.Name(), gc.Equals, "default")
+	c.Check(summaries[0].Machines, gc.Equals, 3)
+	c.Check(summaries[0].Devices, gc.Equals, 1)
+	c.Check(summaries[1].Zone.Name(), gc.Equals, "special")
+	c.Check(summaries[1].Machines, gc.Equals, 0)
+	c.Check(summaries[1].Devices, gc.Equals, 0)
+}
+
+func (s *controllerSuite) TestZoneSummariesFromServer(c *gc.C) {
+	response := `[{"id": 1, "name": "default", "description": "", "resource_uri": "/MAAS/api/2.0/zones/default/", "machines": 7, "devices": 2}]`
+	server, controller := createTestServerController(c, s)
+	server.AddGetResponse("/api/2.0/zones/", http.StatusOK, response)
+	count := server.RequestCount()
+	summaries, err := controller.ZoneSummaries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summaries, gc.HasLen, 1)
+	c.Check(summaries[0].Machines, gc.Equals, 7)
+	c.Check(summaries[0].Devices, gc.Equals, 2)
+	// Only the zones are requested.
+	c.Check(server.RequestCount(), gc.Equals, count+1)
+}
+
+func (s *controllerSuite) TestCreateZone(c *gc.C) {
+	response := `{"id": 3, "name": "rack-3", "description": "third rack", "resource_uri": "/MAAS/api/2.0/zones/rack-3/"}`
+	s.server.AddPostResponse("/api/2.0/zones/?op=", http.StatusOK, response)
+	controller := s.getController(c)
+	result, err := controller.CreateZone("rack-3", "third rack")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ID(), gc.Equals, 3)
+	c.Check(result.Name(), gc.Equals, "rack-3")
+	c.Check(result.Description(), gc.Equals, "third rack")
+	c.Check(result.(*zone).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("name"), gc.Equals, "rack-3")
+	c.Check(form.Get("description"), gc.Equals, "third rack")
+}
+
+func (s *controllerSuite) TestCreateZoneValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreateZone("", "third rack")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing name not valid")
+}
+
+func (s *controllerSuite) TestCreateZoneBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/zones/?op=", http.StatusBadRequest, "name in use")
+	controller := s.getController(c)
+	_, err := controller.CreateZone("default", "")
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "name in use")
+}
+
 func (s *controllerSuite) TestPools(c *gc.C) {
 	controller := s.getController(c)
 	pools, err := controller.Pools()
@@ -360,6 +1702,54 @@ func (s *controllerSuite) TestPools(c *gc.C) {
 	c.Assert(pools, gc.HasLen, 2)
 }
 
+func (s *controllerSuite) TestCreatePool(c *gc.C) {
+	response := `{"id": 2, "name": "swimming", "description": "pool", "resource_uri": "/MAAS/api/2.0/pools/2/"}`
+	s.server.AddPostResponse("/api/2.0/pools/?op=", http.StatusOK, response)
+	controller := s.getController(c)
+	result, err := controller.CreatePool("swimming", "pool")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ID(), gc.Equals, 2)
+	c.Check(result.Name(), gc.Equals, "swimming")
+	c.Check(result.(*pool).controller, gc.NotNil)
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("name"), gc.Equals, "swimming")
+	c.Check(form.Get("description"), gc.Equals, "pool")
+}
+
+func (s *controllerSuite) TestCreatePoolValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreatePool("", "pool")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *controllerSuite) TestPoolSummaries(c *gc.C) {
+	controller := s.getController(c)
+	summaries, err := controller.PoolSummaries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summaries, gc.HasLen, 2)
+	c.Check(summaries[0].Pool.Name(), gc.Equals, "default")
+	c.Check(summaries[0].Machines, gc.Equals, 3)
+	c.Check(summaries[0].Devices, gc.Equals, 1)
+	c.Check(summaries[1].Pool.Name(), gc.Equals, "swimming_is_fun")
+	c.Check(summaries[1].Machines, gc.Equals, 0)
+	c.Check(summaries[1].Devices, gc.Equals, 0)
+}
+
+func (s *controllerSuite) TestPoolSummariesFromServer(c *gc.C) {
+	response := `[{"id": 0, "name": "default", "description": "", "resource_uri": "/MAAS/api/2.0/pools/0/", "machines": 4, "devices": 0}]`
+	server, controller := createTestServerController(c, s)
+	server.AddGetResponse("/api/2.0/pools/", http.StatusOK, response)
+	count := server.RequestCount()
+	summaries, err := controller.PoolSummaries()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(summaries, gc.HasLen, 1)
+	c.Check(summaries[0].Machines, gc.Equals, 4)
+	c.Check(summaries[0].Devices, gc.Equals, 0)
+	c.Check(server.RequestCount(), gc.Equals, count+1)
+}
+
 func (s *controllerSuite) TestMachines(c *gc.C) {
 	controller := s.getController(c)
 	machines, err := controller.Machines(MachinesArgs{})
@@ -802,6 +2192,97 @@ func (s *controllerSuite) TestReleaseMachinesUnexpected(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "unexpected: ServerError: 502 Bad Gateway (wat)")
 }
 
+func (s *controllerSuite) TestCreateMachineArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    CreateMachineArgs
+		errText string
+	}{{
+		errText: "missing Architecture not valid",
+	}, {
+		args:    CreateMachineArgs{Architecture: "amd64/generic"},
+		errText: "missing MACAddresses not valid",
+	}, {
+		args: CreateMachineArgs{
+			Architecture:    "amd64/generic",
+			MACAddresses:    []string{"a-mac-address"},
+			PowerParameters: PowerParameters{"power_address": "10.0.0.1"},
+		},
+		errText: "PowerParameters without PowerType not valid",
+	}, {
+		args: CreateMachineArgs{
+			Architecture: "amd64/generic",
+			MACAddresses: []string{"a-mac-address"},
+		},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *controllerSuite) TestCreateMachineValidates(c *gc.C) {
+	controller := s.getController(c)
+	_, err := controller.CreateMachine(CreateMachineArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *controllerSuite) TestCreateMachine(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/machines/?op=", http.StatusOK, machineResponse)
+	controller := s.getController(c)
+	machine, err := controller.CreateMachine(CreateMachineArgs{
+		Hostname:     "untasted-markita",
+		Architecture: "amd64/generic",
+		MACAddresses: []string{"52:54:00:55:b6:80", "52:54:00:55:b6:81"},
+		Domain:       "maas",
+		Zone:         "default",
+		Pool:         "default",
+		PowerType:    "ipmi",
+		PowerParameters: PowerParameters{
+			"power_address": "10.0.0.1",
+			"power_user":    "admin",
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.SystemID(), gc.Equals, "4y3ha3")
+
+	form := s.server.LastRequest().PostForm
+	c.Check(form.Get("hostname"), gc.Equals, "untasted-markita")
+	c.Check(form.Get("architecture"), gc.Equals, "amd64/generic")
+	c.Check(form["mac_addresses"], jc.DeepEquals, []string{"52:54:00:55:b6:80", "52:54:00:55:b6:81"})
+	c.Check(form.Get("domain"), gc.Equals, "maas")
+	c.Check(form.Get("zone"), gc.Equals, "default")
+	c.Check(form.Get("pool"), gc.Equals, "default")
+	c.Check(form.Get("power_type"), gc.Equals, "ipmi")
+	c.Check(form.Get("power_parameters_power_address"), gc.Equals, "10.0.0.1")
+	c.Check(form.Get("power_parameters_power_user"), gc.Equals, "admin")
+}
+
+func (s *controllerSuite) TestCreateMachineBadRequest(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/machines/?op=", http.StatusBadRequest, "unknown architecture")
+	controller := s.getController(c)
+	_, err := controller.CreateMachine(CreateMachineArgs{
+		Architecture: "z80/generic",
+		MACAddresses: []string{"a-mac-address"},
+	})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "unknown architecture")
+}
+
+func (s *controllerSuite) TestCreateMachineUnexpected(c *gc.C) {
+	s.server.AddPostResponse("/api/2.0/machines/?op=", http.StatusConflict, "wat?")
+	controller := s.getController(c)
+	_, err := controller.CreateMachine(CreateMachineArgs{
+		Architecture: "amd64/generic",
+		MACAddresses: []string{"a-mac-address"},
+	})
+	c.Assert(err, jc.Satisfies, IsUnexpectedError)
+}
+
 func (s *controllerSuite) TestFiles(c *gc.C) {
 	controller := s.getController(c)
 	files, err := controller.Files("")
@@ -0,0 +1,269 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+// The node types that MAAS uses for its own controllers.
+const (
+	nodeTypeRackController          = 2
+	nodeTypeRegionController        = 3
+	nodeTypeRegionAndRackController = 4
+)
+
+// ControllerService is a service that runs on a rack or region controller,
+// such as "rackd" or "dhcpd", along with its status.
+type ControllerService struct {
+	Name string
+	// Status is "running", "degraded", "dead", "off" or "unknown".
+	Status     string
+	StatusInfo string
+}
+
+// controllerNode is a rack or region controller of MAAS, as opposed to the
+// Controller that talks to the MAAS API.
+type controllerNode struct {
+	controller *controller
+
+	resourceURI string
+
+	systemID     string
+	hostname     string
+	fqdn         string
+	nodeType     int
+	version      string
+	architecture string
+	ipAddresses  []string
+	services     []ControllerService
+
+	interfaceSet []*interface_
+}
+
+// SystemID implements RackController and RegionController.
+func (n *controllerNode) SystemID() string {
+	return n.systemID
+}
+
+// Hostname implements RackController and RegionController.
+func (n *controllerNode) Hostname() string {
+	return n.hostname
+}
+
+// FQDN implements RackController and RegionController.
+func (n *controllerNode) FQDN() string {
+	return n.fqdn
+}
+
+// Version implements RackController and RegionController.
+func (n *controllerNode) Version() string {
+	return n.version
+}
+
+// Architecture implements RackController and RegionController.
+func (n *controllerNode) Architecture() string {
+	return n.architecture
+}
+
+// IPAddresses implements RackController and RegionController.
+func (n *controllerNode) IPAddresses() []string {
+	return n.ipAddresses
+}
+
+// IsRackController implements RackController and RegionController.
+func (n *controllerNode) IsRackController() bool {
+	return n.nodeType == nodeTypeRackController || n.nodeType == nodeTypeRegionAndRackController
+}
+
+// IsRegionController implements RackController and RegionController.
+func (n *controllerNode) IsRegionController() bool {
+	return n.nodeType == nodeTypeRegionController || n.nodeType == nodeTypeRegionAndRackController
+}
+
+// Services implements RackController and RegionController.
+func (n *controllerNode) Services() []ControllerService {
+	return n.services
+}
+
+// InterfaceSet implements RackController and RegionController.
+func (n *controllerNode) InterfaceSet() []Interface {
+	result := make([]Interface, len(n.interfaceSet))
+	for i, v := range n.interfaceSet {
+		v.controller = n.controller
+		result[i] = v
+	}
+	return result
+}
+
+// ServedVLANs implements RackController.
+func (n *controllerNode) ServedVLANs() ([]VLAN, error) {
+	fabrics, err := n.controller.Fabrics()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []VLAN
+	for _, fabric := range fabrics {
+		for _, vlan := range fabric.VLANs() {
+			if vlan.PrimaryRack() == n.systemID || vlan.SecondaryRack() == n.systemID {
+				result = append(result, vlan)
+			}
+		}
+	}
+	return result, nil
+}
+
+func readControllerNode(controllerVersion version.Number, source interface{}) (*controllerNode, error) {
+	readFunc, err := getControllerNodeDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "controller node base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readControllerNodes(controllerVersion version.Number, source interface{}) ([]*controllerNode, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "controller node base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getControllerNodeDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readControllerNodeList(valid, readFunc)
+}
+
+func getControllerNodeDeserializationFunc(controllerVersion version.Number) (controllerNodeDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range controllerNodeDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no controller node read func for version %s", controllerVersion)
+	}
+	return controllerNodeDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readControllerNodeList expects the values of the sourceList to be string
+// maps.
+func readControllerNodeList(sourceList []interface{}, readFunc controllerNodeDeserializationFunc) ([]*controllerNode, error) {
+	result := make([]*controllerNode, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for controller node %d, %T", i, value)
+		}
+		node, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "controller node %d", i)
+		}
+		result = append(result, node)
+	}
+	return result, nil
+}
+
+type controllerNodeDeserializationFunc func(map[string]interface{}) (*controllerNode, error)
+
+var controllerNodeDeserializationFuncs = map[version.Number]controllerNodeDeserializationFunc{
+	twoDotOh: controllerNode_2_0,
+}
+
+func controllerNode_2_0(source map[string]interface{}) (*controllerNode, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"system_id":    schema.String(),
+		"hostname":     schema.String(),
+		"fqdn":         schema.String(),
+		"node_type":    schema.ForceInt(),
+		"version":      schema.OneOf(schema.Nil(""), schema.String()),
+		"architecture": schema.OneOf(schema.Nil(""), schema.String()),
+		"ip_addresses": schema.List(schema.String()),
+		"service_set":  schema.List(schema.StringMap(schema.Any())),
+
+		"interface_set": schema.List(schema.StringMap(schema.Any())),
+	}
+	defaults := schema.Defaults{
+		// Only MAAS 2.3 and later report the version of their controllers.
+		"version":       "",
+		"architecture":  "",
+		"ip_addresses":  []interface{}{},
+		"service_set":   []interface{}{},
+		"interface_set": []interface{}{},
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "controller node 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	services, err := readControllerServices(valid["service_set"].([]interface{}))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	interfaceSet, err := readInterfaceList(valid["interface_set"].([]interface{}), interface_2_0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	nodeVersion, _ := valid["version"].(string)
+	architecture, _ := valid["architecture"].(string)
+	result := &controllerNode{
+		resourceURI: valid["resource_uri"].(string),
+
+		systemID:     valid["system_id"].(string),
+		hostname:     valid["hostname"].(string),
+		fqdn:         valid["fqdn"].(string),
+		nodeType:     valid["node_type"].(int),
+		version:      nodeVersion,
+		architecture: architecture,
+		ipAddresses:  convertToStringSlice(valid["ip_addresses"]),
+		services:     services,
+
+		interfaceSet: interfaceSet,
+	}
+	return result, nil
+}
+
+func readControllerServices(sourceList []interface{}) ([]ControllerService, error) {
+	checker := schema.FieldMap(schema.Fields{
+		"name":        schema.String(),
+		"status":      schema.String(),
+		"status_info": schema.String(),
+	}, schema.Defaults{
+		"status_info": "",
+	})
+	result := make([]ControllerService, 0, len(sourceList))
+	for i, value := range sourceList {
+		coerced, err := checker.Coerce(value, nil)
+		if err != nil {
+			return nil, WrapWithDeserializationError(err, "service %d schema check failed", i)
+		}
+		valid := coerced.(map[string]interface{})
+		result = append(result, ControllerService{
+			Name:       valid["name"].(string),
+			Status:     valid["status"].(string),
+			StatusInfo: valid["status_info"].(string),
+		})
+	}
+	return result, nil
+}
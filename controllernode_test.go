@@ -0,0 +1,165 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type controllerNodeSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&controllerNodeSuite{})
+
+func (*controllerNodeSuite) TestReadControllerNodesBadSchema(c *gc.C) {
+	_, err := readControllerNodes(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `controller node base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*controllerNodeSuite) TestReadControllerNodes(c *gc.C) {
+	nodes, err := readControllerNodes(twoDotOh, parseJSON(c, rackControllersResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nodes, gc.HasLen, 2)
+
+	node := nodes[0]
+	c.Check(node.SystemID(), gc.Equals, "4y3h7n")
+	c.Check(node.Hostname(), gc.Equals, "region")
+	c.Check(node.FQDN(), gc.Equals, "region.maas")
+	c.Check(node.Version(), gc.Equals, "3.2.6")
+	c.Check(node.Architecture(), gc.Equals, "amd64/generic")
+	c.Check(node.IPAddresses(), jc.DeepEquals, []string{"192.168.100.2"})
+	c.Check(node.IsRackController(), jc.IsTrue)
+	c.Check(node.IsRegionController(), jc.IsTrue)
+	c.Check(node.Services(), jc.DeepEquals, []ControllerService{
+		{Name: "rackd", Status: "running"},
+		{Name: "dhcpd", Status: "dead", StatusInfo: "dhcpd failed to start"},
+	})
+	interfaces := node.InterfaceSet()
+	c.Assert(interfaces, gc.HasLen, 1)
+	c.Check(interfaces[0].ID(), gc.Equals, 40)
+
+	node = nodes[1]
+	c.Check(node.Version(), gc.Equals, "")
+	c.Check(node.IsRackController(), jc.IsTrue)
+	c.Check(node.IsRegionController(), jc.IsFalse)
+	c.Check(node.Services(), gc.HasLen, 0)
+	c.Check(node.InterfaceSet(), gc.HasLen, 0)
+}
+
+func (*controllerNodeSuite) TestReadControllerNodeBadService(c *gc.C) {
+	_, err := readControllerNode(twoDotOh, parseJSON(c, `{
+        "system_id": "4y3h7n",
+        "hostname": "region",
+        "fqdn": "region.maas",
+        "node_type": 4,
+        "service_set": [{"status": "running"}],
+        "resource_uri": "/MAAS/api/2.0/rackcontrollers/4y3h7n/"
+    }`))
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err, gc.ErrorMatches, "service 0 schema check failed: name: expected string, got nothing")
+}
+
+func (*controllerNodeSuite) TestLowVersion(c *gc.C) {
+	_, err := readControllerNodes(version.MustParse("1.9.0"), parseJSON(c, rackControllersResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*controllerNodeSuite) TestHighVersion(c *gc.C) {
+	nodes, err := readControllerNodes(version.MustParse("2.1.9"), parseJSON(c, rackControllersResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(nodes, gc.HasLen, 2)
+}
+
+func (s *controllerNodeSuite) getServerAndNodes(c *gc.C) (*SimpleTestServer, []*controllerNode) {
+	server, ctrl := createTestServerController(c, s)
+	nodes, err := readControllerNodes(twoDotOh, parseJSON(c, rackControllersResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	for _, node := range nodes {
+		node.controller = ctrl.(*controller)
+	}
+	return server, nodes
+}
+
+func (s *controllerNodeSuite) TestInterfacesHaveController(c *gc.C) {
+	_, nodes := s.getServerAndNodes(c)
+	c.Check(nodes[0].InterfaceSet()[0].(*interface_).controller, gc.Equals, nodes[0].controller)
+}
+
+func (s *controllerNodeSuite) TestServedVLANs(c *gc.C) {
+	server, nodes := s.getServerAndNodes(c)
+	server.AddGetResponse("/api/2.0/fabrics/", http.StatusOK, fabricResponse)
+	vlans, err := nodes[0].ServedVLANs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vlans, gc.HasLen, 1)
+	c.Check(vlans[0].ID(), gc.Equals, 1)
+	c.Check(vlans[0].(*vlan).controller, gc.Equals, nodes[0].controller)
+}
+
+func (s *controllerNodeSuite) TestServedVLANsNone(c *gc.C) {
+	server, nodes := s.getServerAndNodes(c)
+	server.AddGetResponse("/api/2.0/fabrics/", http.StatusOK, fabricResponse)
+	vlans, err := nodes[1].ServedVLANs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(vlans, gc.HasLen, 0)
+}
+
+var rackControllerResponse = `
+{
+    "system_id": "4y3h7n",
+    "hostname": "region",
+    "fqdn": "region.maas",
+    "node_type": 4,
+    "node_type_name": "Region and rack controller",
+    "version": "3.2.6",
+    "architecture": "amd64/generic",
+    "ip_addresses": ["192.168.100.2"],
+    "service_set": [
+        {"name": "rackd", "status": "running", "status_info": ""},
+        {"name": "dhcpd", "status": "dead", "status_info": "dhcpd failed to start"}
+    ],
+    "interface_set": [` + interfaceResponse + `],
+    "resource_uri": "/MAAS/api/2.0/rackcontrollers/4y3h7n/"
+}
+`
+
+var rackControllersResponse = `
+[` + rackControllerResponse + `,
+    {
+        "system_id": "8cwnrp",
+        "hostname": "rack-2",
+        "fqdn": "rack-2.maas",
+        "node_type": 2,
+        "node_type_name": "Rack controller",
+        "version": null,
+        "architecture": "arm64/generic",
+        "ip_addresses": [],
+        "resource_uri": "/MAAS/api/2.0/rackcontrollers/8cwnrp/"
+    }
+]
+`
+
+var regionControllersResponse = `
+[` + rackControllerResponse + `,
+    {
+        "system_id": "q8gmxs",
+        "hostname": "region-2",
+        "fqdn": "region-2.maas",
+        "node_type": 3,
+        "node_type_name": "Region controller",
+        "version": "3.2.6",
+        "architecture": "amd64/generic",
+        "ip_addresses": ["192.168.100.3"],
+        "service_set": [{"name": "regiond", "status": "running", "status_info": ""}],
+        "interface_set": [],
+        "resource_uri": "/MAAS/api/2.0/regioncontrollers/q8gmxs/"
+    }
+]
+`
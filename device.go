@@ -29,6 +29,7 @@ type device struct {
 	interfaceSet []*interface_
 	zone         *zone
 	pool         *pool
+	domain       *domain
 }
 
 // SystemID implements Device.
@@ -66,6 +67,7 @@ func (d *device) Zone() Zone {
 	if d.zone == nil {
 		return nil
 	}
+	d.zone.controller = d.controller
 	return d.zone
 }
 
@@ -74,9 +76,19 @@ func (d *device) Pool() Pool {
 	if d.pool == nil {
 		return nil
 	}
+	d.pool.controller = d.controller
 	return d.pool
 }
 
+// Domain implements Device.
+func (d *device) Domain() Domain {
+	if d.domain == nil {
+		return nil
+	}
+	d.domain.controller = d.controller
+	return d.domain
+}
+
 // InterfaceSet implements Device.
 func (d *device) InterfaceSet() []Interface {
 	result := make([]Interface, len(d.interfaceSet))
@@ -262,10 +274,12 @@ func device_2_0(source map[string]interface{}) (*device, error) {
 		"interface_set": schema.List(schema.StringMap(schema.Any())),
 		"zone":          schema.StringMap(schema.Any()),
 		"pool":          schema.OneOf(schema.Nil(""), schema.StringMap(schema.Any())),
+		"domain":        schema.OneOf(schema.Nil(""), schema.StringMap(schema.Any())),
 	}
 	defaults := schema.Defaults{
 		"owner":  "",
 		"parent": "",
+		"domain": nil,
 	}
 	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(source, nil)
@@ -293,6 +307,13 @@ func device_2_0(source map[string]interface{}) (*device, error) {
 		}
 	}
 
+	var domain *domain
+	if valid["domain"] != nil {
+		if domain, err = domain_(valid["domain"].(map[string]interface{})); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
 	owner, _ := valid["owner"].(string)
 	parent, _ := valid["parent"].(string)
 	result := &device{
@@ -308,6 +329,7 @@ func device_2_0(source map[string]interface{}) (*device, error) {
 		interfaceSet: interfaceSet,
 		zone:         zone,
 		pool:         pool,
+		domain:       domain,
 	}
 	return result, nil
 }
@@ -46,6 +46,10 @@ func (*deviceSuite) TestReadDevices(c *gc.C) {
 	pool := device.Pool()
 	c.Check(pool, gc.NotNil)
 	c.Check(pool.Name(), gc.Equals, "default")
+	domain := device.Domain()
+	c.Check(domain, gc.NotNil)
+	c.Check(domain.Name(), gc.Equals, "maas")
+	c.Check(domain.Authoritative(), jc.IsTrue)
 }
 
 func (*deviceSuite) TestReadDevicesNils(c *gc.C) {
@@ -54,6 +58,7 @@ func (*deviceSuite) TestReadDevicesNils(c *gc.C) {
 	deviceMap["owner"] = nil
 	deviceMap["parent"] = nil
 	deviceMap["pool"] = nil
+	deviceMap["domain"] = nil
 	devices, err := readDevices(twoDotOh, json)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(devices, gc.HasLen, 1)
@@ -62,6 +67,7 @@ func (*deviceSuite) TestReadDevicesNils(c *gc.C) {
 	c.Check(device.Owner(), gc.Equals, "")
 	c.Check(device.Parent(), gc.Equals, "")
 	c.Check(device.Pool(), gc.IsNil)
+	c.Check(device.Domain(), gc.IsNil)
 }
 
 func (*deviceSuite) TestLowVersion(c *gc.C) {
@@ -0,0 +1,235 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type dnsResource struct {
+	controller *controller
+
+	resourceURI string
+
+	id          int
+	fqdn        string
+	addressTTL  *int
+	ipAddresses []string
+
+	resourceRecords []*dnsResourceRecord
+}
+
+func (r *dnsResource) updateFrom(other *dnsResource) {
+	r.resourceURI = other.resourceURI
+	r.id = other.id
+	r.fqdn = other.fqdn
+	r.addressTTL = other.addressTTL
+	r.ipAddresses = other.ipAddresses
+	r.resourceRecords = other.resourceRecords
+}
+
+// ID implements DNSResource.
+func (r *dnsResource) ID() int {
+	return r.id
+}
+
+// FQDN implements DNSResource.
+func (r *dnsResource) FQDN() string {
+	return r.fqdn
+}
+
+// AddressTTL implements DNSResource.
+func (r *dnsResource) AddressTTL() int {
+	if r.addressTTL == nil {
+		return 0
+	}
+	return *r.addressTTL
+}
+
+// IPAddresses implements DNSResource.
+func (r *dnsResource) IPAddresses() []string {
+	return r.ipAddresses
+}
+
+// ResourceRecords implements DNSResource.
+func (r *dnsResource) ResourceRecords() []DNSResourceRecord {
+	result := make([]DNSResourceRecord, len(r.resourceRecords))
+	for i, v := range r.resourceRecords {
+		v.controller = r.controller
+		result[i] = v
+	}
+	return result
+}
+
+// UpdateDNSResourceArgs is an argument struct for passing parameters to
+// the DNSResource.Update method. Only the values that are specified are
+// changed.
+type UpdateDNSResourceArgs struct {
+	FQDN string
+	// IPAddresses replace all the addresses of the resource.
+	IPAddresses []string
+	AddressTTL  int
+}
+
+// Update implements DNSResource.
+func (r *dnsResource) Update(args UpdateDNSResourceArgs) error {
+	if args.AddressTTL < 0 {
+		return errors.NotValidf("negative AddressTTL")
+	}
+	params := NewURLParams()
+	params.MaybeAdd("fqdn", args.FQDN)
+	params.MaybeAdd("ip_addresses", strings.Join(args.IPAddresses, " "))
+	params.MaybeAddInt("address_ttl", args.AddressTTL)
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := r.controller.put(r.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readDNSResource(r.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.updateFrom(response)
+	return nil
+}
+
+// Delete implements DNSResource.
+func (r *dnsResource) Delete() error {
+	err := r.controller.delete(r.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+func readDNSResource(controllerVersion version.Number, source interface{}) (*dnsResource, error) {
+	readFunc, err := getDNSResourceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "dns resource base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readDNSResources(controllerVersion version.Number, source interface{}) ([]*dnsResource, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "dns resource base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getDNSResourceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readDNSResourceList(valid, readFunc)
+}
+
+func getDNSResourceDeserializationFunc(controllerVersion version.Number) (dnsResourceDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range dnsResourceDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no dns resource read func for version %s", controllerVersion)
+	}
+	return dnsResourceDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readDNSResourceList expects the values of the sourceList to be string maps.
+func readDNSResourceList(sourceList []interface{}, readFunc dnsResourceDeserializationFunc) ([]*dnsResource, error) {
+	result := make([]*dnsResource, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for dns resource %d, %T", i, value)
+		}
+		resource, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "dns resource %d", i)
+		}
+		result = append(result, resource)
+	}
+	return result, nil
+}
+
+type dnsResourceDeserializationFunc func(map[string]interface{}) (*dnsResource, error)
+
+var dnsResourceDeserializationFuncs = map[version.Number]dnsResourceDeserializationFunc{
+	twoDotOh: dnsResource_2_0,
+}
+
+func dnsResource_2_0(source map[string]interface{}) (*dnsResource, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":          schema.ForceInt(),
+		"fqdn":        schema.String(),
+		"address_ttl": schema.OneOf(schema.Nil(""), schema.ForceInt()),
+		// The addresses are rendered as ip address objects.
+		"ip_addresses": schema.List(schema.StringMap(schema.Any())),
+
+		"resource_records": schema.List(schema.StringMap(schema.Any())),
+	}
+	defaults := schema.Defaults{
+		"address_ttl":      nil,
+		"ip_addresses":     []interface{}{},
+		"resource_records": []interface{}{},
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "dns resource 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	var addressTTL *int
+	if value, ok := valid["address_ttl"].(int); ok {
+		addressTTL = &value
+	}
+
+	var ipAddresses []string
+	for i, value := range valid["ip_addresses"].([]interface{}) {
+		ip, ok := value.(map[string]interface{})["ip"].(string)
+		if !ok {
+			return nil, NewDeserializationError("missing ip for ip address %d", i)
+		}
+		ipAddresses = append(ipAddresses, ip)
+	}
+
+	resourceRecords, err := readDNSResourceRecordList(valid["resource_records"].([]interface{}), dnsResourceRecord_2_0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	result := &dnsResource{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:          valid["id"].(int),
+		fqdn:        valid["fqdn"].(string),
+		addressTTL:  addressTTL,
+		ipAddresses: ipAddresses,
+
+		resourceRecords: resourceRecords,
+	}
+	return result, nil
+}
@@ -0,0 +1,174 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type dnsResourceSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&dnsResourceSuite{})
+
+func (*dnsResourceSuite) TestReadDNSResourcesBadSchema(c *gc.C) {
+	_, err := readDNSResources(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `dns resource base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*dnsResourceSuite) TestReadDNSResources(c *gc.C) {
+	resources, err := readDNSResources(twoDotOh, parseJSON(c, dnsResourcesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 2)
+
+	resource := resources[0]
+	c.Check(resource.ID(), gc.Equals, 3)
+	c.Check(resource.FQDN(), gc.Equals, "ldap.maas")
+	c.Check(resource.AddressTTL(), gc.Equals, 0)
+	c.Check(resource.IPAddresses(), jc.DeepEquals, []string{"192.168.100.20", "2001:db8::20"})
+	records := resource.ResourceRecords()
+	c.Assert(records, gc.HasLen, 1)
+	c.Check(records[0].Type(), gc.Equals, DNSRecordTXT)
+	c.Check(records[0].Data(), gc.Equals, "directory")
+
+	resource = resources[1]
+	c.Check(resource.AddressTTL(), gc.Equals, 30)
+	c.Check(resource.IPAddresses(), gc.HasLen, 0)
+	c.Check(resource.ResourceRecords(), gc.HasLen, 0)
+}
+
+func (*dnsResourceSuite) TestReadDNSResourceMissingIP(c *gc.C) {
+	_, err := readDNSResource(twoDotOh, parseJSON(c, `{
+        "id": 3,
+        "fqdn": "ldap.maas",
+        "ip_addresses": [{"id": 12}],
+        "resource_uri": "/MAAS/api/2.0/dnsresources/3/"
+    }`))
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, "missing ip for ip address 0")
+}
+
+func (*dnsResourceSuite) TestLowVersion(c *gc.C) {
+	_, err := readDNSResources(version.MustParse("1.9.0"), parseJSON(c, dnsResourcesResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*dnsResourceSuite) TestHighVersion(c *gc.C) {
+	resources, err := readDNSResources(version.MustParse("2.1.9"), parseJSON(c, dnsResourcesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 2)
+}
+
+func (s *dnsResourceSuite) getServerAndResource(c *gc.C) (*SimpleTestServer, *dnsResource) {
+	server, ctrl := createTestServerController(c, s)
+	resources, err := readDNSResources(twoDotOh, parseJSON(c, dnsResourcesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	resources[0].controller = ctrl.(*controller)
+	return server, resources[0]
+}
+
+func (s *dnsResourceSuite) TestResourceRecordsHaveController(c *gc.C) {
+	_, resource := s.getServerAndResource(c)
+	records := resource.ResourceRecords()
+	c.Check(records[0].(*dnsResourceRecord).controller, gc.Equals, resource.controller)
+}
+
+func (s *dnsResourceSuite) TestUpdate(c *gc.C) {
+	server, resource := s.getServerAndResource(c)
+	response := updateJSONMap(c, dnsResourceResponse, map[string]interface{}{
+		"address_ttl":  300,
+		"ip_addresses": []interface{}{map[string]interface{}{"ip": "192.168.100.21"}},
+	})
+	server.AddPutResponse(resource.resourceURI, http.StatusOK, response)
+	err := resource.Update(UpdateDNSResourceArgs{
+		IPAddresses: []string{"192.168.100.21"},
+		AddressTTL:  300,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(resource.IPAddresses(), jc.DeepEquals, []string{"192.168.100.21"})
+	c.Check(resource.AddressTTL(), gc.Equals, 300)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("ip_addresses"), gc.Equals, "192.168.100.21")
+	c.Check(form.Get("address_ttl"), gc.Equals, "300")
+}
+
+func (s *dnsResourceSuite) TestUpdateNothing(c *gc.C) {
+	server, resource := s.getServerAndResource(c)
+	count := server.RequestCount()
+	err := resource.Update(UpdateDNSResourceArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *dnsResourceSuite) TestUpdateValidates(c *gc.C) {
+	_, resource := s.getServerAndResource(c)
+	err := resource.Update(UpdateDNSResourceArgs{AddressTTL: -5})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *dnsResourceSuite) TestUpdateBadRequest(c *gc.C) {
+	server, resource := s.getServerAndResource(c)
+	server.AddPutResponse(resource.resourceURI, http.StatusBadRequest, "fqdn in use")
+	err := resource.Update(UpdateDNSResourceArgs{FQDN: "www.maas"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "fqdn in use")
+}
+
+func (s *dnsResourceSuite) TestDelete(c *gc.C) {
+	server, resource := s.getServerAndResource(c)
+	server.AddDeleteResponse(resource.resourceURI, http.StatusNoContent, "")
+	err := resource.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *dnsResourceSuite) TestDeleteMissing(c *gc.C) {
+	_, resource := s.getServerAndResource(c)
+	err := resource.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+var dnsResourceResponse = `
+{
+    "id": 3,
+    "fqdn": "ldap.maas",
+    "address_ttl": null,
+    "ip_addresses": [
+        {"ip": "192.168.100.20", "alloc_type": 4, "resource_uri": "/MAAS/api/2.0/ipaddresses/"},
+        {"ip": "2001:db8::20", "alloc_type": 4, "resource_uri": "/MAAS/api/2.0/ipaddresses/"}
+    ],
+    "resource_records": [
+        {
+            "id": 11,
+            "fqdn": "ldap.maas",
+            "ttl": null,
+            "rrtype": "TXT",
+            "rrdata": "directory"
+        }
+    ],
+    "resource_uri": "/MAAS/api/2.0/dnsresources/3/"
+}
+`
+
+var dnsResourcesResponse = `
+[` + dnsResourceResponse + `,
+    {
+        "id": 4,
+        "fqdn": "www.maas",
+        "address_ttl": 30,
+        "ip_addresses": [],
+        "resource_records": [],
+        "resource_uri": "/MAAS/api/2.0/dnsresources/4/"
+    }
+]
+`
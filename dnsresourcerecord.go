@@ -0,0 +1,299 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+// DNSRecordType is the type of a DNS resource record.
+type DNSRecordType string
+
+const (
+	// DNSRecordA records map a name to an IPv4 address.
+	DNSRecordA DNSRecordType = "A"
+
+	// DNSRecordAAAA records map a name to an IPv6 address.
+	DNSRecordAAAA DNSRecordType = "AAAA"
+
+	// DNSRecordCNAME records make a name an alias of another name.
+	DNSRecordCNAME DNSRecordType = "CNAME"
+
+	// DNSRecordTXT records hold arbitrary text.
+	DNSRecordTXT DNSRecordType = "TXT"
+
+	// DNSRecordSRV records locate a service. The data has the form
+	// "<priority> <weight> <port> <target>".
+	DNSRecordSRV DNSRecordType = "SRV"
+
+	// DNSRecordMX records locate the mail exchanger of a domain. The data
+	// has the form "<preference> <exchange>".
+	DNSRecordMX DNSRecordType = "MX"
+)
+
+type dnsResourceRecord struct {
+	controller *controller
+
+	resourceURI string
+
+	id     int
+	fqdn   string
+	ttl    *int
+	rrtype DNSRecordType
+	rrdata string
+}
+
+func (r *dnsResourceRecord) updateFrom(other *dnsResourceRecord) {
+	r.resourceURI = other.resourceURI
+	r.id = other.id
+	r.fqdn = other.fqdn
+	r.ttl = other.ttl
+	r.rrtype = other.rrtype
+	r.rrdata = other.rrdata
+}
+
+// ID implements DNSResourceRecord.
+func (r *dnsResourceRecord) ID() int {
+	return r.id
+}
+
+// FQDN implements DNSResourceRecord.
+func (r *dnsResourceRecord) FQDN() string {
+	return r.fqdn
+}
+
+// TTL implements DNSResourceRecord.
+func (r *dnsResourceRecord) TTL() int {
+	if r.ttl == nil {
+		return 0
+	}
+	return *r.ttl
+}
+
+// Type implements DNSResourceRecord.
+func (r *dnsResourceRecord) Type() DNSRecordType {
+	return r.rrtype
+}
+
+// Data implements DNSResourceRecord.
+func (r *dnsResourceRecord) Data() string {
+	return r.rrdata
+}
+
+// UpdateDNSResourceRecordArgs is an argument struct for passing parameters
+// to the DNSResourceRecord.Update method. Only the values that are
+// specified are changed.
+type UpdateDNSResourceRecordArgs struct {
+	Type DNSRecordType
+	Data string
+	TTL  int
+}
+
+// Update implements DNSResourceRecord.
+func (r *dnsResourceRecord) Update(args UpdateDNSResourceRecordArgs) error {
+	if args.TTL < 0 {
+		return errors.NotValidf("negative TTL")
+	}
+	if args.Type != "" || args.Data != "" {
+		// The new data must make sense for the new type, so check
+		// whichever of them is not being changed too.
+		rrtype, rrdata := args.Type, args.Data
+		if rrtype == "" {
+			rrtype = r.rrtype
+		}
+		if rrdata == "" {
+			rrdata = r.rrdata
+		}
+		if err := validateDNSRecordData(rrtype, rrdata); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	params := NewURLParams()
+	params.MaybeAdd("rrtype", string(args.Type))
+	params.MaybeAdd("rrdata", args.Data)
+	params.MaybeAddInt("ttl", args.TTL)
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := r.controller.put(r.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readDNSResourceRecord(r.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.updateFrom(response)
+	return nil
+}
+
+// Delete implements DNSResourceRecord.
+func (r *dnsResourceRecord) Delete() error {
+	err := r.controller.delete(r.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// validateDNSRecord checks the type, data and TTL of a new record.
+func validateDNSRecord(rrtype DNSRecordType, rrdata string, ttl int) error {
+	if rrtype == "" {
+		return errors.NotValidf("missing Type")
+	}
+	if rrdata == "" {
+		return errors.NotValidf("missing Data")
+	}
+	if ttl < 0 {
+		return errors.NotValidf("negative TTL")
+	}
+	return validateDNSRecordData(rrtype, rrdata)
+}
+
+// validateDNSRecordData checks that the record data has the form that the
+// record type requires. Only the types with structured data are checked,
+// MAAS checks the rest.
+func validateDNSRecordData(rrtype DNSRecordType, rrdata string) error {
+	switch rrtype {
+	case DNSRecordA, DNSRecordAAAA:
+		return errors.NewNotValid(nil, fmt.Sprintf("%s records are managed through the IP addresses of a DNSResource", rrtype))
+	case DNSRecordMX:
+		fields := strings.Fields(rrdata)
+		if len(fields) != 2 || !isUint16(fields[0]) {
+			return errors.NotValidf("MX data %q", rrdata)
+		}
+	case DNSRecordSRV:
+		fields := strings.Fields(rrdata)
+		if len(fields) != 4 || !isUint16(fields[0]) || !isUint16(fields[1]) || !isUint16(fields[2]) {
+			return errors.NotValidf("SRV data %q", rrdata)
+		}
+	}
+	return nil
+}
+
+func isUint16(value string) bool {
+	_, err := strconv.ParseUint(value, 10, 16)
+	return err == nil
+}
+
+func readDNSResourceRecord(controllerVersion version.Number, source interface{}) (*dnsResourceRecord, error) {
+	readFunc, err := getDNSResourceRecordDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "dns resource record base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readDNSResourceRecords(controllerVersion version.Number, source interface{}) ([]*dnsResourceRecord, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "dns resource record base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getDNSResourceRecordDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readDNSResourceRecordList(valid, readFunc)
+}
+
+func getDNSResourceRecordDeserializationFunc(controllerVersion version.Number) (dnsResourceRecordDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range dnsResourceRecordDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no dns resource record read func for version %s", controllerVersion)
+	}
+	return dnsResourceRecordDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readDNSResourceRecordList expects the values of the sourceList to be
+// string maps.
+func readDNSResourceRecordList(sourceList []interface{}, readFunc dnsResourceRecordDeserializationFunc) ([]*dnsResourceRecord, error) {
+	result := make([]*dnsResourceRecord, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for dns resource record %d, %T", i, value)
+		}
+		record, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "dns resource record %d", i)
+		}
+		result = append(result, record)
+	}
+	return result, nil
+}
+
+type dnsResourceRecordDeserializationFunc func(map[string]interface{}) (*dnsResourceRecord, error)
+
+var dnsResourceRecordDeserializationFuncs = map[version.Number]dnsResourceRecordDeserializationFunc{
+	twoDotOh: dnsResourceRecord_2_0,
+}
+
+func dnsResourceRecord_2_0(source map[string]interface{}) (*dnsResourceRecord, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":     schema.ForceInt(),
+		"fqdn":   schema.String(),
+		"ttl":    schema.OneOf(schema.Nil(""), schema.ForceInt()),
+		"rrtype": schema.String(),
+		"rrdata": schema.String(),
+	}
+	defaults := schema.Defaults{
+		// The records nested in a dns resource have no resource uri.
+		"resource_uri": "",
+		"ttl":          nil,
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "dns resource record 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	id := valid["id"].(int)
+	resourceURI := valid["resource_uri"].(string)
+	if resourceURI == "" {
+		resourceURI = fmt.Sprintf("dnsresourcerecords/%d/", id)
+	}
+
+	var ttl *int
+	if value, ok := valid["ttl"].(int); ok {
+		ttl = &value
+	}
+
+	result := &dnsResourceRecord{
+		resourceURI: resourceURI,
+
+		id:     id,
+		fqdn:   valid["fqdn"].(string),
+		ttl:    ttl,
+		rrtype: DNSRecordType(valid["rrtype"].(string)),
+		rrdata: valid["rrdata"].(string),
+	}
+	return result, nil
+}
@@ -0,0 +1,189 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type dnsResourceRecordSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&dnsResourceRecordSuite{})
+
+func (*dnsResourceRecordSuite) TestReadDNSResourceRecordsBadSchema(c *gc.C) {
+	_, err := readDNSResourceRecords(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `dns resource record base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*dnsResourceRecordSuite) TestReadDNSResourceRecords(c *gc.C) {
+	records, err := readDNSResourceRecords(twoDotOh, parseJSON(c, dnsResourceRecordsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 2)
+
+	record := records[0]
+	c.Check(record.ID(), gc.Equals, 7)
+	c.Check(record.FQDN(), gc.Equals, "_ldap._tcp.maas")
+	c.Check(record.TTL(), gc.Equals, 0)
+	c.Check(record.Type(), gc.Equals, DNSRecordSRV)
+	c.Check(record.Data(), gc.Equals, "0 5 389 ldap.maas")
+	c.Check(record.resourceURI, gc.Equals, "/MAAS/api/2.0/dnsresourcerecords/7/")
+
+	record = records[1]
+	c.Check(record.TTL(), gc.Equals, 60)
+	c.Check(record.Type(), gc.Equals, DNSRecordCNAME)
+}
+
+func (*dnsResourceRecordSuite) TestReadNestedRecordHasResourceURI(c *gc.C) {
+	record, err := readDNSResourceRecord(twoDotOh, parseJSON(c, `{
+        "id": 9,
+        "fqdn": "mail.maas",
+        "ttl": null,
+        "rrtype": "TXT",
+        "rrdata": "hello"
+    }`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(record.resourceURI, gc.Equals, "dnsresourcerecords/9/")
+}
+
+func (*dnsResourceRecordSuite) TestLowVersion(c *gc.C) {
+	_, err := readDNSResourceRecords(version.MustParse("1.9.0"), parseJSON(c, dnsResourceRecordsResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*dnsResourceRecordSuite) TestHighVersion(c *gc.C) {
+	records, err := readDNSResourceRecords(version.MustParse("2.1.9"), parseJSON(c, dnsResourceRecordsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(records, gc.HasLen, 2)
+}
+
+func (*dnsResourceRecordSuite) TestValidateDNSRecordData(c *gc.C) {
+	for i, test := range []struct {
+		rrtype DNSRecordType
+		rrdata string
+		err    string
+	}{
+		{DNSRecordCNAME, "www.maas", ""},
+		{DNSRecordTXT, "v=spf1 -all", ""},
+		{DNSRecordMX, "10 mail.maas", ""},
+		{DNSRecordMX, "mail.maas", `MX data "mail.maas" not valid`},
+		{DNSRecordMX, "high mail.maas", `MX data "high mail.maas" not valid`},
+		{DNSRecordSRV, "0 5 389 ldap.maas", ""},
+		{DNSRecordSRV, "0 5 ldap.maas", `SRV data "0 5 ldap.maas" not valid`},
+		{DNSRecordSRV, "0 5 65536 ldap.maas", `SRV data "0 5 65536 ldap.maas" not valid`},
+		{DNSRecordA, "10.0.0.1", "A records are managed through the IP addresses of a DNSResource"},
+		{DNSRecordAAAA, "::1", "AAAA records are managed through the IP addresses of a DNSResource"},
+	} {
+		c.Logf("test %d: %s %q", i, test.rrtype, test.rrdata)
+		err := validateDNSRecordData(test.rrtype, test.rrdata)
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *dnsResourceRecordSuite) getServerAndRecord(c *gc.C) (*SimpleTestServer, *dnsResourceRecord) {
+	server, ctrl := createTestServerController(c, s)
+	records, err := readDNSResourceRecords(twoDotOh, parseJSON(c, dnsResourceRecordsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	records[0].controller = ctrl.(*controller)
+	return server, records[0]
+}
+
+func (s *dnsResourceRecordSuite) TestUpdate(c *gc.C) {
+	server, record := s.getServerAndRecord(c)
+	response := updateJSONMap(c, dnsResourceRecordResponse, map[string]interface{}{
+		"rrdata": "0 5 636 ldap.maas",
+		"ttl":    120,
+	})
+	server.AddPutResponse(record.resourceURI, http.StatusOK, response)
+	err := record.Update(UpdateDNSResourceRecordArgs{
+		Data: "0 5 636 ldap.maas",
+		TTL:  120,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(record.Data(), gc.Equals, "0 5 636 ldap.maas")
+	c.Check(record.TTL(), gc.Equals, 120)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("rrdata"), gc.Equals, "0 5 636 ldap.maas")
+	c.Check(form.Get("ttl"), gc.Equals, "120")
+}
+
+func (s *dnsResourceRecordSuite) TestUpdateNothing(c *gc.C) {
+	server, record := s.getServerAndRecord(c)
+	count := server.RequestCount()
+	err := record.Update(UpdateDNSResourceRecordArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *dnsResourceRecordSuite) TestUpdateChecksDataAgainstType(c *gc.C) {
+	_, record := s.getServerAndRecord(c)
+	// The record is an SRV record, so the data needs a port.
+	err := record.Update(UpdateDNSResourceRecordArgs{Data: "ldap.maas"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, `SRV data "ldap.maas" not valid`)
+
+	// Changing the type checks the current data against the new type.
+	err = record.Update(UpdateDNSResourceRecordArgs{Type: DNSRecordMX})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *dnsResourceRecordSuite) TestUpdateBadRequest(c *gc.C) {
+	server, record := s.getServerAndRecord(c)
+	server.AddPutResponse(record.resourceURI, http.StatusBadRequest, "bad data")
+	err := record.Update(UpdateDNSResourceRecordArgs{Type: DNSRecordTXT})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "bad data")
+}
+
+func (s *dnsResourceRecordSuite) TestDelete(c *gc.C) {
+	server, record := s.getServerAndRecord(c)
+	server.AddDeleteResponse(record.resourceURI, http.StatusNoContent, "")
+	err := record.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *dnsResourceRecordSuite) TestDeleteMissing(c *gc.C) {
+	_, record := s.getServerAndRecord(c)
+	err := record.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+var dnsResourceRecordResponse = `
+{
+    "id": 7,
+    "fqdn": "_ldap._tcp.maas",
+    "ttl": null,
+    "rrtype": "SRV",
+    "rrdata": "0 5 389 ldap.maas",
+    "resource_uri": "/MAAS/api/2.0/dnsresourcerecords/7/"
+}
+`
+
+var dnsResourceRecordsResponse = `
+[` + dnsResourceRecordResponse + `,
+    {
+        "id": 8,
+        "fqdn": "www.maas",
+        "ttl": 60,
+        "rrtype": "CNAME",
+        "rrdata": "web.maas",
+        "resource_uri": "/MAAS/api/2.0/dnsresourcerecords/8/"
+    }
+]
+`
@@ -4,13 +4,18 @@
 package gomaasapi
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/version"
 )
 
 type domain struct {
+	controller *controller
+
 	authoritative       bool
+	isDefault           bool
 	resourceRecordCount int
 	ttl                 *int
 	resourceURI         string
@@ -18,11 +23,129 @@ type domain struct {
 	name                string
 }
 
+func (domain *domain) updateFrom(other *domain) {
+	domain.authoritative = other.authoritative
+	domain.isDefault = other.isDefault
+	domain.resourceRecordCount = other.resourceRecordCount
+	domain.ttl = other.ttl
+	domain.resourceURI = other.resourceURI
+	domain.id = other.id
+	domain.name = other.name
+}
+
+// ID implements Domain interface
+func (domain *domain) ID() int {
+	return domain.id
+}
+
 // Name implements Domain interface
 func (domain *domain) Name() string {
 	return domain.name
 }
 
+// TTL implements Domain interface
+func (domain *domain) TTL() int {
+	if domain.ttl == nil {
+		return 0
+	}
+	return *domain.ttl
+}
+
+// Authoritative implements Domain interface
+func (domain *domain) Authoritative() bool {
+	return domain.authoritative
+}
+
+// IsDefault implements Domain interface
+func (domain *domain) IsDefault() bool {
+	return domain.isDefault
+}
+
+// ResourceRecordCount implements Domain interface
+func (domain *domain) ResourceRecordCount() int {
+	return domain.resourceRecordCount
+}
+
+// UpdateDomainArgs is an argument struct for passing parameters to the
+// Domain.Update method. Only the values that are specified are changed.
+type UpdateDomainArgs struct {
+	Name string
+	// Authoritative is a pointer so that a domain can be made
+	// non-authoritative.
+	Authoritative *bool
+	// TTL is the default TTL in seconds of the records in the domain.
+	TTL int
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *UpdateDomainArgs) Validate() error {
+	if a.TTL < 0 {
+		return errors.NotValidf("negative TTL")
+	}
+	return nil
+}
+
+// Update implements Domain interface
+func (domain *domain) Update(args UpdateDomainArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	if args.Authoritative != nil {
+		params.Values.Add("authoritative", fmt.Sprint(*args.Authoritative))
+	}
+	params.MaybeAddInt("ttl", args.TTL)
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := domain.controller.put(domain.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readDomain(domain.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	domain.updateFrom(response)
+	return nil
+}
+
+// Delete implements Domain interface
+func (domain *domain) Delete() error {
+	err := domain.controller.delete(domain.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// SetDefault implements Domain interface
+func (domain *domain) SetDefault() error {
+	source, err := domain.controller.post(domain.resourceURI, "set_default", nil)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readDomain(domain.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	domain.updateFrom(response)
+	return nil
+}
+
+func readDomain(controllerVersion version.Number, source interface{}) (*domain, error) {
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "domain base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return domain_(valid)
+}
+
 func readDomains(controllerVersion version.Number, source interface{}) ([]*domain, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -36,13 +159,18 @@ func readDomains(controllerVersion version.Number, source interface{}) ([]*domai
 func domain_(source map[string]interface{}) (*domain, error) {
 	fields := schema.Fields{
 		"authoritative":         schema.Bool(),
+		"is_default":            schema.Bool(),
 		"resource_record_count": schema.ForceInt(),
 		"ttl":                   schema.OneOf(schema.Nil("null"), schema.ForceInt()),
 		"resource_uri":          schema.String(),
 		"id":                    schema.ForceInt(),
 		"name":                  schema.String(),
 	}
-	checker := schema.FieldMap(fields, nil) // no defaults
+	defaults := schema.Defaults{
+		// Only MAAS 2.5 and later say which domain is the default.
+		"is_default": false,
+	}
+	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(source, nil)
 	if err != nil {
 		return nil, errors.Annotatef(err, "domain schema check failed")
@@ -57,6 +185,7 @@ func domain_(source map[string]interface{}) (*domain, error) {
 
 	result := &domain{
 		authoritative:       valid["authoritative"].(bool),
+		isDefault:           valid["is_default"].(bool),
 		id:                  valid["id"].(int),
 		name:                valid["name"].(string),
 		resourceRecordCount: valid["resource_record_count"].(int),
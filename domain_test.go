@@ -4,11 +4,17 @@
 package gomaasapi
 
 import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 )
 
-type domainSuite struct{}
+type domainSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&domainSuite{})
 
@@ -25,6 +31,133 @@ func (*domainSuite) TestReadDomains(c *gc.C) {
 	c.Assert(domains[1].Name(), gc.Equals, "anotherDomain.com")
 }
 
+func (*domainSuite) TestReadDomainsFields(c *gc.C) {
+	domains, err := readDomains(twoDotOh, parseJSON(c, domainResponse))
+	c.Assert(err, jc.ErrorIsNil)
+
+	maas := domains[0]
+	c.Check(maas.ID(), gc.Equals, 0)
+	c.Check(maas.TTL(), gc.Equals, 0)
+	c.Check(maas.Authoritative(), jc.IsTrue)
+	c.Check(maas.IsDefault(), jc.IsTrue)
+	c.Check(maas.ResourceRecordCount(), gc.Equals, 3)
+
+	another := domains[1]
+	c.Check(another.ID(), gc.Equals, 1)
+	c.Check(another.TTL(), gc.Equals, 10)
+	c.Check(another.IsDefault(), jc.IsFalse)
+}
+
+func (*domainSuite) TestReadDomainBadSchema(c *gc.C) {
+	_, err := readDomain(twoDotOh, "something")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `domain base schema check failed: expected map, got string("something")`)
+}
+
+func (s *domainSuite) getServerAndDomain(c *gc.C) (*SimpleTestServer, *domain) {
+	server, ctrl := createTestServerController(c, s)
+	domains, err := readDomains(twoDotOh, parseJSON(c, domainResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	domains[1].controller = ctrl.(*controller)
+	return server, domains[1]
+}
+
+func (s *domainSuite) TestUpdate(c *gc.C) {
+	server, domain := s.getServerAndDomain(c)
+	server.AddPutResponse(domain.resourceURI, http.StatusOK, `{
+        "authoritative": false,
+        "resource_uri": "/MAAS/api/2.0/domains/1/",
+        "name": "example.com",
+        "id": 1,
+        "ttl": 300,
+        "is_default": false,
+        "resource_record_count": 3
+    }`)
+	authoritative := false
+	err := domain.Update(UpdateDomainArgs{
+		Name:          "example.com",
+		Authoritative: &authoritative,
+		TTL:           300,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(domain.Name(), gc.Equals, "example.com")
+	c.Check(domain.Authoritative(), jc.IsFalse)
+	c.Check(domain.TTL(), gc.Equals, 300)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 3)
+	c.Check(form.Get("name"), gc.Equals, "example.com")
+	c.Check(form.Get("authoritative"), gc.Equals, "false")
+	c.Check(form.Get("ttl"), gc.Equals, "300")
+}
+
+func (s *domainSuite) TestUpdateNothing(c *gc.C) {
+	server, domain := s.getServerAndDomain(c)
+	count := server.RequestCount()
+	err := domain.Update(UpdateDomainArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *domainSuite) TestUpdateValidates(c *gc.C) {
+	_, domain := s.getServerAndDomain(c)
+	err := domain.Update(UpdateDomainArgs{TTL: -1})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "negative TTL not valid")
+}
+
+func (s *domainSuite) TestUpdateBadRequest(c *gc.C) {
+	server, domain := s.getServerAndDomain(c)
+	server.AddPutResponse(domain.resourceURI, http.StatusBadRequest, "name in use")
+	err := domain.Update(UpdateDomainArgs{Name: "maas"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "name in use")
+}
+
+func (s *domainSuite) TestDelete(c *gc.C) {
+	server, domain := s.getServerAndDomain(c)
+	server.AddDeleteResponse(domain.resourceURI, http.StatusNoContent, "")
+	err := domain.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *domainSuite) TestDeleteMissing(c *gc.C) {
+	_, domain := s.getServerAndDomain(c)
+	err := domain.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *domainSuite) TestSetDefault(c *gc.C) {
+	server, domain := s.getServerAndDomain(c)
+	response := updateJSONMap(c, domainItemResponse, map[string]interface{}{
+		"is_default": true,
+	})
+	server.AddPostResponse(domain.resourceURI+"?op=set_default", http.StatusOK, response)
+	err := domain.SetDefault()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(domain.IsDefault(), jc.IsTrue)
+}
+
+func (s *domainSuite) TestSetDefaultForbidden(c *gc.C) {
+	server, domain := s.getServerAndDomain(c)
+	server.AddPostResponse(domain.resourceURI+"?op=set_default", http.StatusForbidden, "admins only")
+	err := domain.SetDefault()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+	c.Check(domain.IsDefault(), jc.IsFalse)
+}
+
+var domainItemResponse = `
+{
+    "authoritative": true,
+    "resource_uri": "/MAAS/api/2.0/domains/1/",
+    "name": "anotherDomain.com",
+    "id": 1,
+    "ttl": 10,
+    "is_default": false,
+    "resource_record_count": 3
+}
+`
+
 var domainResponse = `
 [
     {
@@ -33,6 +166,7 @@ var domainResponse = `
         "name": "maas",
         "id": 0,
         "ttl": null,
+        "is_default": true,
         "resource_record_count": 3
     }, {
         "authoritative": "true",
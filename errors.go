@@ -5,6 +5,7 @@ package gomaasapi
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/juju/errors"
 )
@@ -167,3 +168,21 @@ func IsCannotCompleteError(err error) bool {
 	_, ok := errors.Cause(err).(*CannotCompleteError)
 	return ok
 }
+
+// translateServerError maps the HTTP status codes that MAAS uses when an
+// operation on a resource fails to the errors defined by this package.
+func translateServerError(err error) error {
+	if svrErr, ok := errors.Cause(err).(ServerError); ok {
+		switch svrErr.StatusCode {
+		case http.StatusBadRequest:
+			return errors.Wrap(err, NewBadRequestError(svrErr.BodyMessage))
+		case http.StatusNotFound:
+			return errors.Wrap(err, NewNoMatchError(svrErr.BodyMessage))
+		case http.StatusForbidden:
+			return errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
+		case http.StatusConflict:
+			return errors.Wrap(err, NewCannotCompleteError(svrErr.BodyMessage))
+		}
+	}
+	return NewUnexpectedError(err)
+}
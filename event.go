@@ -0,0 +1,238 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type event struct {
+	id          int
+	systemID    string
+	hostname    string
+	username    string
+	level       string
+	type_       string
+	description string
+	created     string
+}
+
+// ID implements Event.
+func (e *event) ID() int {
+	return e.id
+}
+
+// SystemID implements Event.
+func (e *event) SystemID() string {
+	return e.systemID
+}
+
+// Hostname implements Event.
+func (e *event) Hostname() string {
+	return e.hostname
+}
+
+// Username implements Event.
+func (e *event) Username() string {
+	return e.username
+}
+
+// Level implements Event.
+func (e *event) Level() string {
+	return e.level
+}
+
+// Type implements Event.
+func (e *event) Type() string {
+	return e.type_
+}
+
+// Description implements Event.
+func (e *event) Description() string {
+	return e.description
+}
+
+// Created implements Event.
+func (e *event) Created() string {
+	return e.created
+}
+
+// eventPage is the result of an events query. The events are ordered from
+// newest to oldest. The previous page has older events, and the next page
+// has newer events.
+type eventPage struct {
+	events  []*event
+	prevURI string
+	nextURI string
+}
+
+// eventIterator implements EventIterator by following the previous page
+// URIs of the event pages.
+type eventIterator struct {
+	controller *controller
+	params     url.Values
+
+	prevURI string
+	started bool
+	done    bool
+	events  []Event
+	err     error
+}
+
+// Next implements EventIterator.
+func (it *eventIterator) Next() bool {
+	if it.done || it.err != nil {
+		return false
+	}
+	var page *eventPage
+	if !it.started {
+		it.started = true
+		page, it.err = it.controller.queryEvents(it.params)
+	} else {
+		page, it.err = it.controller.getEventPage(it.prevURI)
+	}
+	if it.err != nil {
+		it.events = nil
+		return false
+	}
+	it.events = nil
+	for _, e := range page.events {
+		it.events = append(it.events, e)
+	}
+	it.prevURI = page.prevURI
+	if len(it.events) == 0 {
+		it.done = true
+		return false
+	}
+	// Without a link to older events, this is the last page.
+	it.done = it.prevURI == ""
+	return true
+}
+
+// Events implements EventIterator.
+func (it *eventIterator) Events() []Event {
+	return it.events
+}
+
+// Err implements EventIterator.
+func (it *eventIterator) Err() error {
+	return it.err
+}
+
+func readEventPage(controllerVersion version.Number, source interface{}) (*eventPage, error) {
+	readFunc, err := getEventDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	fields := schema.Fields{
+		"events":   schema.List(schema.StringMap(schema.Any())),
+		"prev_uri": schema.OneOf(schema.Nil(""), schema.String()),
+		"next_uri": schema.OneOf(schema.Nil(""), schema.String()),
+	}
+	defaults := schema.Defaults{
+		"prev_uri": "",
+		"next_uri": "",
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "event page schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+
+	events, err := readEventList(valid["events"].([]interface{}), readFunc)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	prevURI, _ := valid["prev_uri"].(string)
+	nextURI, _ := valid["next_uri"].(string)
+	return &eventPage{
+		events:  events,
+		prevURI: prevURI,
+		nextURI: nextURI,
+	}, nil
+}
+
+func getEventDeserializationFunc(controllerVersion version.Number) (eventDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range eventDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no event read func for version %s", controllerVersion)
+	}
+	return eventDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readEventList expects the values of the sourceList to be string maps.
+func readEventList(sourceList []interface{}, readFunc eventDeserializationFunc) ([]*event, error) {
+	result := make([]*event, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for event %d, %T", i, value)
+		}
+		event, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "event %d", i)
+		}
+		result = append(result, event)
+	}
+	return result, nil
+}
+
+type eventDeserializationFunc func(map[string]interface{}) (*event, error)
+
+var eventDeserializationFuncs = map[version.Number]eventDeserializationFunc{
+	twoDotOh: event_2_0,
+}
+
+func event_2_0(source map[string]interface{}) (*event, error) {
+	fields := schema.Fields{
+		"id":          schema.ForceInt(),
+		"node":        schema.OneOf(schema.Nil(""), schema.String()),
+		"hostname":    schema.OneOf(schema.Nil(""), schema.String()),
+		"username":    schema.OneOf(schema.Nil(""), schema.String()),
+		"level":       schema.String(),
+		"type":        schema.String(),
+		"description": schema.String(),
+		"created":     schema.String(),
+	}
+	defaults := schema.Defaults{
+		"node":        "",
+		"hostname":    "",
+		"username":    "",
+		"description": "",
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "event 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	systemID, _ := valid["node"].(string)
+	hostname, _ := valid["hostname"].(string)
+	username, _ := valid["username"].(string)
+	result := &event{
+		id:          valid["id"].(int),
+		systemID:    systemID,
+		hostname:    hostname,
+		username:    username,
+		level:       valid["level"].(string),
+		type_:       valid["type"].(string),
+		description: valid["description"].(string),
+		created:     valid["created"].(string),
+	}
+	return result, nil
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type eventSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&eventSuite{})
+
+func (*eventSuite) TestReadEventPageBadSchema(c *gc.C) {
+	_, err := readEventPage(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `event page schema check failed: expected map, got string("wat?")`)
+}
+
+func (*eventSuite) TestReadEventPage(c *gc.C) {
+	page, err := readEventPage(twoDotOh, parseJSON(c, eventsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(page.prevURI, gc.Equals, "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=2&before=4")
+	c.Check(page.nextURI, gc.Equals, "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=2&after=5")
+	c.Assert(page.events, gc.HasLen, 2)
+
+	event := page.events[0]
+	c.Check(event.ID(), gc.Equals, 5)
+	c.Check(event.SystemID(), gc.Equals, "4y3ha6")
+	c.Check(event.Hostname(), gc.Equals, "untasted-markita")
+	c.Check(event.Username(), gc.Equals, "admin")
+	c.Check(event.Level(), gc.Equals, "INFO")
+	c.Check(event.Type(), gc.Equals, "Deploying")
+	c.Check(event.Description(), gc.Equals, "")
+	c.Check(event.Created(), gc.Equals, "Tue, 11 Oct. 2022 10:12:31")
+
+	event = page.events[1]
+	c.Check(event.ID(), gc.Equals, 4)
+	c.Check(event.SystemID(), gc.Equals, "")
+	c.Check(event.Hostname(), gc.Equals, "")
+	c.Check(event.Username(), gc.Equals, "")
+	c.Check(event.Level(), gc.Equals, "WARNING")
+	c.Check(event.Description(), gc.Equals, "Image import failed")
+}
+
+func (*eventSuite) TestReadEventPageBadEvent(c *gc.C) {
+	_, err := readEventPage(twoDotOh, parseJSON(c, `{"events": [{"id": 3}]}`))
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err, gc.ErrorMatches, `event 0: event 2.0 schema check failed: .*`)
+}
+
+func (*eventSuite) TestLowVersion(c *gc.C) {
+	_, err := readEventPage(version.MustParse("1.9.0"), parseJSON(c, eventsResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*eventSuite) TestHighVersion(c *gc.C) {
+	page, err := readEventPage(version.MustParse("2.1.9"), parseJSON(c, eventsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(page.events, gc.HasLen, 2)
+}
+
+const (
+	eventsResponse = `
+{
+    "count": 2,
+    "events": [
+        {
+            "username": "admin",
+            "node": "4y3ha6",
+            "hostname": "untasted-markita",
+            "id": 5,
+            "level": "INFO",
+            "created": "Tue, 11 Oct. 2022 10:12:31",
+            "type": "Deploying",
+            "description": ""
+        },
+        {
+            "username": null,
+            "node": null,
+            "hostname": null,
+            "id": 4,
+            "level": "WARNING",
+            "created": "Tue, 11 Oct. 2022 10:02:17",
+            "type": "Failed to import images",
+            "description": "Image import failed"
+        }
+    ],
+    "next_uri": "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=2&after=5",
+    "prev_uri": "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=2&before=4"
+}
+`
+	olderEventsResponse = `
+{
+    "count": 1,
+    "events": [
+        {
+            "username": "admin",
+            "node": "4y3ha6",
+            "hostname": "untasted-markita",
+            "id": 2,
+            "level": "INFO",
+            "created": "Tue, 11 Oct. 2022 09:58:02",
+            "type": "Commissioning",
+            "description": ""
+        }
+    ],
+    "next_uri": "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=2&after=2",
+    "prev_uri": "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=2&before=2"
+}
+`
+	noEventsResponse = `
+{
+    "count": 0,
+    "events": [],
+    "next_uri": "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=2",
+    "prev_uri": "http://192.168.100.2:5240/MAAS/api/2.0/events/?op=query&limit=2"
+}
+`
+)
@@ -4,14 +4,15 @@
 package gomaasapi
 
 import (
+	"fmt"
+
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/version"
 )
 
 type fabric struct {
-	// Add the controller in when we need to do things with the fabric.
-	// controller Controller
+	controller *controller
 
 	resourceURI string
 
@@ -22,6 +23,14 @@ type fabric struct {
 	vlans []*vlan
 }
 
+func (f *fabric) updateFrom(other *fabric) {
+	f.resourceURI = other.resourceURI
+	f.id = other.id
+	f.name = other.name
+	f.classType = other.classType
+	f.vlans = other.vlans
+}
+
 // ID implements Fabric.
 func (f *fabric) ID() int {
 	return f.id
@@ -41,11 +50,98 @@ func (f *fabric) ClassType() string {
 func (f *fabric) VLANs() []VLAN {
 	var result []VLAN
 	for _, v := range f.vlans {
+		v.controller = f.controller
 		result = append(result, v)
 	}
 	return result
 }
 
+// UpdateFabricArgs is an argument struct for passing parameters to the
+// Fabric.Update method. Only the values that are specified are changed.
+type UpdateFabricArgs struct {
+	Name string
+	// Description and ClassType replace the existing values when not nil.
+	// An empty string removes the value.
+	Description *string
+	ClassType   *string
+}
+
+// Update implements Fabric.
+func (f *fabric) Update(args UpdateFabricArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	if args.Description != nil {
+		params.Values.Add("description", *args.Description)
+	}
+	if args.ClassType != nil {
+		params.Values.Add("class_type", *args.ClassType)
+	}
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := f.controller.put(f.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readFabric(f.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	f.updateFrom(response)
+	return nil
+}
+
+// Delete implements Fabric.
+func (f *fabric) Delete() error {
+	err := f.controller.delete(f.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// CreateVLAN implements Fabric.
+func (f *fabric) CreateVLAN(vid int, name string, mtu int) (VLAN, error) {
+	if vid < 1 || vid > 4094 {
+		return nil, errors.NotValidf("VID %d", vid)
+	}
+	if mtu < 0 {
+		return nil, errors.NotValidf("negative MTU")
+	}
+	params := NewURLParams()
+	params.Values.Add("vid", fmt.Sprint(vid))
+	params.MaybeAdd("name", name)
+	params.MaybeAddInt("mtu", mtu)
+	source, err := f.controller.post(EnsureTrailingSlash(f.resourceURI)+"vlans/", "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	vlan, err := readVLAN(f.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	vlan.controller = f.controller
+	f.vlans = append(f.vlans, vlan)
+	return vlan, nil
+}
+
+func readFabric(controllerVersion version.Number, source interface{}) (*fabric, error) {
+	readFunc, err := getFabricDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "fabric base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readFabrics(controllerVersion version.Number, source interface{}) ([]*fabric, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -54,6 +150,14 @@ func readFabrics(controllerVersion version.Number, source interface{}) ([]*fabri
 	}
 	valid := coerced.([]interface{})
 
+	readFunc, err := getFabricDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readFabricList(valid, readFunc)
+}
+
+func getFabricDeserializationFunc(controllerVersion version.Number) (fabricDeserializationFunc, error) {
 	var deserialisationVersion version.Number
 	for v := range fabricDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
@@ -63,8 +167,7 @@ func readFabrics(controllerVersion version.Number, source interface{}) ([]*fabri
 	if deserialisationVersion == version.Zero {
 		return nil, errors.Errorf("no fabric read func for version %s", controllerVersion)
 	}
-	readFunc := fabricDeserializationFuncs[deserialisationVersion]
-	return readFabricList(valid, readFunc)
+	return fabricDeserializationFuncs[deserialisationVersion], nil
 }
 
 // readFabricList expects the values of the sourceList to be string maps.
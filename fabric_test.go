@@ -4,12 +4,20 @@
 package gomaasapi
 
 import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 )
 
-type fabricSuite struct{}
+type fabricSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&fabricSuite{})
 
@@ -43,6 +51,155 @@ func (*fabricSuite) TestHighVersion(c *gc.C) {
 	c.Assert(fabrics, gc.HasLen, 2)
 }
 
+func (s *fabricSuite) getServerAndFabric(c *gc.C) (*SimpleTestServer, *fabric) {
+	server, ctrl := createTestServerController(c, s)
+	fabric, err := readFabric(twoDotOh, parseJSON(c, fabricItemResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	fabric.controller = ctrl.(*controller)
+	return server, fabric
+}
+
+func (s *fabricSuite) TestVLANsHaveController(c *gc.C) {
+	_, fabric := s.getServerAndFabric(c)
+	vlans := fabric.VLANs()
+	c.Assert(vlans, gc.HasLen, 1)
+	c.Assert(vlans[0].(*vlan).controller, gc.Equals, fabric.controller)
+}
+
+func (s *fabricSuite) TestUpdate(c *gc.C) {
+	server, fabric := s.getServerAndFabric(c)
+	response := updateJSONMap(c, fabricItemResponse, map[string]interface{}{
+		"name":       "london",
+		"class_type": "10g",
+	})
+	server.AddPutResponse(fabric.resourceURI, http.StatusOK, response)
+	description, classType := "London DC", "10g"
+	err := fabric.Update(UpdateFabricArgs{
+		Name:        "london",
+		Description: &description,
+		ClassType:   &classType,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fabric.Name(), gc.Equals, "london")
+	c.Check(fabric.ClassType(), gc.Equals, "10g")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 3)
+	c.Check(form.Get("name"), gc.Equals, "london")
+	c.Check(form.Get("description"), gc.Equals, "London DC")
+	c.Check(form.Get("class_type"), gc.Equals, "10g")
+}
+
+func (s *fabricSuite) TestUpdateClear(c *gc.C) {
+	server, fabric := s.getServerAndFabric(c)
+	response := updateJSONMap(c, fabricItemResponse, map[string]interface{}{
+		"class_type": nil,
+	})
+	server.AddPutResponse(fabric.resourceURI, http.StatusOK, response)
+	empty := ""
+	err := fabric.Update(UpdateFabricArgs{
+		Description: &empty,
+		ClassType:   &empty,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(fabric.ClassType(), gc.Equals, "")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{
+		"description": {""},
+		"class_type":  {""},
+	})
+}
+
+func (s *fabricSuite) TestUpdateNothing(c *gc.C) {
+	server, fabric := s.getServerAndFabric(c)
+	count := server.RequestCount()
+	err := fabric.Update(UpdateFabricArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *fabricSuite) TestUpdateBadRequest(c *gc.C) {
+	server, fabric := s.getServerAndFabric(c)
+	server.AddPutResponse(fabric.resourceURI, http.StatusBadRequest, "name in use")
+	err := fabric.Update(UpdateFabricArgs{Name: "fabric-0"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "name in use")
+}
+
+func (s *fabricSuite) TestDelete(c *gc.C) {
+	server, fabric := s.getServerAndFabric(c)
+	server.AddDeleteResponse(fabric.resourceURI, http.StatusNoContent, "")
+	err := fabric.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *fabricSuite) TestDeleteMissing(c *gc.C) {
+	_, fabric := s.getServerAndFabric(c)
+	err := fabric.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *fabricSuite) TestCreateVLANValidates(c *gc.C) {
+	_, fabric := s.getServerAndFabric(c)
+	for i, test := range []struct {
+		vid     int
+		mtu     int
+		errText string
+	}{
+		{vid: 0, errText: "VID 0 not valid"},
+		{vid: 4095, errText: "VID 4095 not valid"},
+		{vid: 10, mtu: -1, errText: "negative MTU not valid"},
+	} {
+		c.Logf("test %d", i)
+		_, err := fabric.CreateVLAN(test.vid, "", test.mtu)
+		c.Check(err, jc.Satisfies, errors.IsNotValid)
+		c.Check(err.Error(), gc.Equals, test.errText)
+	}
+}
+
+func (s *fabricSuite) TestCreateVLAN(c *gc.C) {
+	server, fabric := s.getServerAndFabric(c)
+	response := updateJSONMap(c, vlanItemResponse, map[string]interface{}{
+		"id":   5010,
+		"vid":  10,
+		"name": "storage",
+		"mtu":  9000,
+	})
+	server.AddPostResponse(fabric.resourceURI+"vlans/?op=", http.StatusOK, response)
+	result, err := fabric.CreateVLAN(10, "storage", 9000)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.ID(), gc.Equals, 5010)
+	c.Check(result.VID(), gc.Equals, 10)
+	c.Check(result.Name(), gc.Equals, "storage")
+	c.Check(result.MTU(), gc.Equals, 9000)
+	c.Check(result.(*vlan).controller, gc.Equals, fabric.controller)
+	c.Check(fabric.VLANs(), gc.HasLen, 2)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 3)
+	c.Check(form.Get("vid"), gc.Equals, "10")
+	c.Check(form.Get("name"), gc.Equals, "storage")
+	c.Check(form.Get("mtu"), gc.Equals, "9000")
+}
+
+func (s *fabricSuite) TestCreateVLANNoTrailingSlash(c *gc.C) {
+	server, fabric := s.getServerAndFabric(c)
+	server.AddPostResponse(fabric.resourceURI+"vlans/?op=", http.StatusOK, vlanItemResponse)
+	fabric.resourceURI = strings.TrimSuffix(fabric.resourceURI, "/")
+	_, err := fabric.CreateVLAN(10, "", 0)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *fabricSuite) TestCreateVLANBadRequest(c *gc.C) {
+	server, fabric := s.getServerAndFabric(c)
+	server.AddPostResponse(fabric.resourceURI+"vlans/?op=", http.StatusBadRequest, "vid in use")
+	_, err := fabric.CreateVLAN(10, "", 0)
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "vid in use")
+	c.Check(fabric.VLANs(), gc.HasLen, 1)
+}
+
 var fabricResponse = `
 [
     {
@@ -85,3 +242,13 @@ var fabricResponse = `
     }
 ]
 `
+
+var fabricItemResponse = `
+{
+    "name": "fabric-1",
+    "id": 1,
+    "class_type": null,
+    "vlans": [` + vlanItemResponse + `],
+    "resource_uri": "/MAAS/api/2.0/fabrics/1/"
+}
+`
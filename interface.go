@@ -6,6 +6,7 @@ package gomaasapi
 import (
 	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/juju/errors"
 	"github.com/juju/schema"
@@ -32,6 +33,8 @@ type interface_ struct {
 
 	parents  []string
 	children []string
+
+	params InterfaceParams
 }
 
 func (i *interface_) updateFrom(other *interface_) {
@@ -47,6 +50,7 @@ func (i *interface_) updateFrom(other *interface_) {
 	i.effectiveMTU = other.effectiveMTU
 	i.parents = other.parents
 	i.children = other.children
+	i.params = other.params
 }
 
 // ID implements Interface.
@@ -89,14 +93,16 @@ func (i *interface_) VLAN() VLAN {
 	if i.vlan == nil {
 		return nil
 	}
+	i.vlan.controller = i.controller
 	return i.vlan
 }
 
 // Links implements Interface.
 func (i *interface_) Links() []Link {
 	result := make([]Link, len(i.links))
-	for i, link := range i.links {
-		result[i] = link
+	for index, link := range i.links {
+		link.controller = i.controller
+		result[index] = link
 	}
 	return result
 }
@@ -111,11 +117,74 @@ func (i *interface_) EffectiveMTU() int {
 	return i.effectiveMTU
 }
 
+// Params implements Interface.
+func (i *interface_) Params() InterfaceParams {
+	return i.params
+}
+
+// InterfaceParams holds the configuration of an interface that MAAS keeps
+// in the params field. The bond and bridge values are only set for
+// interfaces of those types.
+type InterfaceParams struct {
+	// MTU - Maximum transmission unit.
+	MTU int
+	// AcceptRA - Accept router advertisements. (IPv6 only)
+	AcceptRA bool
+	// Autoconf - Perform stateless autoconfiguration. (IPv6 only)
+	Autoconf bool
+
+	BondMode       BondMode
+	BondMIIMon     int
+	BondDownDelay  int
+	BondUpDelay    int
+	BondLACPRate   BondLACPRate
+	BondHashPolicy BondHashPolicy
+	// BondNumGratARP is the number of gratuitous ARPs to send after a
+	// failover.
+	BondNumGratARP int
+
+	BridgeType string
+	BridgeSTP  bool
+	// BridgeForwardDelay is in seconds.
+	BridgeForwardDelay int
+}
+
+// addTo adds all the values to the params. The booleans are always added
+// so that they can be turned off.
+func (p *InterfaceParams) addTo(params *URLParams) {
+	params.MaybeAddInt("mtu", p.MTU)
+	params.Values.Add("accept_ra", fmt.Sprint(p.AcceptRA))
+	params.Values.Add("autoconf", fmt.Sprint(p.Autoconf))
+	params.MaybeAdd("bond_mode", string(p.BondMode))
+	params.MaybeAddInt("bond_miimon", p.BondMIIMon)
+	params.MaybeAddInt("bond_downdelay", p.BondDownDelay)
+	params.MaybeAddInt("bond_updelay", p.BondUpDelay)
+	params.MaybeAdd("bond_lacp_rate", string(p.BondLACPRate))
+	params.MaybeAdd("bond_xmit_hash_policy", string(p.BondHashPolicy))
+	params.MaybeAddInt("bond_num_grat_arp", p.BondNumGratARP)
+	params.MaybeAdd("bridge_type", p.BridgeType)
+	params.Values.Add("bridge_stp", fmt.Sprint(p.BridgeSTP))
+	params.MaybeAddInt("bridge_fd", p.BridgeForwardDelay)
+}
+
 // UpdateInterfaceArgs is an argument struct for calling Interface.Update.
+// Only the values that are specified are changed.
 type UpdateInterfaceArgs struct {
 	Name       string
 	MACAddress string
 	VLAN       VLAN
+	MTU        int
+	// Tags replace the existing tags when not nil. An empty slice removes
+	// all the tags.
+	Tags []string
+	// Params replaces all of the interface params when not nil.
+	Params *InterfaceParams
+
+	// LinkConnected, LinkSpeed and InterfaceSpeed describe the physical
+	// link of the interface. The speeds are in Mbit/s.
+	LinkConnected  *bool
+	LinkSpeed      int
+	InterfaceSpeed int
 }
 
 func (a *UpdateInterfaceArgs) vlanID() int {
@@ -127,14 +196,27 @@ func (a *UpdateInterfaceArgs) vlanID() int {
 
 // Update implements Interface.
 func (i *interface_) Update(args UpdateInterfaceArgs) error {
-	var empty UpdateInterfaceArgs
-	if args == empty {
-		return nil
-	}
 	params := NewURLParams()
 	params.MaybeAdd("name", args.Name)
 	params.MaybeAdd("mac_address", args.MACAddress)
 	params.MaybeAddInt("vlan", args.vlanID())
+	if args.Params != nil {
+		args.Params.addTo(params)
+	}
+	if args.MTU != 0 {
+		params.Values.Set("mtu", fmt.Sprint(args.MTU))
+	}
+	if args.Tags != nil {
+		params.Values.Add("tags", strings.Join(args.Tags, ","))
+	}
+	if args.LinkConnected != nil {
+		params.Values.Add("link_connected", fmt.Sprint(*args.LinkConnected))
+	}
+	params.MaybeAddInt("link_speed", args.LinkSpeed)
+	params.MaybeAddInt("interface_speed", args.InterfaceSpeed)
+	if len(params.Values) == 0 {
+		return nil
+	}
 	source, err := i.controller.put(i.resourceURI, params.Values)
 	if err != nil {
 		if svrErr, ok := errors.Cause(err).(ServerError); ok {
@@ -391,9 +473,14 @@ func interface_2_0(source map[string]interface{}) (*interface_, error) {
 
 		"parents":  schema.List(schema.String()),
 		"children": schema.List(schema.String()),
+
+		// The params default to an empty string, but are a JSON object
+		// once anything has been set.
+		"params": schema.OneOf(schema.Nil(""), schema.String(), schema.StringMap(schema.Any())),
 	}
 	defaults := schema.Defaults{
 		"mac_address": "",
+		"params":      "",
 	}
 	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(source, nil)
@@ -417,6 +504,10 @@ func interface_2_0(source map[string]interface{}) (*interface_, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	var params InterfaceParams
+	if paramsMap, ok := valid["params"].(map[string]interface{}); ok {
+		params = interfaceParams_2_0(paramsMap)
+	}
 	macAddress, _ := valid["mac_address"].(string)
 	result := &interface_{
 		resourceURI: valid["resource_uri"].(string),
@@ -435,6 +526,76 @@ func interface_2_0(source map[string]interface{}) (*interface_, error) {
 
 		parents:  convertToStringSlice(valid["parents"]),
 		children: convertToStringSlice(valid["children"]),
+
+		params: params,
 	}
 	return result, nil
 }
+
+// interfaceParams_2_0 reads the params leniently, as they are free form in
+// MAAS. A param that is null or doesn't have the expected type is read as
+// the zero value rather than failing the whole interface.
+func interfaceParams_2_0(source map[string]interface{}) InterfaceParams {
+	fields := schema.Fields{
+		"mtu":       schema.ForceInt(),
+		"accept_ra": schema.Bool(),
+		"autoconf":  schema.Bool(),
+
+		"bond_mode":             schema.String(),
+		"bond_miimon":           schema.ForceInt(),
+		"bond_downdelay":        schema.ForceInt(),
+		"bond_updelay":          schema.ForceInt(),
+		"bond_lacp_rate":        schema.String(),
+		"bond_xmit_hash_policy": schema.String(),
+		"bond_num_grat_arp":     schema.ForceInt(),
+
+		"bridge_type": schema.String(),
+		"bridge_stp":  schema.Bool(),
+		"bridge_fd":   schema.ForceInt(),
+	}
+	valid := make(map[string]interface{})
+	for name, checker := range fields {
+		value, ok := source[name]
+		if !ok {
+			continue
+		}
+		coerced, err := schema.OneOf(schema.Nil(""), checker).Coerce(value, []string{name})
+		if err != nil {
+			logger.Debugf("ignoring interface param: %v", err)
+			continue
+		}
+		valid[name] = coerced
+	}
+	// From here we know that any value in the map is of the right type.
+
+	mtu, _ := valid["mtu"].(int)
+	acceptRA, _ := valid["accept_ra"].(bool)
+	autoconf, _ := valid["autoconf"].(bool)
+	bondMode, _ := valid["bond_mode"].(string)
+	bondMIIMon, _ := valid["bond_miimon"].(int)
+	bondDownDelay, _ := valid["bond_downdelay"].(int)
+	bondUpDelay, _ := valid["bond_updelay"].(int)
+	bondLACPRate, _ := valid["bond_lacp_rate"].(string)
+	bondHashPolicy, _ := valid["bond_xmit_hash_policy"].(string)
+	bondNumGratARP, _ := valid["bond_num_grat_arp"].(int)
+	bridgeType, _ := valid["bridge_type"].(string)
+	bridgeSTP, _ := valid["bridge_stp"].(bool)
+	bridgeForwardDelay, _ := valid["bridge_fd"].(int)
+	return InterfaceParams{
+		MTU:      mtu,
+		AcceptRA: acceptRA,
+		Autoconf: autoconf,
+
+		BondMode:       BondMode(bondMode),
+		BondMIIMon:     bondMIIMon,
+		BondDownDelay:  bondDownDelay,
+		BondUpDelay:    bondUpDelay,
+		BondLACPRate:   BondLACPRate(bondLACPRate),
+		BondHashPolicy: BondHashPolicy(bondHashPolicy),
+		BondNumGratARP: bondNumGratARP,
+
+		BridgeType:         bridgeType,
+		BridgeSTP:          bridgeSTP,
+		BridgeForwardDelay: bridgeForwardDelay,
+	}
+}
@@ -90,6 +90,98 @@ func (s *interfaceSuite) TestReadInterfaceNilMAC(c *gc.C) {
 	c.Assert(result.MACAddress(), gc.Equals, "")
 }
 
+func (*interfaceSuite) TestReadInterfaceStringParams(c *gc.C) {
+	result, err := readInterface(twoDotOh, parseJSON(c, interfaceResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Params(), jc.DeepEquals, InterfaceParams{})
+}
+
+func (*interfaceSuite) TestReadInterfaceNoParams(c *gc.C) {
+	json := parseJSON(c, interfaceResponse)
+	delete(json.(map[string]interface{}), "params")
+	result, err := readInterface(twoDotOh, json)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Params(), jc.DeepEquals, InterfaceParams{})
+}
+
+func (*interfaceSuite) TestReadInterfaceBondParams(c *gc.C) {
+	json := parseJSON(c, interfaceResponse)
+	json.(map[string]interface{})["params"] = map[string]interface{}{
+		"mtu":                   9000,
+		"accept_ra":             true,
+		"autoconf":              true,
+		"bond_mode":             "802.3ad",
+		"bond_miimon":           100,
+		"bond_downdelay":        0,
+		"bond_updelay":          "200",
+		"bond_lacp_rate":        "fast",
+		"bond_xmit_hash_policy": "layer3+4",
+		"bond_num_grat_arp":     1,
+	}
+	result, err := readInterface(twoDotOh, json)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Params(), jc.DeepEquals, InterfaceParams{
+		MTU:            9000,
+		AcceptRA:       true,
+		Autoconf:       true,
+		BondMode:       Bond8023AD,
+		BondMIIMon:     100,
+		BondUpDelay:    200,
+		BondLACPRate:   BondLACPRateFast,
+		BondHashPolicy: BondHashLayer34,
+		BondNumGratARP: 1,
+	})
+}
+
+func (*interfaceSuite) TestReadInterfaceBridgeParams(c *gc.C) {
+	json := parseJSON(c, interfaceResponse)
+	json.(map[string]interface{})["params"] = map[string]interface{}{
+		"bridge_type": "standard",
+		"bridge_stp":  true,
+		"bridge_fd":   15,
+	}
+	result, err := readInterface(twoDotOh, json)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Params(), jc.DeepEquals, InterfaceParams{
+		BridgeType:         "standard",
+		BridgeSTP:          true,
+		BridgeForwardDelay: 15,
+	})
+}
+
+func (*interfaceSuite) TestReadInterfaceNullAndStringParams(c *gc.C) {
+	json := parseJSON(c, interfaceResponse)
+	json.(map[string]interface{})["params"] = map[string]interface{}{
+		"mtu":            "1500",
+		"accept_ra":      "true",
+		"bond_mode":      nil,
+		"bond_miimon":    nil,
+		"bond_downdelay": "often",
+		"bond_updelay":   "200",
+		"bridge_stp":     "sometimes",
+		"bridge_fd":      nil,
+	}
+	result, err := readInterface(twoDotOh, json)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Params(), jc.DeepEquals, InterfaceParams{
+		MTU:         1500,
+		AcceptRA:    true,
+		BondUpDelay: 200,
+	})
+}
+
+func (*interfaceSuite) TestReadInterfacesNullParams(c *gc.C) {
+	json := parseJSON(c, interfacesResponse)
+	for _, iface := range json.([]interface{}) {
+		iface.(map[string]interface{})["params"] = map[string]interface{}{
+			"bond_miimon": nil,
+		}
+	}
+	result, err := readInterfaces(twoDotOh, json)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.Not(gc.HasLen), 0)
+}
+
 func (*interfaceSuite) TestLowVersion(c *gc.C) {
 	_, err := readInterfaces(version.MustParse("1.9.0"), parseJSON(c, interfacesResponse))
 	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
@@ -402,6 +494,49 @@ func (s *interfaceSuite) TestUpdateGood(c *gc.C) {
 	c.Assert(form.Get("vlan"), gc.Equals, "13")
 }
 
+func (s *interfaceSuite) TestUpdateParams(c *gc.C) {
+	server, iface := s.getServerAndNewInterface(c)
+	server.AddPutResponse(iface.resourceURI, http.StatusOK, interfaceResponse)
+	connected := false
+	args := UpdateInterfaceArgs{
+		MTU:  9000,
+		Tags: []string{},
+		Params: &InterfaceParams{
+			MTU:        1500,
+			AcceptRA:   true,
+			BondMode:   BondActiveBackup,
+			BondMIIMon: 100,
+		},
+		LinkConnected: &connected,
+		LinkSpeed:     1000,
+	}
+	err := iface.Update(args)
+	c.Check(err, jc.ErrorIsNil)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 9)
+	c.Check(form["mtu"], jc.DeepEquals, []string{"9000"})
+	c.Check(form.Get("accept_ra"), gc.Equals, "true")
+	c.Check(form.Get("autoconf"), gc.Equals, "false")
+	c.Check(form.Get("bond_mode"), gc.Equals, "active-backup")
+	c.Check(form.Get("bond_miimon"), gc.Equals, "100")
+	c.Check(form.Get("bridge_stp"), gc.Equals, "false")
+	c.Check(form["tags"], jc.DeepEquals, []string{""})
+	c.Check(form.Get("link_connected"), gc.Equals, "false")
+	c.Check(form.Get("link_speed"), gc.Equals, "1000")
+}
+
+func (s *interfaceSuite) TestUpdateTags(c *gc.C) {
+	server, iface := s.getServerAndNewInterface(c)
+	server.AddPutResponse(iface.resourceURI, http.StatusOK, interfaceResponse)
+	err := iface.Update(UpdateInterfaceArgs{Tags: []string{"foo", "baz"}})
+	c.Check(err, jc.ErrorIsNil)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 1)
+	c.Check(form.Get("tags"), gc.Equals, "foo,baz")
+}
+
 const (
 	interfacesResponse = "[" + interfaceResponse + "]"
 	interfaceResponse  = `
@@ -3,7 +3,11 @@
 
 package gomaasapi
 
-import "github.com/juju/collections/set"
+import (
+	"time"
+
+	"github.com/juju/collections/set"
+)
 
 const (
 	// Capability constants.
@@ -27,23 +31,194 @@ type Controller interface {
 	// constants.
 	Capabilities() set.Strings
 
+	// BootResources returns the images that the region controller has
+	// synced or that were uploaded to it. The sets of the resources are not
+	// included, use GetBootResource for those.
 	BootResources() ([]BootResource, error)
 
+	// GetBootResource returns the boot resource with the specified id,
+	// along with its sets.
+	GetBootResource(id int) (BootResource, error)
+
+	// ImportBootResources starts importing the images selected from the
+	// boot sources. The import happens in the background.
+	ImportBootResources() error
+
+	// StopImport stops any import of boot resources that is in progress.
+	StopImport() error
+
+	// IsImporting returns true while boot resources are being imported.
+	IsImporting() (bool, error)
+
+	// UploadBootResource creates a boot resource, such as a custom image,
+	// and uploads its content in chunks. The content is read once to
+	// compute its SHA256 before it is uploaded, so content that can't seek
+	// is copied to a temporary file. If a chunk fails to upload, the upload
+	// resumes from the point that MAAS got to.
+	UploadBootResource(UploadBootResourceArgs) (BootResource, error)
+
+	// BootSources returns the simplestreams mirrors that the region
+	// controller imports images from.
+	BootSources() ([]BootSource, error)
+
+	// CreateBootSource adds a new simplestreams mirror to import images
+	// from. No images are selected from the new boot source.
+	CreateBootSource(CreateBootSourceArgs) (BootSource, error)
+
+	// RackControllers returns the rack controllers of MAAS, including the
+	// region controllers that are also rack controllers.
+	RackControllers() ([]RackController, error)
+
+	// RegionControllers returns the region controllers of MAAS, including
+	// the rack controllers that are also region controllers.
+	RegionControllers() ([]RegionController, error)
+
+	// ResolveRack returns the rack controller with the specified system
+	// ID, such as the primary or secondary rack of a VLAN.
+	ResolveRack(systemID string) (RackController, error)
+
+	// Config returns the value of the MAAS setting with the specified
+	// name, decoded from JSON. Strings, booleans, numbers and nil are
+	// returned as string, bool, float64 and nil.
+	Config(name string) (interface{}, error)
+
+	// SetConfig changes the value of the MAAS setting with the specified
+	// name. The value may be a string, bool, number, nil, or a []string
+	// for the settings that are lists. This requires admin privileges.
+	SetConfig(name string, value interface{}) error
+
+	// MAASConfig returns a snapshot of the settings of MAAS that are
+	// commonly set when a region is built.
+	MAASConfig() (MAASConfig, error)
+
+	// Users returns the users of MAAS. This requires admin privileges.
+	Users() ([]User, error)
+
+	// WhoAmI returns the user that the controller is authenticated as.
+	WhoAmI() (User, error)
+
+	// CreateUser adds a user to MAAS. This requires admin privileges.
+	CreateUser(CreateUserArgs) (User, error)
+
+	// DeleteUser removes the user with the specified username from MAAS.
+	// This requires admin privileges.
+	DeleteUser(username string) error
+
+	// SSHKeys returns the SSH keys of the authenticated user, which are
+	// installed on the machines that the user deploys.
+	SSHKeys() ([]SSHKey, error)
+
+	// AddSSHKey adds the public SSH key to the authenticated user.
+	AddSSHKey(key string) (SSHKey, error)
+
+	// ImportSSHKeys adds the public SSH keys of a Launchpad or GitHub
+	// user, such as "lp:foo" or "gh:bar", to the authenticated user.
+	ImportSSHKeys(keySource string) ([]SSHKey, error)
+
+	// DeleteSSHKey removes the SSH key with the specified ID from the
+	// authenticated user.
+	DeleteSSHKey(id int) error
+
+	// SSLKeys returns the SSL keys of the authenticated user.
+	SSLKeys() ([]SSLKey, error)
+
+	// AddSSLKey adds the PEM encoded SSL key to the authenticated user.
+	AddSSLKey(key string) (SSLKey, error)
+
+	// DeleteSSLKey removes the SSL key with the specified ID from the
+	// authenticated user.
+	DeleteSSLKey(id int) error
+
+	// VMHosts returns the LXD and virsh VM hosts that MAAS composes
+	// machines on.
+	VMHosts() ([]VMHost, error)
+
+	// CreateVMHost adds a VM host to MAAS. The existing virtual machines of
+	// the VM host are added to MAAS as machines.
+	CreateVMHost(CreateVMHostArgs) (VMHost, error)
+
+	// Events returns the events that match the specified criteria, newest
+	// first.
+	Events(EventsArgs) ([]Event, error)
+
+	// EventHistory returns an iterator that pages back through the events
+	// that match the specified criteria, starting with the newest.
+	EventHistory(EventsArgs) EventIterator
+
+	// FollowEvents polls MAAS every interval for events that match the
+	// specified criteria and delivers them, oldest first, on the returned
+	// channel until the stop channel is closed. Only events newer than
+	// args.After are delivered, or newer than the latest existing event if
+	// args.After isn't specified. If a poll fails, the error is sent on the
+	// error channel and both channels are closed.
+	FollowEvents(args EventsArgs, interval time.Duration, stop <-chan struct{}) (<-chan Event, <-chan error)
+
 	// Fabrics returns the list of Fabrics defined in the MAAS controller.
 	Fabrics() ([]Fabric, error)
 
+	// CreateFabric creates a new fabric, which has a default untagged VLAN.
+	CreateFabric(CreateFabricArgs) (Fabric, error)
+
 	// Spaces returns the list of Spaces defined in the MAAS controller.
 	Spaces() ([]Space, error)
 
+	// CreateSpace creates a new space. The description is optional.
+	CreateSpace(name, description string) (Space, error)
+
+	// Subnets returns the list of Subnets defined in the MAAS controller.
+	Subnets() ([]Subnet, error)
+
+	// GetSubnet returns the subnet with the specified id.
+	GetSubnet(id int) (Subnet, error)
+
+	// CreateSubnet creates and returns a new Subnet.
+	CreateSubnet(CreateSubnetArgs) (Subnet, error)
+
+	// IPRanges returns the dynamic and reserved IP ranges defined in the
+	// MAAS controller.
+	IPRanges() ([]IPRange, error)
+
+	// CreateIPRange creates and returns a new IPRange.
+	CreateIPRange(CreateIPRangeArgs) (IPRange, error)
+
+	// IPAddresses returns the reserved and allocated addresses that match
+	// the params.
+	IPAddresses(IPAddressesArgs) ([]IPAddress, error)
+
+	// ReserveIPAddress reserves an address so that MAAS doesn't allocate
+	// it to anything else. The reserved address is returned.
+	ReserveIPAddress(ReserveIPArgs) (IPAddress, error)
+
+	// ReleaseIPAddress releases an address reserved by ReserveIPAddress.
+	// Administrators may force the release of addresses that were allocated
+	// in other ways.
+	ReleaseIPAddress(ip string, force bool) error
+
 	// StaticRoutes returns the list of StaticRoutes defined in the MAAS controller.
 	StaticRoutes() ([]StaticRoute, error)
 
 	// Zones lists all the zones known to the MAAS controller.
 	Zones() ([]Zone, error)
 
+	// CreateZone creates a new zone. The description is optional.
+	CreateZone(name, description string) (Zone, error)
+
+	// ZoneSummaries lists all the zones along with the number of machines
+	// and devices in each. If the MAAS controller doesn't provide the counts,
+	// they are computed from the machine and device listings.
+	ZoneSummaries() ([]ZoneSummary, error)
+
 	// Pools lists all the pools known to the MAAS controller.
 	Pools() ([]Pool, error)
 
+	// CreatePool creates a new resource pool. The description is optional.
+	CreatePool(name, description string) (Pool, error)
+
+	// PoolSummaries lists all the pools along with the number of machines
+	// and devices in each. If the MAAS controller doesn't provide the counts,
+	// they are computed from the machine and device listings.
+	PoolSummaries() ([]PoolSummary, error)
+
 	// Machines returns a list of machines that match the params.
 	Machines(MachinesArgs) ([]Machine, error)
 
@@ -55,6 +230,10 @@ type Controller interface {
 	// from the user making them available to be allocated again.
 	ReleaseMachines(ReleaseMachinesArgs) error
 
+	// CreateMachine enlists a new machine with the specified power
+	// configuration. The new machine is returned.
+	CreateMachine(CreateMachineArgs) (Machine, error)
+
 	// Devices returns a list of devices that match the params.
 	Devices(DevicesArgs) ([]Device, error)
 
@@ -76,8 +255,30 @@ type Controller interface {
 	// Returns the DNS Domain Managed By MAAS
 	Domains() ([]Domain, error)
 
+	// CreateDomain creates a new DNS domain.
+	CreateDomain(CreateDomainArgs) (Domain, error)
+
+	// DNSResources returns the DNS resources that match the params.
+	DNSResources(DNSResourcesArgs) ([]DNSResource, error)
+
+	// CreateDNSResource creates a name with address records for the
+	// specified IP addresses.
+	CreateDNSResource(CreateDNSResourceArgs) (DNSResource, error)
+
+	// DNSResourceRecords returns the DNS resource records that match the
+	// params.
+	DNSResourceRecords(DNSResourceRecordsArgs) ([]DNSResourceRecord, error)
+
+	// CreateDNSResourceRecord creates a record, other than an address
+	// record, for a name.
+	CreateDNSResourceRecord(CreateDNSResourceRecordArgs) (DNSResourceRecord, error)
+
 	// Returns the list of MAAS tags
 	Tags() ([]Tag, error)
+
+	// CreateTag creates a new tag. Only the name is required. If the tag
+	// has a definition, MAAS tags the matching machines automatically.
+	CreateTag(name, comment, definition, kernelOpts string) (Tag, error)
 }
 
 // File represents a file stored in the MAAS controller.
@@ -109,6 +310,17 @@ type Fabric interface {
 	ClassType() string
 
 	VLANs() []VLAN
+
+	// Update the name, description or class type of the fabric.
+	Update(UpdateFabricArgs) error
+
+	// Delete removes the fabric, along with its VLANs, from the MAAS
+	// controller. The default fabric cannot be deleted.
+	Delete() error
+
+	// CreateVLAN creates a new tagged VLAN in the fabric. The name is
+	// optional, and MAAS uses the default MTU if mtu is zero.
+	CreateVLAN(vid int, name string, mtu int) (VLAN, error)
 }
 
 // VLAN represents an instance of a Virtual LAN. VLANs are a common way to
@@ -143,6 +355,21 @@ type VLAN interface {
 
 	PrimaryRack() string
 	SecondaryRack() string
+
+	// Space is the name of the space the VLAN is in, if any.
+	Space() string
+
+	// Update the name, MTU, DHCP configuration, relay VLAN or space of
+	// the VLAN.
+	Update(UpdateVLANArgs) error
+
+	// SetSpace moves the VLAN, along with its subnets, into the space. A nil
+	// space removes the VLAN from its current space.
+	SetSpace(Space) error
+
+	// Delete removes the VLAN from the MAAS controller. The default VLAN
+	// of a fabric cannot be deleted.
+	Delete() error
 }
 
 // Zone represents a physical zone that a Machine is in. The meaning of a
@@ -150,35 +377,234 @@ type VLAN interface {
 // or a data centre. Users can then allocate nodes from specific physical zones,
 // to suit their redundancy or performance requirements.
 type Zone interface {
+	ID() int
 	Name() string
 	Description() string
+
+	// Update the name or description of the zone.
+	Update(UpdateZoneArgs) error
+
+	// Delete removes the zone from the MAAS controller. The default zone
+	// cannot be deleted.
+	Delete() error
 }
 
 // Pool is just a logical separation of resources.
 type Pool interface {
+	ID() int
 	// The name of the resource pool
 	Name() string
 	Description() string
+
+	// Update the name or description of the resource pool.
+	Update(UpdatePoolArgs) error
+
+	// Delete removes the resource pool from the MAAS controller. The default
+	// pool cannot be deleted.
+	Delete() error
 }
 
+// Domain represents a DNS domain managed by MAAS.
 type Domain interface {
+	ID() int
+
 	// The name of the Domain
 	Name() string
+
+	// TTL is the default TTL in seconds of the records in the domain, or
+	// zero if the domain uses the global default TTL.
+	TTL() int
+
+	// Authoritative is true if MAAS is authoritative for the domain.
+	Authoritative() bool
+
+	// IsDefault is true for the domain that new nodes are put in.
+	IsDefault() bool
+
+	ResourceRecordCount() int
+
+	// Update the name, authority or TTL of the domain.
+	Update(UpdateDomainArgs) error
+
+	// Delete removes the domain from the MAAS controller. Domains that
+	// still have records cannot be deleted.
+	Delete() error
+
+	// SetDefault makes the domain the default domain. Other Domain values
+	// are not refreshed, so they may still report themselves as the
+	// default.
+	SetDefault() error
 }
 
-// BootResource is the bomb... find something to say here.
+// DNSResource represents a name in a DNS domain managed by MAAS, along with
+// its address records and other resource records.
+type DNSResource interface {
+	ID() int
+	FQDN() string
+
+	// AddressTTL is the TTL in seconds of the address records, or zero if
+	// the TTL of the domain is used.
+	AddressTTL() int
+
+	// IPAddresses are the addresses of the A and AAAA records of the name.
+	IPAddresses() []string
+
+	// ResourceRecords are the records of the name, other than the address
+	// records.
+	ResourceRecords() []DNSResourceRecord
+
+	// Update the name, addresses or address TTL of the resource.
+	Update(UpdateDNSResourceArgs) error
+
+	// Delete removes the name, along with all its records.
+	Delete() error
+}
+
+// DNSResourceRecord represents a single DNS record, such as a CNAME, TXT,
+// SRV or MX record, in a DNS domain managed by MAAS.
+type DNSResourceRecord interface {
+	ID() int
+	FQDN() string
+
+	// TTL is the TTL of the record in seconds, or zero if the TTL of the
+	// domain is used.
+	TTL() int
+
+	Type() DNSRecordType
+	Data() string
+
+	// Update the type, data or TTL of the record.
+	Update(UpdateDNSResourceRecordArgs) error
+
+	// Delete removes the record.
+	Delete() error
+}
+
+// BootResource represents an image that MAAS uses to commission and deploy
+// machines, such as a kernel and root filesystem for a release of Ubuntu.
 type BootResource interface {
 	ID() int
 	Name() string
+
+	// Type is how the resource got to MAAS: "Synced", "Uploaded" or
+	// "Generated".
 	Type() string
 	Architecture() string
 	SubArchitectures() set.Strings
 	KernelFlavor() string
+
+	// LastDeployed is when a machine was last deployed with the resource,
+	// or empty if MAAS doesn't say.
+	LastDeployed() string
+
+	// Sets are the versions of the resource ordered from oldest to newest.
+	// Sets are only returned from Controller.GetBootResource.
+	Sets() []BootResourceSet
+
+	// Size is the size in bytes of the newest set of the resource, or zero
+	// if the sets are not known.
+	Size() int
+}
+
+// BootSource represents a simplestreams mirror that MAAS imports boot
+// resources from.
+type BootSource interface {
+	ID() int
+	URL() string
+	KeyringFilename() string
+	KeyringData() []byte
+	Created() string
+	Updated() string
+
+	// Update the URL or keyring filename of the boot source.
+	Update(UpdateBootSourceArgs) error
+
+	// Delete removes the boot source, along with its selections.
+	Delete() error
+
+	// Selections returns the images that are imported from the boot source.
+	Selections() ([]BootSourceSelection, error)
+
+	// CreateSelection selects images to import from the boot source.
+	CreateSelection(CreateBootSourceSelectionArgs) (BootSourceSelection, error)
+}
+
+// BootSourceSelection represents a set of images that MAAS imports from a
+// boot source. An empty list of arches, subarches or labels, or a list
+// containing "*", selects all of the values available.
+type BootSourceSelection interface {
+	ID() int
+	BootSourceID() int
+	OS() string
+	Release() string
+	Arches() []string
+	Subarches() []string
+	Labels() []string
+
+	// Update the images selected.
+	Update(UpdateBootSourceSelectionArgs) error
+
+	// Delete stops importing the selected images. Images already imported
+	// are removed by the next import.
+	Delete() error
+}
+
+// RegionController represents a region controller of MAAS, which serves
+// the API and the web UI, and manages the rack controllers.
+type RegionController interface {
+	SystemID() string
+	Hostname() string
+	FQDN() string
+
+	// Version is the version of MAAS running on the controller, or empty
+	// if MAAS doesn't say.
+	Version() string
+	Architecture() string
+	IPAddresses() []string
+
+	// IsRackController is true if the region controller is also a rack
+	// controller.
+	IsRackController() bool
+	IsRegionController() bool
+
+	// Services returns the MAAS services that run on the controller.
+	Services() []ControllerService
+
+	// InterfaceSet returns all the interfaces of the controller.
+	InterfaceSet() []Interface
+}
+
+// RackController represents a rack controller of MAAS, which provides DHCP,
+// PXE booting and power control to the machines on the VLANs it serves.
+type RackController interface {
+	SystemID() string
+	Hostname() string
+	FQDN() string
+
+	// Version is the version of MAAS running on the controller, or empty
+	// if MAAS doesn't say.
+	Version() string
+	Architecture() string
+	IPAddresses() []string
+
+	IsRackController() bool
+	// IsRegionController is true if the rack controller is also a region
+	// controller.
+	IsRegionController() bool
+
+	// Services returns the MAAS services that run on the controller.
+	Services() []ControllerService
+
+	// InterfaceSet returns all the interfaces of the controller.
+	InterfaceSet() []Interface
+
+	// ServedVLANs returns the VLANs that the controller is the primary or
+	// secondary rack of.
+	ServedVLANs() ([]VLAN, error)
 }
 
 // Device represents some form of device in MAAS.
 type Device interface {
-	// TODO: add domain
 	SystemID() string
 	Hostname() string
 	FQDN() string
@@ -186,6 +612,10 @@ type Device interface {
 	Zone() Zone
 	Pool() Pool
 
+	// Domain returns the DNS domain of the device, or nil if MAAS
+	// doesn't say.
+	Domain() Domain
+
 	// Parent returns the SystemID of the Parent. Most often this will be a
 	// Machine.
 	Parent() string
@@ -221,6 +651,14 @@ type Machine interface {
 
 	IPAddresses() []string
 	PowerState() string
+	PowerType() string
+
+	// PowerParameters fetches the power driver configuration for the
+	// machine. This requires admin privileges.
+	PowerParameters() (PowerParameters, error)
+	// UpdatePowerParameters changes the power type and driver
+	// configuration of the machine.
+	UpdatePowerParameters(UpdatePowerParametersArgs) error
 
 	// Devices returns a list of devices that match the params and have
 	// this Machine as the parent.
@@ -241,6 +679,14 @@ type Machine interface {
 	// specified. If there is no match, nil is returned.
 	Interface(id int) Interface
 
+	// CreateBond creates a bond of the parent interfaces.
+	CreateBond(CreateBondArgs) (Interface, error)
+	// CreateBridge creates a bridge on the parent interface.
+	CreateBridge(CreateBridgeArgs) (Interface, error)
+	// CreateVLANInterface creates an interface on the parent that is
+	// tagged for the VLAN.
+	CreateVLANInterface(parent Interface, vlan VLAN) (Interface, error)
+
 	// PhysicalBlockDevices returns all the physical block devices on the machine.
 	PhysicalBlockDevices() []BlockDevice
 	// PhysicalBlockDevice returns the physical block device for the machine
@@ -257,25 +703,107 @@ type Machine interface {
 	// id specified. If there is no match, nil is returned.
 	Partition(id int) Partition
 
+	// SetStorageLayout replaces the storage configuration of the machine
+	// with one of the predefined layouts. The machine must be Ready.
+	SetStorageLayout(StorageLayoutArgs) error
+
+	// VolumeGroups returns the LVM volume groups defined on the machine.
+	VolumeGroups() ([]VolumeGroup, error)
+	// CreateVolumeGroup creates a volume group from the specified block
+	// devices and partitions.
+	CreateVolumeGroup(CreateVolumeGroupArgs) (VolumeGroup, error)
+
+	// RAIDs returns the software RAIDs defined on the machine.
+	RAIDs() ([]RAID, error)
+	// CreateRAID creates a software RAID from the specified block devices
+	// and partitions. The block devices of the machine are not updated, so
+	// the machine needs to be read again to see the virtual block device of
+	// the RAID and the members that it uses.
+	CreateRAID(CreateRAIDArgs) (RAID, error)
+
+	// BcacheCacheSets returns the bcache cache sets defined on the machine.
+	BcacheCacheSets() ([]BcacheCacheSet, error)
+	// CreateBcacheCacheSet creates a cache set on the specified block
+	// device or partition.
+	CreateBcacheCacheSet(BcacheCacheSetArgs) (BcacheCacheSet, error)
+
+	// Bcaches returns the bcache devices defined on the machine.
+	Bcaches() ([]Bcache, error)
+	// CreateBcache creates a bcache device that caches the backing device
+	// using the cache set. The block devices of the machine are not
+	// updated, so the machine needs to be read again to see the virtual
+	// block device of the bcache.
+	CreateBcache(CreateBcacheArgs) (Bcache, error)
+
 	Zone() Zone
 	Pool() Pool
 
 	// Start the machine and install the operating system specified in the args.
 	Start(StartArgs) error
 
+	// Commission begins commissioning the machine.
+	Commission(CommissionArgs) error
+	// Abort cancels the current operation on the machine, such as
+	// commissioning or deploying.
+	Abort(AbortArgs) error
+
+	// PowerOn and PowerOff turn the machine on or off using its configured
+	// power driver.
+	PowerOn(PowerOnArgs) error
+	PowerOff(PowerOffArgs) error
+	// QueryPowerState asks the power driver for the current power state of
+	// the machine, rather than returning the last known state.
+	QueryPowerState() (string, error)
+
+	// Lock and Unlock control whether changes can be made to a deployed
+	// machine.
+	Lock(LockArgs) error
+	Unlock(UnlockArgs) error
+
+	// MarkBroken and MarkFixed move the machine into and out of the broken
+	// state.
+	MarkBroken(MarkBrokenArgs) error
+	MarkFixed(MarkFixedArgs) error
+
+	// RescueMode boots the machine into an ephemeral environment, and
+	// ExitRescueMode returns it to its previous state.
+	RescueMode(RescueModeArgs) error
+	ExitRescueMode(ExitRescueModeArgs) error
+
 	// CreateDevice creates a new Device with this Machine as the parent.
 	// The device will have one interface that is linked to the specified subnet.
 	CreateDevice(CreateMachineDeviceArgs) (Device, error)
+
+	// ScriptResults returns the results of the commissioning, testing and
+	// installation scripts that have run on the machine, including their
+	// output.
+	ScriptResults(ScriptResultsArgs) ([]ScriptResult, error)
+
+	// Details returns the hardware and the LLDP neighbors of the machine, as
+	// they were found when it was last commissioned.
+	Details() (MachineDetails, error)
 }
 
 // Space is a name for a collection of Subnets.
 type Space interface {
 	ID() int
 	Name() string
+	Description() string
 	Subnets() []Subnet
+
+	// Update the name or description of the space.
+	Update(UpdateSpaceArgs) error
+
+	// Delete removes the space from the MAAS controller. Any VLANs in the
+	// space are left without a space.
+	Delete() error
 }
 
 // Subnet refers to an IP range on a VLAN.
+//
+// The methods that make requests of the controller are only available on the
+// subnets returned from the Controller, or from the other entities returned
+// from the Controller, including the links of interfaces.
 type Subnet interface {
 	ID() int
 	Name() string
@@ -289,6 +817,70 @@ type Subnet interface {
 	// DNSServers is a list of ip addresses of the DNS servers for the subnet.
 	// This list may be empty.
 	DNSServers() []string
+
+	// AllowProxy is true if the MAAS proxy allows requests from the subnet.
+	AllowProxy() bool
+
+	// Managed is true if MAAS manages the allocation of addresses in the
+	// subnet.
+	Managed() bool
+
+	// RDNSMode is how MAAS generates reverse DNS for the subnet.
+	RDNSMode() RDNSMode
+
+	// Statistics returns the usage of the addresses in the subnet. The
+	// unreserved ranges are included if includeRanges is true.
+	Statistics(includeRanges bool) (SubnetStats, error)
+
+	// ReservedIPRanges returns the ranges of addresses in the subnet that
+	// are in use or reserved, along with their purpose.
+	ReservedIPRanges() ([]AddressRange, error)
+
+	// UnreservedIPRanges returns the ranges of addresses in the subnet that
+	// are free to be allocated.
+	UnreservedIPRanges() ([]AddressRange, error)
+
+	// Update the subnet with the specified values.
+	Update(UpdateSubnetArgs) error
+
+	// Delete removes the subnet from the MAAS controller.
+	Delete() error
+}
+
+// IPAddress is an address that MAAS has allocated, or that has been
+// reserved by a user.
+type IPAddress interface {
+	IP() string
+	AllocType() IPAllocType
+	// AllocTypeName is the human readable form of the AllocType.
+	AllocTypeName() string
+	Created() string
+	// Owner is the username of the user that the address belongs to, and
+	// may be empty.
+	Owner() string
+
+	// Subnet may be nil if the address isn't in a known subnet.
+	Subnet() Subnet
+	// InterfaceSet are the interfaces that the address is assigned to.
+	InterfaceSet() []Interface
+}
+
+// IPRange is a range of addresses in a subnet that MAAS either uses for
+// DHCP, or never allocates.
+type IPRange interface {
+	ID() int
+	// Type is either "dynamic" or "reserved".
+	Type() string
+	StartIP() string
+	EndIP() string
+	Comment() string
+	Subnet() Subnet
+
+	// Update the range with the specified values.
+	Update(UpdateIPRangeArgs) error
+
+	// Delete removes the range from the MAAS controller.
+	Delete() error
 }
 
 // StaticRoute defines an explicit route that users have requested to be added
@@ -330,10 +922,12 @@ type Interface interface {
 	MACAddress() string
 	EffectiveMTU() int
 
-	// Params is a JSON field, and defaults to an empty string, but is almost
-	// always a JSON object in practice. Gleefully ignoring it until we need it.
+	// Params holds the MTU, IPv6, bond and bridge configuration of the
+	// interface. All values are zero if MAAS has no params for it.
+	Params() InterfaceParams
 
-	// Update the name, mac address or VLAN.
+	// Update the name, mac address, VLAN, MTU, tags, params or link
+	// details.
 	Update(UpdateInterfaceArgs) error
 
 	// Delete this interface.
@@ -391,6 +985,17 @@ type StorageDevice interface {
 // as a filesystem.
 type Partition interface {
 	StorageDevice
+
+	// Format creates a filesystem on the partition.
+	Format(FormatStorageDeviceArgs) error
+	// Unformat removes the filesystem from the partition.
+	Unformat() error
+	// Mount the filesystem on the partition.
+	Mount(MountStorageDeviceArgs) error
+	// Unmount the filesystem on the partition.
+	Unmount() error
+	// Delete removes the partition from its block device.
+	Delete() error
 }
 
 // BlockDevice represents an entire block device on the machine.
@@ -408,6 +1013,122 @@ type BlockDevice interface {
 
 	// There are some other attributes for block devices, but we can
 	// expose them on an as needed basis.
+
+	// Format creates a filesystem on the whole block device.
+	Format(FormatStorageDeviceArgs) error
+	// Unformat removes the filesystem from the block device.
+	Unformat() error
+	// Mount the filesystem on the block device.
+	Mount(MountStorageDeviceArgs) error
+	// Unmount the filesystem on the block device.
+	Unmount() error
+	// SetBootDisk makes this the disk that the machine boots from.
+	SetBootDisk() error
+	// Delete removes the block device from the machine.
+	Delete() error
+
+	// CreatePartition adds a new partition to the block device and
+	// returns it. The partitions of the block device are not updated, so
+	// the machine needs to be read again to see the new partition.
+	CreatePartition(CreatePartitionArgs) (Partition, error)
+}
+
+// VolumeGroup represents an LVM volume group on the machine.
+type VolumeGroup interface {
+	ID() int
+	Name() string
+	UUID() string
+
+	Size() uint64
+	UsedSize() uint64
+	AvailableSize() uint64
+
+	// Devices are the block devices and partitions that are the physical
+	// volumes of the volume group.
+	Devices() []StorageDevice
+	LogicalVolumes() []LogicalVolume
+
+	// CreateLogicalVolume adds a new logical volume to the volume group
+	// and returns it.
+	CreateLogicalVolume(CreateLogicalVolumeArgs) (LogicalVolume, error)
+	// Delete removes the volume group from the machine.
+	Delete() error
+}
+
+// RAID represents a software RAID on the machine.
+type RAID interface {
+	ID() int
+	Name() string
+	UUID() string
+	Level() string
+	Size() uint64
+
+	// Devices are the active block devices and partitions of the RAID.
+	Devices() []StorageDevice
+	SpareDevices() []StorageDevice
+
+	// VirtualDevice is the block device that the RAID presents.
+	VirtualDevice() BlockDevice
+
+	// Update changes the name or members of the RAID.
+	Update(UpdateRAIDArgs) error
+	// Delete removes the RAID from the machine.
+	Delete() error
+}
+
+// BcacheCacheSet represents a block device or partition that is used as
+// the cache for bcache devices.
+type BcacheCacheSet interface {
+	ID() int
+	Name() string
+
+	// CacheDevice is either a BlockDevice or a Partition.
+	CacheDevice() StorageDevice
+
+	// Update changes the cache device of the cache set.
+	Update(BcacheCacheSetArgs) error
+	// Delete removes the cache set from the machine.
+	Delete() error
+}
+
+// Bcache represents a bcache device on the machine.
+type Bcache interface {
+	ID() int
+	Name() string
+	UUID() string
+	CacheMode() string
+	Size() uint64
+
+	CacheSet() BcacheCacheSet
+	// BackingDevice is either a BlockDevice or a Partition.
+	BackingDevice() StorageDevice
+
+	// VirtualDevice is the block device that the bcache presents.
+	VirtualDevice() BlockDevice
+
+	// Update changes the settings of the bcache device.
+	Update(UpdateBcacheArgs) error
+	// Delete removes the bcache device from the machine.
+	Delete() error
+}
+
+// LogicalVolume represents a logical volume in a volume group. It may be
+// mounted as a filesystem.
+type LogicalVolume interface {
+	StorageDevice
+
+	Name() string
+
+	// Format creates a filesystem on the logical volume.
+	Format(FormatStorageDeviceArgs) error
+	// Unformat removes the filesystem from the logical volume.
+	Unformat() error
+	// Mount the filesystem on the logical volume.
+	Mount(MountStorageDeviceArgs) error
+	// Unmount the filesystem on the logical volume.
+	Unmount() error
+	// Delete removes the logical volume from its volume group.
+	Delete() error
 }
 
 // OwnerDataHolder represents any MAAS object that can store key/value
@@ -431,4 +1152,147 @@ type Tag interface {
 	Comment() string
 	Definition() string
 	KernelOpts() string
+
+	// Update the name, comment, definition or kernel options of the tag.
+	Update(UpdateTagArgs) error
+
+	// Delete removes the tag from the MAAS controller, and from all the
+	// machines and devices that have it.
+	Delete() error
+
+	// Rebuild asks MAAS to re-evaluate the tag definition against all the
+	// machines. The rebuild happens in the background.
+	Rebuild() error
+
+	// Machines returns the machines that have the tag.
+	Machines() ([]Machine, error)
+
+	// Devices returns the devices that have the tag.
+	Devices() ([]Device, error)
+
+	// UpdateNodes adds the tag to, and removes the tag from, the nodes with
+	// the given system IDs, and returns the number of nodes added and
+	// removed. Only tags without a definition can be updated manually.
+	UpdateNodes(add, remove []string) (added int, removed int, err error)
+}
+
+// Event is an entry in the event log of MAAS, such as a change of the
+// status of a node, or an action by a user.
+type Event interface {
+	ID() int
+
+	// SystemID and Hostname identify the node of the event, if there is
+	// one.
+	SystemID() string
+	Hostname() string
+
+	// Username is the user that caused the event, if there is one.
+	Username() string
+
+	// Level is one of "DEBUG", "INFO", "WARNING", "ERROR" or "CRITICAL".
+	Level() string
+	Type() string
+	Description() string
+	Created() string
+}
+
+// EventIterator pages back through the event log of MAAS, newest first.
+type EventIterator interface {
+	// Next gets the next page of older events. It returns false when
+	// there are no more events, or if there is an error.
+	Next() bool
+
+	// Events returns the events of the current page.
+	Events() []Event
+
+	// Err returns the error that stopped the iteration, if there was one.
+	Err() error
+}
+
+// ScriptResult is the result of a commissioning, testing or installation
+// script that ran on a machine.
+type ScriptResult interface {
+	ID() int
+	Name() string
+	Type() ScriptType
+
+	// Status is the status name of the script, such as "Pending",
+	// "Running", "Passed", "Failed" or "Timed out".
+	Status() string
+	// ExitStatus is zero if the script hasn't finished.
+	ExitStatus() int
+
+	Started() string
+	Ended() string
+	Runtime() string
+
+	// Output is the combined stdout and stderr of the script.
+	Output() []byte
+	Stdout() []byte
+	Stderr() []byte
+}
+
+// VMHost is an LXD or virsh host that MAAS can compose virtual machines on.
+type VMHost interface {
+	ID() int
+	Name() string
+	// Type is "lxd" or "virsh".
+	Type() string
+	Version() string
+	Architectures() []string
+	// Capabilities are the features of the VM host, such as "composable",
+	// "over_commit" and "storage_pools".
+	Capabilities() set.Strings
+	Tags() []string
+	Zone() Zone
+	Pool() Pool
+
+	// CPUOverCommitRatio and MemoryOverCommitRatio are the multiples of
+	// the total cores and memory that can be used by composed machines.
+	CPUOverCommitRatio() float64
+	MemoryOverCommitRatio() float64
+
+	// Total, Used and Available are the amounts of the resources of the
+	// VM host.
+	Total() VMHostResources
+	Used() VMHostResources
+	Available() VMHostResources
+	StoragePools() []VMHostStoragePool
+
+	// Refresh asks MAAS to update the resources of the VM host, and the
+	// machines that it has, from the VM host itself.
+	Refresh() error
+
+	// Delete removes the VM host from MAAS. The virtual machines on the VM
+	// host are not destroyed.
+	Delete() error
+
+	// Compose creates a new virtual machine on the VM host, and returns the
+	// machine that MAAS commissions from it.
+	Compose(ComposeArgs) (Machine, error)
+}
+
+// User is a user of MAAS.
+type User interface {
+	Username() string
+	Email() string
+	IsSuperuser() bool
+	// IsLocal is false for the users that are managed by an external
+	// identity provider.
+	IsLocal() bool
+}
+
+// SSHKey is a public SSH key of a user.
+type SSHKey interface {
+	ID() int
+	Key() string
+	// KeySource is the Launchpad or GitHub user that the key was imported
+	// from, such as "lp:foo", or empty if the key was added directly.
+	KeySource() string
+}
+
+// SSLKey is an SSL key of a user.
+type SSLKey interface {
+	ID() int
+	Key() string
 }
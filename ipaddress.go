@@ -0,0 +1,217 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+// IPAllocType describes how an IP address was allocated.
+type IPAllocType int
+
+const (
+	// IPAllocAuto addresses are assigned to interfaces automatically.
+	IPAllocAuto IPAllocType = 0
+
+	// IPAllocSticky addresses are statically assigned to interfaces.
+	IPAllocSticky IPAllocType = 1
+
+	// IPAllocUserReserved addresses are reserved by a user through the
+	// ipaddresses API.
+	IPAllocUserReserved IPAllocType = 4
+
+	// IPAllocDHCP addresses are handed out by the MAAS DHCP server.
+	IPAllocDHCP IPAllocType = 5
+
+	// IPAllocDiscovered addresses are observed on the network by MAAS.
+	IPAllocDiscovered IPAllocType = 6
+)
+
+type ipAddress struct {
+	controller *controller
+
+	resourceURI string
+
+	ip            string
+	allocType     IPAllocType
+	allocTypeName string
+	created       string
+	owner         string
+
+	subnet       *subnet
+	interfaceSet []*interface_
+}
+
+// IP implements IPAddress.
+func (a *ipAddress) IP() string {
+	return a.ip
+}
+
+// AllocType implements IPAddress.
+func (a *ipAddress) AllocType() IPAllocType {
+	return a.allocType
+}
+
+// AllocTypeName implements IPAddress.
+func (a *ipAddress) AllocTypeName() string {
+	return a.allocTypeName
+}
+
+// Created implements IPAddress.
+func (a *ipAddress) Created() string {
+	return a.created
+}
+
+// Owner implements IPAddress.
+func (a *ipAddress) Owner() string {
+	return a.owner
+}
+
+// Subnet implements IPAddress.
+func (a *ipAddress) Subnet() Subnet {
+	if a.subnet == nil {
+		return nil
+	}
+	a.subnet.controller = a.controller
+	return a.subnet
+}
+
+// InterfaceSet implements IPAddress.
+func (a *ipAddress) InterfaceSet() []Interface {
+	result := make([]Interface, len(a.interfaceSet))
+	for i, v := range a.interfaceSet {
+		v.controller = a.controller
+		result[i] = v
+	}
+	return result
+}
+
+func readIPAddress(controllerVersion version.Number, source interface{}) (*ipAddress, error) {
+	readFunc, err := getIPAddressDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ip address base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readIPAddresses(controllerVersion version.Number, source interface{}) ([]*ipAddress, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ip address base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getIPAddressDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readIPAddressList(valid, readFunc)
+}
+
+func getIPAddressDeserializationFunc(controllerVersion version.Number) (ipAddressDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range ipAddressDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no ip address read func for version %s", controllerVersion)
+	}
+	return ipAddressDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readIPAddressList expects the values of the sourceList to be string maps.
+func readIPAddressList(sourceList []interface{}, readFunc ipAddressDeserializationFunc) ([]*ipAddress, error) {
+	result := make([]*ipAddress, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for ip address %d, %T", i, value)
+		}
+		ipAddress, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "ip address %d", i)
+		}
+		result = append(result, ipAddress)
+	}
+	return result, nil
+}
+
+type ipAddressDeserializationFunc func(map[string]interface{}) (*ipAddress, error)
+
+var ipAddressDeserializationFuncs = map[version.Number]ipAddressDeserializationFunc{
+	twoDotOh: ipAddress_2_0,
+}
+
+func ipAddress_2_0(source map[string]interface{}) (*ipAddress, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"ip":              schema.String(),
+		"alloc_type":      schema.ForceInt(),
+		"alloc_type_name": schema.String(),
+		"created":         schema.String(),
+		"owner":           schema.OneOf(schema.Nil(""), schema.StringMap(schema.Any())),
+
+		"subnet":        schema.OneOf(schema.Nil(""), schema.StringMap(schema.Any())),
+		"interface_set": schema.List(schema.StringMap(schema.Any())),
+	}
+	defaults := schema.Defaults{
+		"alloc_type_name": "",
+		"created":         "",
+		"owner":           nil,
+		"subnet":          nil,
+		"interface_set":   []interface{}{},
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ip address 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	var owner string
+	if ownerMap, ok := valid["owner"].(map[string]interface{}); ok {
+		owner, _ = ownerMap["username"].(string)
+	}
+
+	var subnet *subnet
+	if subnetSource, ok := valid["subnet"].(map[string]interface{}); ok {
+		if subnet, err = subnet_2_0(subnetSource); err != nil {
+			return nil, errors.Annotate(err, "subnet")
+		}
+	}
+
+	interfaceSet, err := readInterfaceList(valid["interface_set"].([]interface{}), interface_2_0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	result := &ipAddress{
+		resourceURI: valid["resource_uri"].(string),
+
+		ip:            valid["ip"].(string),
+		allocType:     IPAllocType(valid["alloc_type"].(int)),
+		allocTypeName: valid["alloc_type_name"].(string),
+		created:       valid["created"].(string),
+		owner:         owner,
+
+		subnet:       subnet,
+		interfaceSet: interfaceSet,
+	}
+	return result, nil
+}
@@ -0,0 +1,135 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type ipAddressSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&ipAddressSuite{})
+
+func (*ipAddressSuite) TestReadIPAddressesBadSchema(c *gc.C) {
+	_, err := readIPAddresses(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `ip address base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*ipAddressSuite) TestReadIPAddresses(c *gc.C) {
+	ipAddresses, err := readIPAddresses(twoDotOh, parseJSON(c, ipAddressesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipAddresses, gc.HasLen, 2)
+
+	address := ipAddresses[0]
+	c.Check(address.IP(), gc.Equals, "192.168.100.4")
+	c.Check(address.AllocType(), gc.Equals, IPAllocUserReserved)
+	c.Check(address.AllocTypeName(), gc.Equals, "User reserved")
+	c.Check(address.Created(), gc.Equals, "Tue, 11 Oct. 2016 01:20:02")
+	c.Check(address.Owner(), gc.Equals, "admin")
+	subnet := address.Subnet()
+	c.Assert(subnet, gc.NotNil)
+	c.Check(subnet.CIDR(), gc.Equals, "192.168.100.0/24")
+	c.Check(address.InterfaceSet(), gc.HasLen, 0)
+
+	address = ipAddresses[1]
+	c.Check(address.AllocType(), gc.Equals, IPAllocSticky)
+	c.Check(address.Owner(), gc.Equals, "")
+	c.Check(address.Subnet(), gc.IsNil)
+	interfaces := address.InterfaceSet()
+	c.Assert(interfaces, gc.HasLen, 1)
+	c.Check(interfaces[0].ID(), gc.Equals, 40)
+}
+
+func (*ipAddressSuite) TestReadIPAddressMinimal(c *gc.C) {
+	address, err := readIPAddress(twoDotOh, parseJSON(c, `{
+        "ip": "192.168.100.5",
+        "alloc_type": 4,
+        "resource_uri": "/MAAS/api/2.0/ipaddresses/"
+    }`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(address.IP(), gc.Equals, "192.168.100.5")
+	c.Check(address.Subnet(), gc.IsNil)
+	c.Check(address.InterfaceSet(), gc.HasLen, 0)
+}
+
+func (*ipAddressSuite) TestLowVersion(c *gc.C) {
+	_, err := readIPAddresses(version.MustParse("1.9.0"), parseJSON(c, ipAddressesResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*ipAddressSuite) TestHighVersion(c *gc.C) {
+	ipAddresses, err := readIPAddresses(version.MustParse("2.1.9"), parseJSON(c, ipAddressesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipAddresses, gc.HasLen, 2)
+}
+
+func (s *ipAddressSuite) TestSubnetAndInterfacesHaveController(c *gc.C) {
+	_, ctrl := createTestServerController(c, s)
+	ipAddresses, err := readIPAddresses(twoDotOh, parseJSON(c, ipAddressesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	for _, address := range ipAddresses {
+		address.controller = ctrl.(*controller)
+	}
+	c.Check(ipAddresses[0].Subnet().(*subnet).controller, gc.Equals, ctrl.(*controller))
+	c.Check(ipAddresses[1].InterfaceSet()[0].(*interface_).controller, gc.Equals, ctrl.(*controller))
+}
+
+var ipAddressResponse = `
+{
+    "alloc_type": 4,
+    "alloc_type_name": "User reserved",
+    "created": "Tue, 11 Oct. 2016 01:20:02",
+    "ip": "192.168.100.4",
+    "owner": {
+        "is_superuser": true,
+        "username": "admin",
+        "email": "admin@example.com",
+        "resource_uri": "/MAAS/api/2.0/users/admin/"
+    },
+    "subnet": {
+        "gateway_ip": "192.168.100.1",
+        "name": "192.168.100.0/24",
+        "vlan": {
+            "fabric": "fabric-0",
+            "resource_uri": "/MAAS/api/2.0/vlans/1/",
+            "name": "untagged",
+            "secondary_rack": null,
+            "primary_rack": "4y3h7n",
+            "vid": 0,
+            "dhcp_on": true,
+            "id": 1,
+            "mtu": 1500
+        },
+        "space": "space-0",
+        "id": 1,
+        "resource_uri": "/MAAS/api/2.0/subnets/1/",
+        "dns_servers": [],
+        "cidr": "192.168.100.0/24",
+        "rdns_mode": 2
+    },
+    "interface_set": [],
+    "resource_uri": "/MAAS/api/2.0/ipaddresses/"
+}
+`
+
+var ipAddressesResponse = `
+[` + ipAddressResponse + `,
+    {
+        "alloc_type": 1,
+        "alloc_type_name": "Sticky",
+        "created": "Tue, 11 Oct. 2016 01:22:13",
+        "ip": "10.0.0.4",
+        "owner": null,
+        "subnet": null,
+        "interface_set": [` + interfaceResponse + `],
+        "resource_uri": "/MAAS/api/2.0/ipaddresses/"
+    }
+]
+`
@@ -0,0 +1,243 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+// IPRangeType is the type of an IP range.
+type IPRangeType string
+
+const (
+	// IPRangeDynamic ranges are used by MAAS for DHCP.
+	IPRangeDynamic IPRangeType = "dynamic"
+
+	// IPRangeReserved ranges are never allocated by MAAS.
+	IPRangeReserved IPRangeType = "reserved"
+)
+
+type ipRange struct {
+	controller *controller
+
+	resourceURI string
+
+	id      int
+	type_   string
+	startIP string
+	endIP   string
+	comment string
+
+	subnet *subnet
+}
+
+func (r *ipRange) updateFrom(other *ipRange) {
+	r.resourceURI = other.resourceURI
+	r.id = other.id
+	r.type_ = other.type_
+	r.startIP = other.startIP
+	r.endIP = other.endIP
+	r.comment = other.comment
+	r.subnet = other.subnet
+}
+
+// ID implements IPRange.
+func (r *ipRange) ID() int {
+	return r.id
+}
+
+// Type implements IPRange.
+func (r *ipRange) Type() string {
+	return r.type_
+}
+
+// StartIP implements IPRange.
+func (r *ipRange) StartIP() string {
+	return r.startIP
+}
+
+// EndIP implements IPRange.
+func (r *ipRange) EndIP() string {
+	return r.endIP
+}
+
+// Comment implements IPRange.
+func (r *ipRange) Comment() string {
+	return r.comment
+}
+
+// Subnet implements IPRange.
+func (r *ipRange) Subnet() Subnet {
+	if r.subnet == nil {
+		return nil
+	}
+	r.subnet.controller = r.controller
+	return r.subnet
+}
+
+// UpdateIPRangeArgs is an argument struct for passing parameters to the
+// IPRange.Update method. Only the values that are specified are changed.
+type UpdateIPRangeArgs struct {
+	Type    IPRangeType
+	StartIP string
+	EndIP   string
+	Comment string
+}
+
+// Validate ensures that the Type is known, if it is specified.
+func (a *UpdateIPRangeArgs) Validate() error {
+	switch a.Type {
+	case "", IPRangeDynamic, IPRangeReserved:
+	default:
+		return errors.NotValidf("unknown Type value (%q)", a.Type)
+	}
+	return nil
+}
+
+// Update implements IPRange.
+func (r *ipRange) Update(args UpdateIPRangeArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("type", string(args.Type))
+	params.MaybeAdd("start_ip", args.StartIP)
+	params.MaybeAdd("end_ip", args.EndIP)
+	params.MaybeAdd("comment", args.Comment)
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := r.controller.put(r.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readIPRange(r.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.updateFrom(response)
+	return nil
+}
+
+// Delete implements IPRange.
+func (r *ipRange) Delete() error {
+	err := r.controller.delete(r.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+func readIPRange(controllerVersion version.Number, source interface{}) (*ipRange, error) {
+	readFunc, err := getIPRangeDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ip range base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readIPRanges(controllerVersion version.Number, source interface{}) ([]*ipRange, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ip range base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getIPRangeDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readIPRangeList(valid, readFunc)
+}
+
+func getIPRangeDeserializationFunc(controllerVersion version.Number) (ipRangeDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range ipRangeDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no ip range read func for version %s", controllerVersion)
+	}
+	return ipRangeDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readIPRangeList expects the values of the sourceList to be string maps.
+func readIPRangeList(sourceList []interface{}, readFunc ipRangeDeserializationFunc) ([]*ipRange, error) {
+	result := make([]*ipRange, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for ip range %d, %T", i, value)
+		}
+		ipRange, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "ip range %d", i)
+		}
+		result = append(result, ipRange)
+	}
+	return result, nil
+}
+
+type ipRangeDeserializationFunc func(map[string]interface{}) (*ipRange, error)
+
+var ipRangeDeserializationFuncs = map[version.Number]ipRangeDeserializationFunc{
+	twoDotOh: ipRange_2_0,
+}
+
+func ipRange_2_0(source map[string]interface{}) (*ipRange, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":       schema.ForceInt(),
+		"type":     schema.String(),
+		"start_ip": schema.String(),
+		"end_ip":   schema.String(),
+		"comment":  schema.OneOf(schema.Nil(""), schema.String()),
+
+		"subnet": schema.StringMap(schema.Any()),
+	}
+	defaults := schema.Defaults{
+		"comment": "",
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ip range 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	subnet, err := subnet_2_0(valid["subnet"].(map[string]interface{}))
+	if err != nil {
+		return nil, errors.Annotate(err, "subnet")
+	}
+
+	comment, _ := valid["comment"].(string)
+	result := &ipRange{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:      valid["id"].(int),
+		type_:   valid["type"].(string),
+		startIP: valid["start_ip"].(string),
+		endIP:   valid["end_ip"].(string),
+		comment: comment,
+
+		subnet: subnet,
+	}
+	return result, nil
+}
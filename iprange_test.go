@@ -0,0 +1,224 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type ipRangeSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&ipRangeSuite{})
+
+func (*ipRangeSuite) TestReadIPRangesBadSchema(c *gc.C) {
+	_, err := readIPRanges(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `ip range base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*ipRangeSuite) TestReadIPRanges(c *gc.C) {
+	ipRanges, err := readIPRanges(twoDotOh, parseJSON(c, ipRangesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipRanges, gc.HasLen, 2)
+
+	ipRange := ipRanges[0]
+	c.Check(ipRange.ID(), gc.Equals, 1)
+	c.Check(ipRange.Type(), gc.Equals, "dynamic")
+	c.Check(ipRange.StartIP(), gc.Equals, "192.168.100.100")
+	c.Check(ipRange.EndIP(), gc.Equals, "192.168.100.200")
+	c.Check(ipRange.Comment(), gc.Equals, "")
+	subnet := ipRange.Subnet()
+	c.Assert(subnet, gc.NotNil)
+	c.Check(subnet.ID(), gc.Equals, 1)
+	c.Check(subnet.CIDR(), gc.Equals, "192.168.100.0/24")
+
+	ipRange = ipRanges[1]
+	c.Check(ipRange.Type(), gc.Equals, "reserved")
+	c.Check(ipRange.Comment(), gc.Equals, "switches")
+}
+
+func (*ipRangeSuite) TestLowVersion(c *gc.C) {
+	_, err := readIPRanges(version.MustParse("1.9.0"), parseJSON(c, ipRangesResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*ipRangeSuite) TestHighVersion(c *gc.C) {
+	ipRanges, err := readIPRanges(version.MustParse("2.1.9"), parseJSON(c, ipRangesResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ipRanges, gc.HasLen, 2)
+}
+
+func (s *ipRangeSuite) getServerAndIPRange(c *gc.C) (*SimpleTestServer, *ipRange) {
+	server, ctrl := createTestServerController(c, s)
+	ipRange, err := readIPRange(twoDotOh, parseJSON(c, ipRangeResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	ipRange.controller = ctrl.(*controller)
+	return server, ipRange
+}
+
+func (s *ipRangeSuite) TestSubnetHasController(c *gc.C) {
+	_, ipRange := s.getServerAndIPRange(c)
+	subnet := ipRange.Subnet().(*subnet)
+	c.Assert(subnet.controller, gc.Equals, ipRange.controller)
+}
+
+func (s *ipRangeSuite) TestUpdateArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    UpdateIPRangeArgs
+		errText string
+	}{{
+		args: UpdateIPRangeArgs{},
+	}, {
+		args: UpdateIPRangeArgs{Type: IPRangeReserved},
+	}, {
+		args:    UpdateIPRangeArgs{Type: "wat"},
+		errText: `unknown Type value ("wat") not valid`,
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *ipRangeSuite) TestUpdate(c *gc.C) {
+	server, ipRange := s.getServerAndIPRange(c)
+	response := updateJSONMap(c, ipRangeResponse, map[string]interface{}{
+		"type":    "reserved",
+		"end_ip":  "192.168.100.150",
+		"comment": "shrunk",
+	})
+	server.AddPutResponse(ipRange.resourceURI, http.StatusOK, response)
+	err := ipRange.Update(UpdateIPRangeArgs{
+		Type:    IPRangeReserved,
+		EndIP:   "192.168.100.150",
+		Comment: "shrunk",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(ipRange.Type(), gc.Equals, "reserved")
+	c.Check(ipRange.EndIP(), gc.Equals, "192.168.100.150")
+	c.Check(ipRange.Comment(), gc.Equals, "shrunk")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 3)
+	c.Check(form.Get("type"), gc.Equals, "reserved")
+	c.Check(form.Get("end_ip"), gc.Equals, "192.168.100.150")
+	c.Check(form.Get("comment"), gc.Equals, "shrunk")
+}
+
+func (s *ipRangeSuite) TestUpdateNothing(c *gc.C) {
+	server, ipRange := s.getServerAndIPRange(c)
+	count := server.RequestCount()
+	err := ipRange.Update(UpdateIPRangeArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *ipRangeSuite) TestUpdateBadRequest(c *gc.C) {
+	server, ipRange := s.getServerAndIPRange(c)
+	server.AddPutResponse(ipRange.resourceURI, http.StatusBadRequest, "overlaps")
+	err := ipRange.Update(UpdateIPRangeArgs{StartIP: "192.168.100.1"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "overlaps")
+}
+
+func (s *ipRangeSuite) TestDelete(c *gc.C) {
+	server, ipRange := s.getServerAndIPRange(c)
+	server.AddDeleteResponse(ipRange.resourceURI, http.StatusNoContent, "")
+	err := ipRange.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ipRangeSuite) TestDeleteMissing(c *gc.C) {
+	_, ipRange := s.getServerAndIPRange(c)
+	err := ipRange.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+var ipRangeResponse = `
+{
+    "id": 1,
+    "type": "dynamic",
+    "start_ip": "192.168.100.100",
+    "end_ip": "192.168.100.200",
+    "comment": null,
+    "user": {
+        "is_superuser": true,
+        "username": "admin",
+        "email": "admin@example.com",
+        "resource_uri": "/MAAS/api/2.0/users/admin/"
+    },
+    "subnet": {
+        "gateway_ip": "192.168.100.1",
+        "name": "192.168.100.0/24",
+        "vlan": {
+            "fabric": "fabric-0",
+            "resource_uri": "/MAAS/api/2.0/vlans/1/",
+            "name": "untagged",
+            "secondary_rack": null,
+            "primary_rack": "4y3h7n",
+            "vid": 0,
+            "dhcp_on": true,
+            "id": 1,
+            "mtu": 1500
+        },
+        "space": "space-0",
+        "id": 1,
+        "resource_uri": "/MAAS/api/2.0/subnets/1/",
+        "dns_servers": [],
+        "cidr": "192.168.100.0/24",
+        "allow_proxy": true,
+        "managed": true,
+        "rdns_mode": 2
+    },
+    "resource_uri": "/MAAS/api/2.0/ipranges/1/"
+}
+`
+
+var ipRangesResponse = `
+[` + ipRangeResponse + `,
+    {
+        "id": 2,
+        "type": "reserved",
+        "start_ip": "192.168.100.2",
+        "end_ip": "192.168.100.9",
+        "comment": "switches",
+        "user": null,
+        "subnet": {
+            "gateway_ip": "192.168.100.1",
+            "name": "192.168.100.0/24",
+            "vlan": {
+                "fabric": "fabric-0",
+                "resource_uri": "/MAAS/api/2.0/vlans/1/",
+                "name": "untagged",
+                "secondary_rack": null,
+                "primary_rack": "4y3h7n",
+                "vid": 0,
+                "dhcp_on": true,
+                "id": 1,
+                "mtu": 1500
+            },
+            "space": "space-0",
+            "id": 1,
+            "resource_uri": "/MAAS/api/2.0/subnets/1/",
+            "dns_servers": [],
+            "cidr": "192.168.100.0/24",
+            "rdns_mode": 2
+        },
+        "resource_uri": "/MAAS/api/2.0/ipranges/2/"
+    }
+]
+`
@@ -10,6 +10,8 @@ import (
 )
 
 type link struct {
+	controller *controller
+
 	id        int
 	mode      string
 	subnet    *subnet
@@ -34,6 +36,7 @@ func (k *link) Subnet() Subnet {
 	if k.subnet == nil {
 		return nil
 	}
+	k.subnet.controller = k.controller
 	return k.subnet
 }
 
@@ -0,0 +1,252 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"fmt"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type logicalVolume struct {
+	controller *controller
+
+	// resourceURI is the block device URI of the logical volume, while
+	// volumeGroupURI is that of the volume group it belongs to.
+	resourceURI    string
+	volumeGroupURI string
+
+	id      int
+	name    string
+	path    string
+	uuid    string
+	usedFor string
+	size    uint64
+	tags    []string
+
+	filesystem *filesystem
+}
+
+func (l *logicalVolume) updateFrom(other *logicalVolume) {
+	l.resourceURI = other.resourceURI
+	l.id = other.id
+	l.name = other.name
+	l.path = other.path
+	l.uuid = other.uuid
+	l.usedFor = other.usedFor
+	l.size = other.size
+	l.tags = other.tags
+	l.filesystem = other.filesystem
+}
+
+// Type implements LogicalVolume.
+func (l *logicalVolume) Type() string {
+	return "logicalvolume"
+}
+
+// ID implements LogicalVolume.
+func (l *logicalVolume) ID() int {
+	return l.id
+}
+
+// Name implements LogicalVolume.
+func (l *logicalVolume) Name() string {
+	return l.name
+}
+
+// Path implements LogicalVolume.
+func (l *logicalVolume) Path() string {
+	return l.path
+}
+
+// FileSystem implements LogicalVolume.
+func (l *logicalVolume) FileSystem() FileSystem {
+	if l.filesystem == nil {
+		return nil
+	}
+	return l.filesystem
+}
+
+// UUID implements LogicalVolume.
+func (l *logicalVolume) UUID() string {
+	return l.uuid
+}
+
+// UsedFor implements LogicalVolume.
+func (l *logicalVolume) UsedFor() string {
+	return l.usedFor
+}
+
+// Size implements LogicalVolume.
+func (l *logicalVolume) Size() uint64 {
+	return l.size
+}
+
+// Tags implements LogicalVolume.
+func (l *logicalVolume) Tags() []string {
+	return l.tags
+}
+
+// Format implements LogicalVolume.
+func (l *logicalVolume) Format(args FormatStorageDeviceArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("fstype", args.FSType)
+	params.MaybeAdd("uuid", args.UUID)
+	params.MaybeAdd("label", args.Label)
+	return errors.Trace(l.postOp("format", params))
+}
+
+// Unformat implements LogicalVolume.
+func (l *logicalVolume) Unformat() error {
+	return errors.Trace(l.postOp("unformat", NewURLParams()))
+}
+
+// Mount implements LogicalVolume.
+func (l *logicalVolume) Mount(args MountStorageDeviceArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("mount_point", args.MountPoint)
+	params.MaybeAdd("mount_options", args.MountOptions)
+	return errors.Trace(l.postOp("mount", params))
+}
+
+// Unmount implements LogicalVolume.
+func (l *logicalVolume) Unmount() error {
+	return errors.Trace(l.postOp("unmount", NewURLParams()))
+}
+
+// Delete implements LogicalVolume.
+func (l *logicalVolume) Delete() error {
+	params := NewURLParams()
+	params.Values.Add("id", fmt.Sprint(l.id))
+	_, err := l.controller._postRaw(l.volumeGroupURI, "delete_logical_volume", params.Values, nil)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// postOp calls the specified block device operation on the logical volume
+// and refreshes the logical volume from the response.
+func (l *logicalVolume) postOp(op string, params *URLParams) error {
+	source, err := l.controller.post(l.resourceURI, op, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readLogicalVolume(l.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	l.updateFrom(response)
+	return nil
+}
+
+func readLogicalVolume(controllerVersion version.Number, source interface{}) (*logicalVolume, error) {
+	readFunc, err := getLogicalVolumeDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "logical volume base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func getLogicalVolumeDeserializationFunc(controllerVersion version.Number) (logicalVolumeDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range logicalVolumeDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no logical volume read func for version %s", controllerVersion)
+	}
+	return logicalVolumeDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readLogicalVolumeList expects the values of the sourceList to be string maps.
+func readLogicalVolumeList(sourceList []interface{}, readFunc logicalVolumeDeserializationFunc) ([]*logicalVolume, error) {
+	result := make([]*logicalVolume, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for logical volume %d, %T", i, value)
+		}
+		logicalVolume, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "logical volume %d", i)
+		}
+		result = append(result, logicalVolume)
+	}
+	return result, nil
+}
+
+type logicalVolumeDeserializationFunc func(map[string]interface{}) (*logicalVolume, error)
+
+var logicalVolumeDeserializationFuncs = map[version.Number]logicalVolumeDeserializationFunc{
+	twoDotOh: logicalVolume_2_0,
+}
+
+func logicalVolume_2_0(source map[string]interface{}) (*logicalVolume, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":       schema.ForceInt(),
+		"name":     schema.String(),
+		"path":     schema.String(),
+		"uuid":     schema.OneOf(schema.Nil(""), schema.String()),
+		"used_for": schema.String(),
+		"size":     schema.ForceUint(),
+		"tags":     schema.List(schema.String()),
+
+		"filesystem": schema.OneOf(schema.Nil(""), schema.StringMap(schema.Any())),
+	}
+	defaults := schema.Defaults{
+		"tags": []string{},
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "logical volume 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	var filesystem *filesystem
+	if fsSource, ok := valid["filesystem"].(map[string]interface{}); ok {
+		if filesystem, err = filesystem2_0(fsSource); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	uuid, _ := valid["uuid"].(string)
+	result := &logicalVolume{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:      valid["id"].(int),
+		name:    valid["name"].(string),
+		path:    valid["path"].(string),
+		uuid:    uuid,
+		usedFor: valid["used_for"].(string),
+		size:    valid["size"].(uint64),
+		tags:    convertToStringSlice(valid["tags"]),
+
+		filesystem: filesystem,
+	}
+	return result, nil
+}
@@ -0,0 +1,156 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type logicalVolumeSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&logicalVolumeSuite{})
+
+func (*logicalVolumeSuite) TestTypeLogicalVolume(c *gc.C) {
+	var empty logicalVolume
+	c.Assert(empty.Type() == "logicalvolume", jc.IsTrue)
+}
+
+func (*logicalVolumeSuite) TestNilFileSystem(c *gc.C) {
+	var empty logicalVolume
+	c.Assert(empty.FileSystem() == nil, jc.IsTrue)
+}
+
+func (*logicalVolumeSuite) TestReadLogicalVolumeBadSchema(c *gc.C) {
+	_, err := readLogicalVolume(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `logical volume base schema check failed: expected map, got string("wat?")`)
+}
+
+func (*logicalVolumeSuite) TestReadLogicalVolume(c *gc.C) {
+	lv, err := readLogicalVolume(twoDotOh, parseJSON(c, logicalVolumeResponse))
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(lv.Type(), gc.Equals, "logicalvolume")
+	c.Check(lv.ID(), gc.Equals, 47)
+	c.Check(lv.Name(), gc.Equals, "vg0-lv0")
+	c.Check(lv.Path(), gc.Equals, "/dev/disk/by-dname/vg0-lv0")
+	c.Check(lv.UUID(), gc.Equals, "e2d9c1b8-6d3a-4e55-8f5a-3c1a0e9b7d26")
+	c.Check(lv.UsedFor(), gc.Equals, "ext4 formatted filesystem mounted at /srv")
+	c.Check(lv.Size(), gc.Equals, uint64(4294967296))
+	c.Check(lv.Tags(), gc.DeepEquals, []string{})
+
+	fs := lv.FileSystem()
+	c.Assert(fs, gc.NotNil)
+	c.Assert(fs.Type(), gc.Equals, "ext4")
+	c.Assert(fs.MountPoint(), gc.Equals, "/srv")
+}
+
+func (*logicalVolumeSuite) TestLowVersion(c *gc.C) {
+	_, err := readLogicalVolume(version.MustParse("1.9.0"), parseJSON(c, logicalVolumeResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (s *logicalVolumeSuite) getServerAndLogicalVolume(c *gc.C) (*SimpleTestServer, *logicalVolume) {
+	server, ctrl := createTestServerController(c, s)
+	vg, err := readVolumeGroup(twoDotOh, parseJSON(c, volumeGroupResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	vg.controller = ctrl.(*controller)
+	return server, vg.LogicalVolumes()[0].(*logicalVolume)
+}
+
+func (s *logicalVolumeSuite) TestFormat(c *gc.C) {
+	server, lv := s.getServerAndLogicalVolume(c)
+	response := updateJSONMap(c, logicalVolumeResponse, map[string]interface{}{
+		"filesystem": map[string]interface{}{
+			"fstype": "xfs",
+			"label":  "data",
+			"uuid":   "d2c0b4a5-0e9e-4c10-9c5b-77e1e6c08f6b",
+		},
+	})
+	server.AddPostResponse(lv.resourceURI+"?op=format", http.StatusOK, response)
+
+	err := lv.Format(FormatStorageDeviceArgs{FSType: "xfs", Label: "data"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lv.FileSystem().Type(), gc.Equals, "xfs")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 2)
+	c.Check(form.Get("fstype"), gc.Equals, "xfs")
+	c.Check(form.Get("label"), gc.Equals, "data")
+}
+
+func (s *logicalVolumeSuite) TestFormatValidates(c *gc.C) {
+	_, lv := s.getServerAndLogicalVolume(c)
+	err := lv.Format(FormatStorageDeviceArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *logicalVolumeSuite) TestMount(c *gc.C) {
+	server, lv := s.getServerAndLogicalVolume(c)
+	response := updateJSONMap(c, logicalVolumeResponse, map[string]interface{}{
+		"filesystem": map[string]interface{}{
+			"fstype":      "ext4",
+			"mount_point": "/var/lib",
+			"uuid":        "fcd7745e-f1b5-4f5d-9575-9b0bb796b752",
+		},
+	})
+	server.AddPostResponse(lv.resourceURI+"?op=mount", http.StatusOK, response)
+
+	err := lv.Mount(MountStorageDeviceArgs{MountPoint: "/var/lib"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(lv.FileSystem().MountPoint(), gc.Equals, "/var/lib")
+}
+
+func (s *logicalVolumeSuite) TestDelete(c *gc.C) {
+	server, lv := s.getServerAndLogicalVolume(c)
+	server.AddPostResponse(lv.volumeGroupURI+"?op=delete_logical_volume", http.StatusNoContent, "")
+	err := lv.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 1)
+	c.Check(form.Get("id"), gc.Equals, "47")
+}
+
+func (s *logicalVolumeSuite) TestDeleteForbidden(c *gc.C) {
+	server, lv := s.getServerAndLogicalVolume(c)
+	server.AddPostResponse(lv.volumeGroupURI+"?op=delete_logical_volume", http.StatusForbidden, "")
+	err := lv.Delete()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+const logicalVolumeResponse = `
+    {
+        "path": "/dev/disk/by-dname/vg0-lv0",
+        "name": "vg0-lv0",
+        "used_for": "ext4 formatted filesystem mounted at /srv",
+        "partitions": [],
+        "filesystem": {
+            "fstype": "ext4",
+            "mount_point": "/srv",
+            "label": null,
+            "mount_options": null,
+            "uuid": "0c4c1b5f-8e2d-4b79-a0a1-8b86e2b0f1e3"
+        },
+        "id_path": null,
+        "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/47/",
+        "id": 47,
+        "type": "virtual",
+        "block_size": 4096,
+        "used_size": 4294967296,
+        "available_size": 0,
+        "uuid": "e2d9c1b8-6d3a-4e55-8f5a-3c1a0e9b7d26",
+        "size": 4294967296,
+        "model": null,
+        "tags": []
+    }
+`
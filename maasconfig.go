@@ -0,0 +1,260 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+)
+
+// MAASConfig is a snapshot of the settings of MAAS that are commonly set
+// when a region is built. Use Controller.Config and Controller.SetConfig
+// for the other settings.
+type MAASConfig struct {
+	MAASName string
+
+	// DefaultOSystem and DefaultDistroSeries are used to deploy machines
+	// when the operating system isn't specified.
+	DefaultOSystem      string
+	DefaultDistroSeries string
+	DefaultMinHWEKernel string
+
+	CommissioningDistroSeries string
+
+	// KernelOpts are the kernel parameters of all the machines.
+	KernelOpts string
+
+	NTPServers      []string
+	NTPExternalOnly bool
+
+	UpstreamDNS      []string
+	DNSSECValidation string
+
+	HTTPProxy       string
+	EnableHTTPProxy bool
+	UsePeerProxy    bool
+}
+
+// maasConfigNames are the names of the settings that are in a MAASConfig.
+var maasConfigNames = []string{
+	"maas_name",
+	"default_osystem",
+	"default_distro_series",
+	"default_min_hwe_kernel",
+	"commissioning_distro_series",
+	"kernel_opts",
+	"ntp_servers",
+	"ntp_external_only",
+	"upstream_dns",
+	"dnssec_validation",
+	"http_proxy",
+	"enable_http_proxy",
+	"use_peer_proxy",
+}
+
+// values returns the settings of the config by name, with the types that
+// are accepted by Controller.SetConfig.
+func (c *MAASConfig) values() map[string]interface{} {
+	return map[string]interface{}{
+		"maas_name":                   c.MAASName,
+		"default_osystem":             c.DefaultOSystem,
+		"default_distro_series":       c.DefaultDistroSeries,
+		"default_min_hwe_kernel":      c.DefaultMinHWEKernel,
+		"commissioning_distro_series": c.CommissioningDistroSeries,
+		"kernel_opts":                 c.KernelOpts,
+		"ntp_servers":                 c.NTPServers,
+		"ntp_external_only":           c.NTPExternalOnly,
+		"upstream_dns":                c.UpstreamDNS,
+		"dnssec_validation":           c.DNSSECValidation,
+		"http_proxy":                  c.HTTPProxy,
+		"enable_http_proxy":           c.EnableHTTPProxy,
+		"use_peer_proxy":              c.UsePeerProxy,
+	}
+}
+
+// DesiredMAASConfig holds the settings that MAASConfig.Diff compares with
+// a snapshot. Only the settings that are not nil are compared, and an empty
+// string or slice is a desired value that clears the setting.
+type DesiredMAASConfig struct {
+	MAASName *string
+
+	DefaultOSystem      *string
+	DefaultDistroSeries *string
+	DefaultMinHWEKernel *string
+
+	CommissioningDistroSeries *string
+
+	KernelOpts *string
+
+	NTPServers      []string
+	NTPExternalOnly *bool
+
+	UpstreamDNS      []string
+	DNSSECValidation *string
+
+	HTTPProxy       *string
+	EnableHTTPProxy *bool
+	UsePeerProxy    *bool
+}
+
+// values returns the settings that are set by name, with the types that
+// are accepted by Controller.SetConfig.
+func (d *DesiredMAASConfig) values() map[string]interface{} {
+	result := make(map[string]interface{})
+	addString := func(name string, value *string) {
+		if value != nil {
+			result[name] = *value
+		}
+	}
+	addBool := func(name string, value *bool) {
+		if value != nil {
+			result[name] = *value
+		}
+	}
+	addList := func(name string, value []string) {
+		if value != nil {
+			result[name] = value
+		}
+	}
+	addString("maas_name", d.MAASName)
+	addString("default_osystem", d.DefaultOSystem)
+	addString("default_distro_series", d.DefaultDistroSeries)
+	addString("default_min_hwe_kernel", d.DefaultMinHWEKernel)
+	addString("commissioning_distro_series", d.CommissioningDistroSeries)
+	addString("kernel_opts", d.KernelOpts)
+	addList("ntp_servers", d.NTPServers)
+	addBool("ntp_external_only", d.NTPExternalOnly)
+	addList("upstream_dns", d.UpstreamDNS)
+	addString("dnssec_validation", d.DNSSECValidation)
+	addString("http_proxy", d.HTTPProxy)
+	addBool("enable_http_proxy", d.EnableHTTPProxy)
+	addBool("use_peer_proxy", d.UsePeerProxy)
+	return result
+}
+
+// ConfigChange is a setting that is different in the desired config.
+type ConfigChange struct {
+	Name    string
+	Current interface{}
+	Desired interface{}
+}
+
+// Diff returns the settings that need to be changed to make MAAS match the
+// desired config, ordered by name. The Desired values of the changes can be
+// passed to Controller.SetConfig. The settings that aren't set in the
+// desired config are left alone.
+func (c MAASConfig) Diff(desired DesiredMAASConfig) []ConfigChange {
+	current := c.values()
+	var result []ConfigChange
+	for name, value := range desired.values() {
+		if configValuesEqual(current[name], value) {
+			continue
+		}
+		result = append(result, ConfigChange{
+			Name:    name,
+			Current: current[name],
+			Desired: value,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// configValuesEqual treats nil and empty lists as equal, as MAAS doesn't
+// distinguish between them.
+func configValuesEqual(a, b interface{}) bool {
+	aList, aIsList := a.([]string)
+	bList, bIsList := b.([]string)
+	if aIsList && bIsList && len(aList) == 0 && len(bList) == 0 {
+		return true
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// configValueString converts the value of a setting to the form that MAAS
+// accepts. Lists are separated by spaces.
+func configValueString(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case int, int64, float64:
+		return fmt.Sprint(v), nil
+	case []string:
+		return strings.Join(v, " "), nil
+	}
+	return "", errors.NotValidf("config value of type %T", value)
+}
+
+// readMAASConfig reads the settings of a MAASConfig, as they are decoded
+// from the JSON values that MAAS returns.
+func readMAASConfig(source map[string]interface{}) (MAASConfig, error) {
+	optionalString := schema.OneOf(schema.Nil(""), schema.String())
+	optionalBool := schema.OneOf(schema.Nil(""), schema.Bool())
+	fields := schema.Fields{
+		"maas_name":                   optionalString,
+		"default_osystem":             optionalString,
+		"default_distro_series":       optionalString,
+		"default_min_hwe_kernel":      optionalString,
+		"commissioning_distro_series": optionalString,
+		"kernel_opts":                 optionalString,
+		// Lists of servers are space or comma separated strings.
+		"ntp_servers":       optionalString,
+		"ntp_external_only": optionalBool,
+		"upstream_dns":      optionalString,
+		"dnssec_validation": optionalString,
+		"http_proxy":        optionalString,
+		"enable_http_proxy": optionalBool,
+		"use_peer_proxy":    optionalBool,
+	}
+	checker := schema.FieldMap(fields, nil)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return MAASConfig{}, WrapWithDeserializationError(err, "maas config schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	str := func(name string) string {
+		value, _ := valid[name].(string)
+		return value
+	}
+	list := func(name string) []string {
+		return strings.FieldsFunc(str(name), func(r rune) bool {
+			return r == ',' || r == ' '
+		})
+	}
+	boolean := func(name string) bool {
+		value, _ := valid[name].(bool)
+		return value
+	}
+	result := MAASConfig{
+		MAASName:                  str("maas_name"),
+		DefaultOSystem:            str("default_osystem"),
+		DefaultDistroSeries:       str("default_distro_series"),
+		DefaultMinHWEKernel:       str("default_min_hwe_kernel"),
+		CommissioningDistroSeries: str("commissioning_distro_series"),
+		KernelOpts:                str("kernel_opts"),
+		NTPServers:                list("ntp_servers"),
+		NTPExternalOnly:           boolean("ntp_external_only"),
+		UpstreamDNS:               list("upstream_dns"),
+		DNSSECValidation:          str("dnssec_validation"),
+		HTTPProxy:                 str("http_proxy"),
+		EnableHTTPProxy:           boolean("enable_http_proxy"),
+		UsePeerProxy:              boolean("use_peer_proxy"),
+	}
+	return result, nil
+}
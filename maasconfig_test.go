@@ -0,0 +1,180 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type maasConfigSuite struct{}
+
+var _ = gc.Suite(&maasConfigSuite{})
+
+func (*maasConfigSuite) TestReadMAASConfig(c *gc.C) {
+	config, err := readMAASConfig(parseJSON(c, maasConfigResponse).(map[string]interface{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(config, jc.DeepEquals, MAASConfig{
+		MAASName:                  "region-1",
+		DefaultOSystem:            "ubuntu",
+		DefaultDistroSeries:       "jammy",
+		DefaultMinHWEKernel:       "",
+		CommissioningDistroSeries: "jammy",
+		KernelOpts:                "console=ttyS0,115200",
+		NTPServers:                []string{"ntp.ubuntu.com", "pool.ntp.org"},
+		NTPExternalOnly:           false,
+		UpstreamDNS:               []string{"8.8.8.8", "8.8.4.4"},
+		DNSSECValidation:          "auto",
+		HTTPProxy:                 "",
+		EnableHTTPProxy:           true,
+		UsePeerProxy:              false,
+	})
+}
+
+func (*maasConfigSuite) TestReadMAASConfigBadSchema(c *gc.C) {
+	source := parseJSON(c, maasConfigResponse).(map[string]interface{})
+	source["enable_http_proxy"] = "maybe"
+	_, err := readMAASConfig(source)
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err, gc.ErrorMatches, `maas config schema check failed: enable_http_proxy: .*`)
+}
+
+func (*maasConfigSuite) TestMAASConfigNames(c *gc.C) {
+	config := MAASConfig{}
+	values := config.values()
+	c.Assert(values, gc.HasLen, len(maasConfigNames))
+	for _, name := range maasConfigNames {
+		_, found := values[name]
+		c.Check(found, jc.IsTrue, gc.Commentf("%s", name))
+	}
+}
+
+func (*maasConfigSuite) TestDesiredMAASConfigNames(c *gc.C) {
+	desired := DesiredMAASConfig{}
+	c.Assert(desired.values(), gc.HasLen, 0)
+
+	str, boolean := "", false
+	desired = DesiredMAASConfig{
+		MAASName:                  &str,
+		DefaultOSystem:            &str,
+		DefaultDistroSeries:       &str,
+		DefaultMinHWEKernel:       &str,
+		CommissioningDistroSeries: &str,
+		KernelOpts:                &str,
+		NTPServers:                []string{},
+		NTPExternalOnly:           &boolean,
+		UpstreamDNS:               []string{},
+		DNSSECValidation:          &str,
+		HTTPProxy:                 &str,
+		EnableHTTPProxy:           &boolean,
+		UsePeerProxy:              &boolean,
+	}
+	values := desired.values()
+	c.Assert(values, gc.HasLen, len(maasConfigNames))
+	for _, name := range maasConfigNames {
+		_, found := values[name]
+		c.Check(found, jc.IsTrue, gc.Commentf("%s", name))
+	}
+}
+
+func (*maasConfigSuite) TestDiff(c *gc.C) {
+	current, err := readMAASConfig(parseJSON(c, maasConfigResponse).(map[string]interface{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(current.Diff(DesiredMAASConfig{}), gc.HasLen, 0)
+
+	// Settings that are already the desired values aren't changed.
+	maasName, httpProxy, enableHTTPProxy := current.MAASName, "http://squid.example.com:3128/", false
+	desired := DesiredMAASConfig{
+		MAASName:        &maasName,
+		NTPServers:      []string{"ntp.example.com"},
+		HTTPProxy:       &httpProxy,
+		EnableHTTPProxy: &enableHTTPProxy,
+	}
+	c.Assert(current.Diff(desired), jc.DeepEquals, []ConfigChange{{
+		Name:    "enable_http_proxy",
+		Current: true,
+		Desired: false,
+	}, {
+		Name:    "http_proxy",
+		Current: "",
+		Desired: "http://squid.example.com:3128/",
+	}, {
+		Name:    "ntp_servers",
+		Current: []string{"ntp.ubuntu.com", "pool.ntp.org"},
+		Desired: []string{"ntp.example.com"},
+	}})
+}
+
+func (*maasConfigSuite) TestDiffOnlySetSettings(c *gc.C) {
+	current, err := readMAASConfig(parseJSON(c, maasConfigResponse).(map[string]interface{}))
+	c.Assert(err, jc.ErrorIsNil)
+	desired := DesiredMAASConfig{NTPServers: []string{"ntp.example.com"}}
+	changes := current.Diff(desired)
+	c.Assert(changes, gc.HasLen, 1)
+	c.Check(changes[0].Name, gc.Equals, "ntp_servers")
+}
+
+func (*maasConfigSuite) TestDiffClears(c *gc.C) {
+	current := MAASConfig{KernelOpts: "console=ttyS0", UpstreamDNS: []string{"8.8.8.8"}}
+	empty := ""
+	desired := DesiredMAASConfig{KernelOpts: &empty, UpstreamDNS: []string{}}
+	c.Assert(current.Diff(desired), jc.DeepEquals, []ConfigChange{{
+		Name:    "kernel_opts",
+		Current: "console=ttyS0",
+		Desired: "",
+	}, {
+		Name:    "upstream_dns",
+		Current: []string{"8.8.8.8"},
+		Desired: []string{},
+	}})
+}
+
+func (*maasConfigSuite) TestDiffEmptyLists(c *gc.C) {
+	current := MAASConfig{UpstreamDNS: []string{}}
+	c.Assert(current.Diff(DesiredMAASConfig{UpstreamDNS: []string{}}), gc.HasLen, 0)
+	current = MAASConfig{}
+	c.Assert(current.Diff(DesiredMAASConfig{UpstreamDNS: []string{}}), gc.HasLen, 0)
+}
+
+func (*maasConfigSuite) TestConfigValueString(c *gc.C) {
+	for i, test := range []struct {
+		value    interface{}
+		expected string
+	}{
+		{nil, ""},
+		{"jammy", "jammy"},
+		{true, "true"},
+		{false, "false"},
+		{3, "3"},
+		{float64(1.5), "1.5"},
+		{[]string{"8.8.8.8", "8.8.4.4"}, "8.8.8.8 8.8.4.4"},
+	} {
+		c.Logf("test %d", i)
+		value, err := configValueString(test.value)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(value, gc.Equals, test.expected)
+	}
+	_, err := configValueString(map[string]string{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "config value of type map[string]string not valid")
+}
+
+const maasConfigResponse = `
+{
+    "maas_name": "region-1",
+    "default_osystem": "ubuntu",
+    "default_distro_series": "jammy",
+    "default_min_hwe_kernel": null,
+    "commissioning_distro_series": "jammy",
+    "kernel_opts": "console=ttyS0,115200",
+    "ntp_servers": "ntp.ubuntu.com, pool.ntp.org",
+    "ntp_external_only": false,
+    "upstream_dns": "8.8.8.8 8.8.4.4",
+    "dnssec_validation": "auto",
+    "http_proxy": null,
+    "enable_http_proxy": true,
+    "use_peer_proxy": false
+}
+`
@@ -7,7 +7,9 @@ import (
 	"fmt"
 	"net/http"
 	"net/url"
+	"strings"
 
+	"github.com/juju/collections/set"
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/version"
@@ -33,6 +35,7 @@ type machine struct {
 
 	ipAddresses []string
 	powerState  string
+	powerType   string
 
 	// NOTE: consider some form of status struct
 	statusName    string
@@ -60,12 +63,17 @@ func (m *machine) updateFrom(other *machine) {
 	m.hardwareInfo = other.hardwareInfo
 	m.ipAddresses = other.ipAddresses
 	m.powerState = other.powerState
+	m.powerType = other.powerType
 	m.statusName = other.statusName
 	m.statusMessage = other.statusMessage
 	m.zone = other.zone
 	m.pool = other.pool
 	m.tags = other.tags
 	m.ownerData = other.ownerData
+	m.bootInterface = other.bootInterface
+	m.interfaceSet = other.interfaceSet
+	m.physicalBlockDevices = other.physicalBlockDevices
+	m.blockDevices = other.blockDevices
 }
 
 // SystemID implements Machine.
@@ -93,6 +101,7 @@ func (m *machine) Pool() Pool {
 	if m.pool == nil {
 		return nil
 	}
+	m.pool.controller = m.controller
 	return m.pool
 }
 
@@ -129,11 +138,90 @@ func (m *machine) PowerState() string {
 	return m.powerState
 }
 
+// PowerType implements Machine.
+func (m *machine) PowerType() string {
+	return m.powerType
+}
+
+// PowerParameters implements Machine.
+func (m *machine) PowerParameters() (PowerParameters, error) {
+	source, err := m.controller.getOp(m.resourceURI, "power_parameters")
+	if err != nil {
+		if svrErr, ok := errors.Cause(err).(ServerError); ok {
+			switch svrErr.StatusCode {
+			case http.StatusNotFound:
+				return nil, errors.Wrap(err, NewNoMatchError(svrErr.BodyMessage))
+			case http.StatusForbidden:
+				return nil, errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
+			}
+		}
+		return nil, NewUnexpectedError(err)
+	}
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "power parameters schema check failed")
+	}
+	// Most drivers only use strings, but some values come back as numbers
+	// or booleans, so they are converted to the form MAAS accepts them in.
+	result := make(PowerParameters)
+	for key, value := range coerced.(map[string]interface{}) {
+		if value == nil {
+			result[key] = ""
+			continue
+		}
+		result[key] = fmt.Sprint(value)
+	}
+	return result, nil
+}
+
+// UpdatePowerParametersArgs is an argument struct for passing parameters to
+// the Machine.UpdatePowerParameters method.
+type UpdatePowerParametersArgs struct {
+	// PowerType is optional, and the current power type is kept if it
+	// is not specified.
+	PowerType  string
+	Parameters PowerParameters
+	// SkipCheck stops MAAS from validating the parameters against the
+	// power driver.
+	SkipCheck bool
+}
+
+// UpdatePowerParameters implements Machine.
+func (m *machine) UpdatePowerParameters(args UpdatePowerParametersArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("power_type", args.PowerType)
+	args.Parameters.addTo(params)
+	params.MaybeAddBool("power_parameters_skip_check", args.SkipCheck)
+	source, err := m.controller.put(m.resourceURI, params.Values)
+	if err != nil {
+		if svrErr, ok := errors.Cause(err).(ServerError); ok {
+			switch svrErr.StatusCode {
+			case http.StatusBadRequest:
+				return errors.Wrap(err, NewBadRequestError(svrErr.BodyMessage))
+			case http.StatusNotFound:
+				return errors.Wrap(err, NewNoMatchError(svrErr.BodyMessage))
+			case http.StatusForbidden:
+				return errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
+			}
+		}
+		return NewUnexpectedError(err)
+	}
+
+	machine, err := readMachine(m.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m.updateFrom(machine)
+	return nil
+}
+
 // Zone implements Machine.
 func (m *machine) Zone() Zone {
 	if m.zone == nil {
 		return nil
 	}
+	m.zone.controller = m.controller
 	return m.zone
 }
 
@@ -167,6 +255,221 @@ func (m *machine) Interface(id int) Interface {
 	return nil
 }
 
+// BondMode is the bonding policy of a bond interface.
+type BondMode string
+
+const (
+	BondBalanceRR    BondMode = "balance-rr"
+	BondActiveBackup BondMode = "active-backup"
+	BondBalanceXOR   BondMode = "balance-xor"
+	BondBroadcast    BondMode = "broadcast"
+	Bond8023AD       BondMode = "802.3ad"
+	BondBalanceTLB   BondMode = "balance-tlb"
+	BondBalanceALB   BondMode = "balance-alb"
+)
+
+// BondLACPRate is the rate at which LACPDU packets are requested from the
+// link partner of an 802.3ad bond.
+type BondLACPRate string
+
+const (
+	BondLACPRateFast BondLACPRate = "fast"
+	BondLACPRateSlow BondLACPRate = "slow"
+)
+
+// BondHashPolicy is the transmit hash policy used to select the slave for
+// balance-xor, 802.3ad and balance-tlb bonds.
+type BondHashPolicy string
+
+const (
+	BondHashLayer2  BondHashPolicy = "layer2"
+	BondHashLayer23 BondHashPolicy = "layer2+3"
+	BondHashLayer34 BondHashPolicy = "layer3+4"
+	BondHashEncap23 BondHashPolicy = "encap2+3"
+	BondHashEncap34 BondHashPolicy = "encap3+4"
+)
+
+// CreateBondArgs is an argument struct for passing parameters to
+// the Machine.CreateBond method.
+type CreateBondArgs struct {
+	// Name of the bond (required).
+	Name string
+	// Parents are the interfaces that are bonded together (required).
+	Parents []Interface
+	// MACAddress is optional, and the MAC address of the first parent is
+	// used if it isn't specified.
+	MACAddress string
+	// VLAN is the untagged VLAN the bond is connected to (optional).
+	VLAN VLAN
+	// Tags to attach to the interface (optional).
+	Tags []string
+	// MTU - Maximum transmission unit. (optional)
+	MTU int
+	// AcceptRA - Accept router advertisements. (IPv6 only)
+	AcceptRA bool
+	// Autoconf - Perform stateless autoconfiguration. (IPv6 only)
+	Autoconf bool
+
+	// Mode is optional, and MAAS uses balance-rr if it isn't specified.
+	Mode BondMode
+	// MIIMon is the link monitoring frequency in milliseconds.
+	MIIMon int
+	// DownDelay and UpDelay are the milliseconds to wait before disabling
+	// or enabling a slave after a link failure or recovery.
+	DownDelay int
+	UpDelay   int
+	// LACPRate is only used by 802.3ad bonds.
+	LACPRate BondLACPRate
+	// HashPolicy is only used by balance-xor, 802.3ad and balance-tlb bonds.
+	HashPolicy BondHashPolicy
+}
+
+// Validate checks the required fields are set for the arg structure.
+func (a *CreateBondArgs) Validate() error {
+	if a.Name == "" {
+		return errors.NotValidf("missing Name")
+	}
+	if len(a.Parents) == 0 {
+		return errors.NotValidf("missing Parents")
+	}
+	return nil
+}
+
+// CreateBond implements Machine.
+func (m *machine) CreateBond(args CreateBondArgs) (Interface, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("name", args.Name)
+	for _, parent := range args.Parents {
+		params.Values.Add("parents", fmt.Sprint(parent.ID()))
+	}
+	params.MaybeAdd("mac_address", args.MACAddress)
+	if args.VLAN != nil {
+		params.Values.Add("vlan", fmt.Sprint(args.VLAN.ID()))
+	}
+	params.MaybeAdd("tags", strings.Join(args.Tags, ","))
+	params.MaybeAddInt("mtu", args.MTU)
+	params.MaybeAddBool("accept_ra", args.AcceptRA)
+	params.MaybeAddBool("autoconf", args.Autoconf)
+	params.MaybeAdd("bond_mode", string(args.Mode))
+	params.MaybeAddInt("bond_miimon", args.MIIMon)
+	params.MaybeAddInt("bond_downdelay", args.DownDelay)
+	params.MaybeAddInt("bond_updelay", args.UpDelay)
+	params.MaybeAdd("bond_lacp_rate", string(args.LACPRate))
+	params.MaybeAdd("bond_xmit_hash_policy", string(args.HashPolicy))
+	return m.createInterface("create_bond", params)
+}
+
+// CreateBridgeArgs is an argument struct for passing parameters to
+// the Machine.CreateBridge method.
+type CreateBridgeArgs struct {
+	// Name of the bridge (required).
+	Name string
+	// Parent is the interface that is bridged (required).
+	Parent Interface
+	// MACAddress is optional, and the MAC address of the parent is used if
+	// it isn't specified.
+	MACAddress string
+	// VLAN is the untagged VLAN the bridge is connected to (optional).
+	VLAN VLAN
+	// Tags to attach to the interface (optional).
+	Tags []string
+	// MTU - Maximum transmission unit. (optional)
+	MTU int
+	// AcceptRA - Accept router advertisements. (IPv6 only)
+	AcceptRA bool
+	// Autoconf - Perform stateless autoconfiguration. (IPv6 only)
+	Autoconf bool
+
+	// STP turns on the spanning tree protocol for the bridge.
+	STP bool
+	// ForwardDelay is the bridge forward delay in seconds.
+	ForwardDelay int
+}
+
+// Validate checks the required fields are set for the arg structure.
+func (a *CreateBridgeArgs) Validate() error {
+	if a.Name == "" {
+		return errors.NotValidf("missing Name")
+	}
+	if a.Parent == nil {
+		return errors.NotValidf("missing Parent")
+	}
+	return nil
+}
+
+// CreateBridge implements Machine.
+func (m *machine) CreateBridge(args CreateBridgeArgs) (Interface, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("name", args.Name)
+	params.Values.Add("parent", fmt.Sprint(args.Parent.ID()))
+	params.MaybeAdd("mac_address", args.MACAddress)
+	if args.VLAN != nil {
+		params.Values.Add("vlan", fmt.Sprint(args.VLAN.ID()))
+	}
+	params.MaybeAdd("tags", strings.Join(args.Tags, ","))
+	params.MaybeAddInt("mtu", args.MTU)
+	params.MaybeAddBool("accept_ra", args.AcceptRA)
+	params.MaybeAddBool("autoconf", args.Autoconf)
+	params.MaybeAddBool("bridge_stp", args.STP)
+	params.MaybeAddInt("bridge_fd", args.ForwardDelay)
+	return m.createInterface("create_bridge", params)
+}
+
+// CreateVLANInterface implements Machine.
+func (m *machine) CreateVLANInterface(parent Interface, vlan VLAN) (Interface, error) {
+	if parent == nil {
+		return nil, errors.NotValidf("missing parent")
+	}
+	if vlan == nil {
+		return nil, errors.NotValidf("missing VLAN")
+	}
+	params := NewURLParams()
+	params.Values.Add("parent", fmt.Sprint(parent.ID()))
+	params.Values.Add("vlan", fmt.Sprint(vlan.ID()))
+	return m.createInterface("create_vlan", params)
+}
+
+// createInterface calls the specified create operation on the interfaces
+// of the machine, and adds the new interface to the interface set, with
+// the parents of the new interface updated to list it as a child.
+func (m *machine) createInterface(op string, params *URLParams) (Interface, error) {
+	source, err := m.controller.post(m.nodeURI("interfaces"), op, params.Values)
+	if err != nil {
+		if svrErr, ok := errors.Cause(err).(ServerError); ok {
+			switch svrErr.StatusCode {
+			case http.StatusBadRequest, http.StatusNotFound, http.StatusConflict:
+				return nil, errors.Wrap(err, NewBadRequestError(svrErr.BodyMessage))
+			case http.StatusForbidden:
+				return nil, errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
+			case http.StatusServiceUnavailable:
+				return nil, errors.Wrap(err, NewCannotCompleteError(svrErr.BodyMessage))
+			}
+		}
+		return nil, NewUnexpectedError(err)
+	}
+
+	iface, err := readInterface(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	iface.controller = m.controller
+
+	parents := set.NewStrings(iface.parents...)
+	for _, existing := range m.interfaceSet {
+		if parents.Contains(existing.name) && !set.NewStrings(existing.children...).Contains(iface.name) {
+			existing.children = append(existing.children, iface.name)
+		}
+	}
+	m.interfaceSet = append(m.interfaceSet, iface)
+	return iface, nil
+}
+
 // OperatingSystem implements Machine.
 func (m *machine) OperatingSystem() string {
 	return m.operatingSystem
@@ -196,6 +499,7 @@ func (m *machine) StatusMessage() string {
 func (m *machine) PhysicalBlockDevices() []BlockDevice {
 	result := make([]BlockDevice, len(m.physicalBlockDevices))
 	for i, v := range m.physicalBlockDevices {
+		v.controller = m.controller
 		result[i] = v
 	}
 	return result
@@ -210,6 +514,7 @@ func (m *machine) PhysicalBlockDevice(id int) BlockDevice {
 func (m *machine) BlockDevices() []BlockDevice {
 	result := make([]BlockDevice, len(m.blockDevices))
 	for i, v := range m.blockDevices {
+		v.controller = m.controller
 		result[i] = v
 	}
 	return result
@@ -279,19 +584,532 @@ func (m *machine) Start(args StartArgs) error {
 	params.MaybeAdd("distro_series", args.DistroSeries)
 	params.MaybeAdd("hwe_kernel", args.Kernel)
 	params.MaybeAdd("comment", args.Comment)
-	result, err := m.controller.post(m.resourceURI, "deploy", params.Values)
+	return errors.Trace(m.postOp("deploy", params.Values))
+}
+
+// CommissionArgs is an argument struct for passing parameters to the
+// Machine.Commission method.
+type CommissionArgs struct {
+	// EnableSSH leaves the machine running after commissioning so that it
+	// can be accessed over SSH.
+	EnableSSH      bool
+	SkipBMCConfig  bool
+	SkipNetworking bool
+	SkipStorage    bool
+	// CommissioningScripts and TestingScripts are the names or tags of the
+	// scripts to run. If not specified, MAAS uses its defaults.
+	CommissioningScripts []string
+	TestingScripts       []string
+}
+
+// Commission implements Machine.
+func (m *machine) Commission(args CommissionArgs) error {
+	params := NewURLParams()
+	params.MaybeAddBool("enable_ssh", args.EnableSSH)
+	params.MaybeAddBool("skip_bmc_config", args.SkipBMCConfig)
+	params.MaybeAddBool("skip_networking", args.SkipNetworking)
+	params.MaybeAddBool("skip_storage", args.SkipStorage)
+	params.MaybeAdd("commissioning_scripts", strings.Join(args.CommissioningScripts, ","))
+	params.MaybeAdd("testing_scripts", strings.Join(args.TestingScripts, ","))
+	return errors.Trace(m.postOp("commission", params.Values))
+}
+
+// AbortArgs is an argument struct for passing parameters to the
+// Machine.Abort method.
+type AbortArgs struct {
+	Comment string
+}
+
+// Abort implements Machine.
+func (m *machine) Abort(args AbortArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("comment", args.Comment)
+	return errors.Trace(m.postOp("abort", params.Values))
+}
+
+// PowerOnArgs is an argument struct for passing parameters to the
+// Machine.PowerOn method.
+type PowerOnArgs struct {
+	// UserData needs to be Base64 encoded user data for cloud-init.
+	UserData string
+	Comment  string
+}
+
+// PowerOn implements Machine.
+func (m *machine) PowerOn(args PowerOnArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("user_data", args.UserData)
+	params.MaybeAdd("comment", args.Comment)
+	return errors.Trace(m.postOp("power_on", params.Values))
+}
+
+// PowerOffStopMode is the type of the stop mode constants used for
+// PowerOffArgs.
+type PowerOffStopMode string
+
+const (
+	// PowerOffHard powers the machine off immediately.
+	PowerOffHard PowerOffStopMode = "hard"
+
+	// PowerOffSoft asks the operating system to shut down cleanly.
+	PowerOffSoft PowerOffStopMode = "soft"
+)
+
+// PowerOffArgs is an argument struct for passing parameters to the
+// Machine.PowerOff method.
+type PowerOffArgs struct {
+	// StopMode is optional, and MAAS uses a hard power off if not specified.
+	StopMode PowerOffStopMode
+	Comment  string
+}
+
+// PowerOff implements Machine.
+func (m *machine) PowerOff(args PowerOffArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("stop_mode", string(args.StopMode))
+	params.MaybeAdd("comment", args.Comment)
+	return errors.Trace(m.postOp("power_off", params.Values))
+}
+
+// QueryPowerState implements Machine.
+func (m *machine) QueryPowerState() (string, error) {
+	source, err := m.controller.getOp(m.resourceURI, "query_power_state")
 	if err != nil {
-		if svrErr, ok := errors.Cause(err).(ServerError); ok {
-			switch svrErr.StatusCode {
-			case http.StatusNotFound, http.StatusConflict:
-				return errors.Wrap(err, NewBadRequestError(svrErr.BodyMessage))
-			case http.StatusForbidden:
-				return errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
-			case http.StatusServiceUnavailable:
-				return errors.Wrap(err, NewCannotCompleteError(svrErr.BodyMessage))
-			}
+		return "", errors.Trace(translateMachineOpError(err))
+	}
+	fields := schema.Fields{
+		"state": schema.String(),
+	}
+	checker := schema.FieldMap(fields, nil) // no defaults
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return "", WrapWithDeserializationError(err, "power state schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// The query does not return the machine, so only the power state is
+	// refreshed.
+	m.powerState = valid["state"].(string)
+	return m.powerState, nil
+}
+
+// LockArgs is an argument struct for passing parameters to the
+// Machine.Lock method.
+type LockArgs struct {
+	Comment string
+}
+
+// Lock implements Machine.
+func (m *machine) Lock(args LockArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("comment", args.Comment)
+	return errors.Trace(m.postOp("lock", params.Values))
+}
+
+// UnlockArgs is an argument struct for passing parameters to the
+// Machine.Unlock method.
+type UnlockArgs struct {
+	Comment string
+}
+
+// Unlock implements Machine.
+func (m *machine) Unlock(args UnlockArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("comment", args.Comment)
+	return errors.Trace(m.postOp("unlock", params.Values))
+}
+
+// MarkBrokenArgs is an argument struct for passing parameters to the
+// Machine.MarkBroken method.
+type MarkBrokenArgs struct {
+	Comment string
+}
+
+// MarkBroken implements Machine.
+func (m *machine) MarkBroken(args MarkBrokenArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("comment", args.Comment)
+	return errors.Trace(m.postOp("mark_broken", params.Values))
+}
+
+// MarkFixedArgs is an argument struct for passing parameters to the
+// Machine.MarkFixed method.
+type MarkFixedArgs struct {
+	Comment string
+}
+
+// MarkFixed implements Machine.
+func (m *machine) MarkFixed(args MarkFixedArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("comment", args.Comment)
+	return errors.Trace(m.postOp("mark_fixed", params.Values))
+}
+
+// RescueModeArgs is an argument struct for passing parameters to the
+// Machine.RescueMode method. MAAS doesn't accept any parameters for
+// entering rescue mode yet, but the struct leaves room for them.
+type RescueModeArgs struct{}
+
+// RescueMode implements Machine.
+func (m *machine) RescueMode(args RescueModeArgs) error {
+	return errors.Trace(m.postOp("rescue_mode", nil))
+}
+
+// ExitRescueModeArgs is an argument struct for passing parameters to the
+// Machine.ExitRescueMode method. As with RescueModeArgs, there are no
+// parameters yet.
+type ExitRescueModeArgs struct{}
+
+// ExitRescueMode implements Machine.
+func (m *machine) ExitRescueMode(args ExitRescueModeArgs) error {
+	return errors.Trace(m.postOp("exit_rescue_mode", nil))
+}
+
+// StorageLayout is the type of the storage layout constants used for
+// StorageLayoutArgs.
+type StorageLayout string
+
+const (
+	// StorageLayoutFlat puts the root filesystem on a partition.
+	StorageLayoutFlat StorageLayout = "flat"
+
+	// StorageLayoutLVM puts the root filesystem on a logical volume.
+	StorageLayoutLVM StorageLayout = "lvm"
+
+	// StorageLayoutBcache uses an SSD as a cache for the root device.
+	StorageLayoutBcache StorageLayout = "bcache"
+
+	// StorageLayoutVMFS6 is the layout required to deploy VMware ESXi.
+	StorageLayoutVMFS6 StorageLayout = "vmfs6"
+
+	// StorageLayoutBlank removes all storage configuration.
+	StorageLayoutBlank StorageLayout = "blank"
+)
+
+// StorageLayoutArgs is an argument struct for passing parameters to the
+// Machine.SetStorageLayout method. Sizes are in bytes, and MAAS picks
+// sensible defaults for any that are not specified.
+type StorageLayoutArgs struct {
+	// Layout is required.
+	Layout StorageLayout
+	// BootSize is the size of the boot partition on the root device.
+	BootSize uint64
+	// RootSize is the size of the root partition or logical volume.
+	RootSize uint64
+	// RootDevice is the block device to put the root filesystem on. If not
+	// specified, MAAS uses the boot disk.
+	RootDevice BlockDevice
+
+	// VGName and LVSize are only used by the lvm layout.
+	VGName string
+	LVSize uint64
+
+	// CacheDevice and CacheMode are only used by the bcache layout.
+	CacheDevice BlockDevice
+	CacheMode   string
+}
+
+// Validate ensures that the Layout is set and that the layout specific
+// options are consistent with it.
+func (a *StorageLayoutArgs) Validate() error {
+	switch a.Layout {
+	case StorageLayoutFlat, StorageLayoutLVM, StorageLayoutBcache, StorageLayoutVMFS6, StorageLayoutBlank:
+	case "":
+		return errors.NotValidf("missing Layout")
+	default:
+		return errors.NotValidf("unknown Layout value (%q)", a.Layout)
+	}
+	if (a.VGName != "" || a.LVSize != 0) && a.Layout != StorageLayoutLVM {
+		return errors.NotValidf("specifying VGName or LVSize for Layout %q", a.Layout)
+	}
+	if (a.CacheDevice != nil || a.CacheMode != "") && a.Layout != StorageLayoutBcache {
+		return errors.NotValidf("specifying CacheDevice or CacheMode for Layout %q", a.Layout)
+	}
+	return nil
+}
+
+// SetStorageLayout implements Machine.
+func (m *machine) SetStorageLayout(args StorageLayoutArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("storage_layout", string(args.Layout))
+	maybeAddSize(params, "boot_size", args.BootSize)
+	maybeAddSize(params, "root_size", args.RootSize)
+	if args.RootDevice != nil {
+		params.Values.Add("root_device", fmt.Sprint(args.RootDevice.ID()))
+	}
+	params.MaybeAdd("vg_name", args.VGName)
+	maybeAddSize(params, "lv_size", args.LVSize)
+	if args.CacheDevice != nil {
+		params.Values.Add("cache_device", fmt.Sprint(args.CacheDevice.ID()))
+	}
+	params.MaybeAdd("cache_mode", args.CacheMode)
+	result, err := m.controller.post(m.resourceURI, "set_storage_layout", params.Values)
+	if err != nil {
+		// MAAS returns 400 for layouts that don't fit the machine's disks.
+		if svrErr, ok := errors.Cause(err).(ServerError); ok && svrErr.StatusCode == http.StatusBadRequest {
+			return errors.Wrap(err, NewBadRequestError(svrErr.BodyMessage))
 		}
-		return NewUnexpectedError(err)
+		return translateMachineOpError(err)
+	}
+
+	machine, err := readMachine(m.controller.apiVersion, result)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	m.updateFrom(machine)
+	return nil
+}
+
+// nodeURI is used for the collections of this machine that are on the
+// nodes endpoint, not machines, such as volume groups, raids and script
+// results.
+func (m *machine) nodeURI(collection string) string {
+	return strings.Replace(m.resourceURI, "machines", "nodes", 1) + collection + "/"
+}
+
+// VolumeGroups implements Machine.
+func (m *machine) VolumeGroups() ([]VolumeGroup, error) {
+	source, err := m.controller.get(m.nodeURI("volume-groups"))
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	volumeGroups, err := readVolumeGroups(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []VolumeGroup
+	for _, v := range volumeGroups {
+		v.controller = m.controller
+		result = append(result, v)
+	}
+	return result, nil
+}
+
+// CreateVolumeGroup implements Machine.
+func (m *machine) CreateVolumeGroup(args CreateVolumeGroupArgs) (VolumeGroup, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("name", args.Name)
+	params.MaybeAdd("uuid", args.UUID)
+	addBlockDeviceIDs(params, "block_devices", args.BlockDevices)
+	addPartitionIDs(params, "partitions", args.Partitions)
+	source, err := m.controller.post(m.nodeURI("volume-groups"), "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	volumeGroup, err := readVolumeGroup(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumeGroup.controller = m.controller
+	return volumeGroup, nil
+}
+
+// RAIDs implements Machine.
+func (m *machine) RAIDs() ([]RAID, error) {
+	source, err := m.controller.get(m.nodeURI("raids"))
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	raids, err := readRAIDs(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []RAID
+	for _, r := range raids {
+		r.controller = m.controller
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// CreateRAID implements Machine.
+func (m *machine) CreateRAID(args CreateRAIDArgs) (RAID, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("uuid", args.UUID)
+	params.Values.Add("level", string(args.Level))
+	addBlockDeviceIDs(params, "block_devices", args.BlockDevices)
+	addPartitionIDs(params, "partitions", args.Partitions)
+	addBlockDeviceIDs(params, "spare_devices", args.SpareBlockDevices)
+	addPartitionIDs(params, "spare_partitions", args.SparePartitions)
+	source, err := m.controller.post(m.nodeURI("raids"), "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	raid, err := readRAID(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	raid.controller = m.controller
+	return raid, nil
+}
+
+// BcacheCacheSets implements Machine.
+func (m *machine) BcacheCacheSets() ([]BcacheCacheSet, error) {
+	source, err := m.controller.get(m.nodeURI("bcache-cache-sets"))
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	cacheSets, err := readBcacheCacheSets(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []BcacheCacheSet
+	for _, s := range cacheSets {
+		s.controller = m.controller
+		result = append(result, s)
+	}
+	return result, nil
+}
+
+// CreateBcacheCacheSet implements Machine.
+func (m *machine) CreateBcacheCacheSet(args BcacheCacheSetArgs) (BcacheCacheSet, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	source, err := m.controller.post(m.nodeURI("bcache-cache-sets"), "", args.params().Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	cacheSet, err := readBcacheCacheSet(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	cacheSet.controller = m.controller
+	return cacheSet, nil
+}
+
+// Bcaches implements Machine.
+func (m *machine) Bcaches() ([]Bcache, error) {
+	source, err := m.controller.get(m.nodeURI("bcaches"))
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	bcaches, err := readBcaches(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []Bcache
+	for _, b := range bcaches {
+		b.controller = m.controller
+		result = append(result, b)
+	}
+	return result, nil
+}
+
+// CreateBcache implements Machine.
+func (m *machine) CreateBcache(args CreateBcacheArgs) (Bcache, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("uuid", args.UUID)
+	params.Values.Add("cache_set", fmt.Sprint(args.CacheSet.ID()))
+	if args.BackingDevice != nil {
+		params.Values.Add("backing_device", fmt.Sprint(args.BackingDevice.ID()))
+	}
+	if args.BackingPartition != nil {
+		params.Values.Add("backing_partition", fmt.Sprint(args.BackingPartition.ID()))
+	}
+	params.Values.Add("cache_mode", string(args.CacheMode))
+	source, err := m.controller.post(m.nodeURI("bcaches"), "", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	bcache, err := readBcache(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	bcache.controller = m.controller
+	return bcache, nil
+}
+
+// ScriptResultsArgs is an argument struct for passing parameters to the
+// Machine.ScriptResults method. Results of all types and hardware types are
+// returned if they aren't specified.
+type ScriptResultsArgs struct {
+	Type         ScriptType
+	HardwareType HardwareType
+	// Scripts are the names or tags of the scripts to return the results
+	// of.
+	Scripts []string
+}
+
+// ScriptResults implements Machine.
+func (m *machine) ScriptResults(args ScriptResultsArgs) ([]ScriptResult, error) {
+	params := NewURLParams()
+	params.MaybeAdd("type", string(args.Type))
+	params.MaybeAdd("hardware_type", string(args.HardwareType))
+	params.MaybeAdd("filters", strings.Join(args.Scripts, ","))
+	params.Values.Add("include_output", "true")
+	source, err := m.controller.getQuery(m.nodeURI("results"), params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	scriptResults, err := readScriptResults(m.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []ScriptResult
+	for _, r := range scriptResults {
+		result = append(result, r)
+	}
+	return result, nil
+}
+
+// Details implements Machine.
+func (m *machine) Details() (MachineDetails, error) {
+	source, err := m.controller._getRaw(m.resourceURI, "details", nil)
+	if err != nil {
+		return MachineDetails{}, translateServerError(err)
+	}
+	details, err := readMachineDetails(source)
+	if err != nil {
+		return MachineDetails{}, errors.Trace(err)
+	}
+	return details, nil
+}
+
+// addBlockDeviceIDs adds the IDs of the block devices as repeated values.
+func addBlockDeviceIDs(params *URLParams, name string, blockDevices []BlockDevice) {
+	for _, b := range blockDevices {
+		params.Values.Add(name, fmt.Sprint(b.ID()))
+	}
+}
+
+// addPartitionIDs adds the IDs of the partitions as repeated values.
+func addPartitionIDs(params *URLParams, name string, partitions []Partition) {
+	for _, p := range partitions {
+		params.Values.Add(name, fmt.Sprint(p.ID()))
+	}
+}
+
+// maybeAddSize adds the size in bytes iff it is not zero.
+func maybeAddSize(params *URLParams, name string, size uint64) {
+	if size != 0 {
+		params.Values.Add(name, fmt.Sprint(size))
+	}
+}
+
+// postOp calls the specified operation on the machine and refreshes the
+// machine from the response.
+func (m *machine) postOp(op string, params url.Values) error {
+	result, err := m.controller.post(m.resourceURI, op, params)
+	if err != nil {
+		return translateMachineOpError(err)
 	}
 
 	machine, err := readMachine(m.controller.apiVersion, result)
@@ -302,6 +1120,22 @@ func (m *machine) Start(args StartArgs) error {
 	return nil
 }
 
+// translateMachineOpError maps the HTTP status codes that MAAS uses for
+// failed machine operations to the errors defined by this package.
+func translateMachineOpError(err error) error {
+	if svrErr, ok := errors.Cause(err).(ServerError); ok {
+		switch svrErr.StatusCode {
+		case http.StatusNotFound, http.StatusConflict:
+			return errors.Wrap(err, NewBadRequestError(svrErr.BodyMessage))
+		case http.StatusForbidden:
+			return errors.Wrap(err, NewPermissionError(svrErr.BodyMessage))
+		case http.StatusServiceUnavailable:
+			return errors.Wrap(err, NewCannotCompleteError(svrErr.BodyMessage))
+		}
+	}
+	return NewUnexpectedError(err)
+}
+
 // CreateMachineDeviceArgs is an argument structure for Machine.CreateDevice.
 // Only InterfaceName and MACAddress fields are required, the others are only
 // used if set. If Subnet and VLAN are both set, Subnet.VLAN() must match the
@@ -532,6 +1366,7 @@ func machine_2_0(source map[string]interface{}) (*machine, error) {
 
 		"ip_addresses":   schema.List(schema.String()),
 		"power_state":    schema.String(),
+		"power_type":     schema.OneOf(schema.Nil(""), schema.String()),
 		"status_name":    schema.String(),
 		"status_message": schema.OneOf(schema.Nil(""), schema.String()),
 
@@ -545,6 +1380,7 @@ func machine_2_0(source map[string]interface{}) (*machine, error) {
 	}
 	defaults := schema.Defaults{
 		"architecture": "",
+		"power_type":   "",
 	}
 
 	checker := schema.FieldMap(fields, defaults)
@@ -605,6 +1441,7 @@ func machine_2_0(source map[string]interface{}) (*machine, error) {
 
 	architecture, _ := valid["architecture"].(string)
 	statusMessage, _ := valid["status_message"].(string)
+	powerType, _ := valid["power_type"].(string)
 	result := &machine{
 		resourceURI: valid["resource_uri"].(string),
 
@@ -623,6 +1460,7 @@ func machine_2_0(source map[string]interface{}) (*machine, error) {
 
 		ipAddresses:   convertToStringSlice(valid["ip_addresses"]),
 		powerState:    valid["power_state"].(string),
+		powerType:     powerType,
 		statusName:    valid["status_name"].(string),
 		statusMessage: statusMessage,
 
@@ -4,8 +4,10 @@
 package gomaasapi
 
 import (
+	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 
 	"github.com/juju/errors"
 	"github.com/juju/testing"
@@ -81,6 +83,7 @@ func (*machineSuite) checkMachine(c *gc.C, machine Machine) {
 	c.Check(machine.Memory(), gc.Equals, 1024)
 	c.Check(machine.CPUCount(), gc.Equals, 1)
 	c.Check(machine.PowerState(), gc.Equals, "on")
+	c.Check(machine.PowerType(), gc.Equals, "virsh")
 	c.Check(machine.Zone().Name(), gc.Equals, "default")
 	c.Check(machine.Pool().Name(), gc.Equals, "default")
 	c.Check(machine.OperatingSystem(), gc.Equals, "ubuntu")
@@ -127,6 +130,7 @@ func (*machineSuite) TestReadMachinesNilValues(c *gc.C) {
 	data["boot_interface"] = nil
 	data["pool"] = nil
 	data["hardware_info"] = nil
+	data["power_type"] = nil
 	machines, err := readMachines(twoDotOh, json)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(machines, gc.HasLen, 3)
@@ -136,6 +140,7 @@ func (*machineSuite) TestReadMachinesNilValues(c *gc.C) {
 	c.Check(machine.BootInterface(), gc.IsNil)
 	c.Check(machine.Pool(), gc.IsNil)
 	c.Check(machine.HardwareInfo(), gc.IsNil)
+	c.Check(machine.PowerType(), gc.Equals, "")
 }
 
 func (*machineSuite) TestLowVersion(c *gc.C) {
@@ -222,6 +227,14 @@ func (s *machineSuite) TestStartMachineServiceUnavailable(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "no ip addresses available")
 }
 
+func (s *machineSuite) TestStartMachineBadRequest(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse(machine.resourceURI+"?op=deploy", http.StatusBadRequest, "no such series")
+	err := machine.Start(StartArgs{})
+	c.Assert(err, jc.Satisfies, IsUnexpectedError)
+	c.Assert(err.Error(), gc.Equals, "unexpected: ServerError: 400 Bad Request (no such series)")
+}
+
 func (s *machineSuite) TestStartMachineUnknown(c *gc.C) {
 	server, machine := s.getServerAndMachine(c)
 	server.AddPostResponse(machine.resourceURI+"?op=deploy", http.StatusMethodNotAllowed, "wat?")
@@ -230,6 +243,745 @@ func (s *machineSuite) TestStartMachineUnknown(c *gc.C) {
 	c.Assert(err.Error(), gc.Equals, "unexpected: ServerError: 405 Method Not Allowed (wat?)")
 }
 
+func (s *machineSuite) TestCommission(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, machineResponse, map[string]interface{}{
+		"status_name": "Commissioning",
+	})
+	server.AddPostResponse(machine.resourceURI+"?op=commission", http.StatusOK, response)
+
+	err := machine.Commission(CommissionArgs{
+		EnableSSH:            true,
+		SkipBMCConfig:        true,
+		SkipNetworking:       true,
+		SkipStorage:          true,
+		CommissioningScripts: []string{"update_firmware", "configure_hba"},
+		TestingScripts:       []string{"smartctl-validate"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.StatusName(), gc.Equals, "Commissioning")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 6)
+	c.Check(form.Get("enable_ssh"), gc.Equals, "true")
+	c.Check(form.Get("skip_bmc_config"), gc.Equals, "true")
+	c.Check(form.Get("skip_networking"), gc.Equals, "true")
+	c.Check(form.Get("skip_storage"), gc.Equals, "true")
+	c.Check(form.Get("commissioning_scripts"), gc.Equals, "update_firmware,configure_hba")
+	c.Check(form.Get("testing_scripts"), gc.Equals, "smartctl-validate")
+}
+
+func (s *machineSuite) TestPowerOn(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, machineResponse, map[string]interface{}{
+		"power_state": "on",
+	})
+	server.AddPostResponse(machine.resourceURI+"?op=power_on", http.StatusOK, response)
+
+	err := machine.PowerOn(PowerOnArgs{UserData: "userdata", Comment: "a comment"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.PowerState(), gc.Equals, "on")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 2)
+	c.Check(form.Get("user_data"), gc.Equals, "userdata")
+	c.Check(form.Get("comment"), gc.Equals, "a comment")
+}
+
+func (s *machineSuite) TestPowerOff(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, machineResponse, map[string]interface{}{
+		"power_state": "off",
+	})
+	server.AddPostResponse(machine.resourceURI+"?op=power_off", http.StatusOK, response)
+
+	err := machine.PowerOff(PowerOffArgs{StopMode: PowerOffSoft, Comment: "a comment"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.PowerState(), gc.Equals, "off")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 2)
+	c.Check(form.Get("stop_mode"), gc.Equals, "soft")
+	c.Check(form.Get("comment"), gc.Equals, "a comment")
+}
+
+func (s *machineSuite) TestQueryPowerState(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse(machine.resourceURI+"?op=query_power_state", http.StatusOK, `{"state": "off"}`)
+
+	state, err := machine.QueryPowerState()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(state, gc.Equals, "off")
+	c.Assert(machine.PowerState(), gc.Equals, "off")
+}
+
+func (s *machineSuite) TestQueryPowerStateBadResponse(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse(machine.resourceURI+"?op=query_power_state", http.StatusOK, `{"wat": "?"}`)
+
+	_, err := machine.QueryPowerState()
+	c.Assert(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(machine.PowerState(), gc.Equals, "on")
+}
+
+func (s *machineSuite) TestQueryPowerStateServiceUnavailable(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse(machine.resourceURI+"?op=query_power_state", http.StatusServiceUnavailable, "bmc unreachable")
+
+	_, err := machine.QueryPowerState()
+	c.Assert(err, jc.Satisfies, IsCannotCompleteError)
+	c.Assert(err.Error(), gc.Equals, "bmc unreachable")
+}
+
+func (s *machineSuite) TestCommentOps(c *gc.C) {
+	for i, test := range []struct {
+		op   string
+		call func(*machine) error
+	}{{
+		op:   "abort",
+		call: func(m *machine) error { return m.Abort(AbortArgs{Comment: "a comment"}) },
+	}, {
+		op:   "lock",
+		call: func(m *machine) error { return m.Lock(LockArgs{Comment: "a comment"}) },
+	}, {
+		op:   "unlock",
+		call: func(m *machine) error { return m.Unlock(UnlockArgs{Comment: "a comment"}) },
+	}, {
+		op:   "mark_broken",
+		call: func(m *machine) error { return m.MarkBroken(MarkBrokenArgs{Comment: "a comment"}) },
+	}, {
+		op:   "mark_fixed",
+		call: func(m *machine) error { return m.MarkFixed(MarkFixedArgs{Comment: "a comment"}) },
+	}} {
+		c.Logf("test %d: %s", i, test.op)
+		server, machine := s.getServerAndMachine(c)
+		response := updateJSONMap(c, machineResponse, map[string]interface{}{
+			"status_message": test.op,
+		})
+		server.AddPostResponse(machine.resourceURI+"?op="+test.op, http.StatusOK, response)
+
+		err := test.call(machine)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(machine.StatusMessage(), gc.Equals, test.op)
+
+		form := server.LastRequest().PostForm
+		c.Check(form, gc.HasLen, 1)
+		c.Check(form.Get("comment"), gc.Equals, "a comment")
+	}
+}
+
+func (s *machineSuite) TestRescueMode(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, machineResponse, map[string]interface{}{
+		"status_name": "Entering rescue mode",
+	})
+	server.AddPostResponse(machine.resourceURI+"?op=rescue_mode", http.StatusOK, response)
+
+	err := machine.RescueMode(RescueModeArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.StatusName(), gc.Equals, "Entering rescue mode")
+	c.Assert(server.LastRequest().PostForm, gc.HasLen, 0)
+}
+
+func (s *machineSuite) TestExitRescueMode(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, machineResponse, map[string]interface{}{
+		"status_name": "Exiting rescue mode",
+	})
+	server.AddPostResponse(machine.resourceURI+"?op=exit_rescue_mode", http.StatusOK, response)
+
+	err := machine.ExitRescueMode(ExitRescueModeArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.StatusName(), gc.Equals, "Exiting rescue mode")
+}
+
+func (s *machineSuite) TestLifecycleOpErrors(c *gc.C) {
+	for i, test := range []struct {
+		status  int
+		checker func(error) bool
+	}{
+		{http.StatusNotFound, IsBadRequestError},
+		{http.StatusConflict, IsBadRequestError},
+		{http.StatusForbidden, IsPermissionError},
+		{http.StatusServiceUnavailable, IsCannotCompleteError},
+		{http.StatusMethodNotAllowed, IsUnexpectedError},
+	} {
+		c.Logf("test %d: %d", i, test.status)
+		server, machine := s.getServerAndMachine(c)
+		server.AddPostResponse(machine.resourceURI+"?op=power_on", test.status, "no dice")
+		err := machine.PowerOn(PowerOnArgs{})
+		c.Check(err, jc.Satisfies, test.checker)
+	}
+}
+
+func (s *machineSuite) TestPowerParameters(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse(machine.resourceURI+"?op=power_parameters", http.StatusOK, `{
+		"power_address": "qemu+ssh://ubuntu@10.0.0.1/system",
+		"power_id": "untasted-markita",
+		"power_pass": null,
+		"power_port": 22
+	}`)
+	params, err := machine.PowerParameters()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(params, jc.DeepEquals, PowerParameters{
+		"power_address": "qemu+ssh://ubuntu@10.0.0.1/system",
+		"power_id":      "untasted-markita",
+		"power_pass":    "",
+		"power_port":    "22",
+	})
+}
+
+func (s *machineSuite) TestPowerParametersForbidden(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse(machine.resourceURI+"?op=power_parameters", http.StatusForbidden, "admins only")
+	_, err := machine.PowerParameters()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+	c.Assert(err.Error(), gc.Equals, "admins only")
+}
+
+func (s *machineSuite) TestUpdatePowerParameters(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, machineResponse, map[string]interface{}{
+		"power_type": "ipmi",
+	})
+	server.AddPutResponse(machine.resourceURI, http.StatusOK, response)
+	err := machine.UpdatePowerParameters(UpdatePowerParametersArgs{
+		PowerType:  "ipmi",
+		Parameters: PowerParameters{"power_address": "10.0.0.1"},
+		SkipCheck:  true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.PowerType(), gc.Equals, "ipmi")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 3)
+	c.Check(form.Get("power_type"), gc.Equals, "ipmi")
+	c.Check(form.Get("power_parameters_power_address"), gc.Equals, "10.0.0.1")
+	c.Check(form.Get("power_parameters_skip_check"), gc.Equals, "true")
+}
+
+func (s *machineSuite) TestUpdatePowerParametersClear(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPutResponse(machine.resourceURI, http.StatusOK, machineResponse)
+	err := machine.UpdatePowerParameters(UpdatePowerParametersArgs{
+		Parameters: PowerParameters{"power_pass": ""},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{"power_parameters_power_pass": {""}})
+}
+
+func (s *machineSuite) TestUpdatePowerParametersBadRequest(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPutResponse(machine.resourceURI, http.StatusBadRequest, "bad power address")
+	err := machine.UpdatePowerParameters(UpdatePowerParametersArgs{
+		Parameters: PowerParameters{"power_address": "nope"},
+	})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "bad power address")
+}
+
+func (s *machineSuite) TestStorageLayoutArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    StorageLayoutArgs
+		errText string
+	}{{
+		errText: "missing Layout not valid",
+	}, {
+		args:    StorageLayoutArgs{Layout: "zfs"},
+		errText: `unknown Layout value ("zfs") not valid`,
+	}, {
+		args:    StorageLayoutArgs{Layout: StorageLayoutFlat, VGName: "vg0"},
+		errText: `specifying VGName or LVSize for Layout "flat" not valid`,
+	}, {
+		args:    StorageLayoutArgs{Layout: StorageLayoutLVM, CacheMode: "writeback"},
+		errText: `specifying CacheDevice or CacheMode for Layout "lvm" not valid`,
+	}, {
+		args: StorageLayoutArgs{Layout: StorageLayoutLVM, VGName: "vg0", LVSize: 1 << 30},
+	}, {
+		args: StorageLayoutArgs{Layout: StorageLayoutBcache, CacheMode: "writeback"},
+	}, {
+		args: StorageLayoutArgs{Layout: StorageLayoutBlank},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *machineSuite) TestSetStorageLayout(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	source := parseJSON(c, machineResponse).(map[string]interface{})
+	blockDevices := source["blockdevice_set"].([]interface{})
+	source["blockdevice_set"] = blockDevices[:1]
+	response, err := json.Marshal(source)
+	c.Assert(err, jc.ErrorIsNil)
+	server.AddPostResponse(machine.resourceURI+"?op=set_storage_layout", http.StatusOK, string(response))
+
+	err = machine.SetStorageLayout(StorageLayoutArgs{
+		Layout:     StorageLayoutLVM,
+		BootSize:   512 * 1024 * 1024,
+		RootSize:   20 * 1024 * 1024 * 1024,
+		RootDevice: machine.BlockDevice(34),
+		VGName:     "vgroot",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machine.BlockDevices(), gc.HasLen, 1)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 5)
+	c.Check(form.Get("storage_layout"), gc.Equals, "lvm")
+	c.Check(form.Get("boot_size"), gc.Equals, "536870912")
+	c.Check(form.Get("root_size"), gc.Equals, "21474836480")
+	c.Check(form.Get("root_device"), gc.Equals, "34")
+	c.Check(form.Get("vg_name"), gc.Equals, "vgroot")
+}
+
+func (s *machineSuite) TestSetStorageLayoutBadRequest(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse(machine.resourceURI+"?op=set_storage_layout", http.StatusBadRequest, "root size too large")
+	err := machine.SetStorageLayout(StorageLayoutArgs{Layout: StorageLayoutFlat})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "root size too large")
+}
+
+func (s *machineSuite) TestSetStorageLayoutValidates(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	err := machine.SetStorageLayout(StorageLayoutArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *machineSuite) TestSetStorageLayoutNotReady(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse(machine.resourceURI+"?op=set_storage_layout", http.StatusConflict, "machine must be Ready")
+	err := machine.SetStorageLayout(StorageLayoutArgs{Layout: StorageLayoutFlat})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "machine must be Ready")
+}
+
+func (s *machineSuite) TestScriptResults(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse("/MAAS/api/2.0/nodes/4y3ha3/results/?include_output=true", http.StatusOK, scriptResultsResponse)
+	results, err := machine.ScriptResults(ScriptResultsArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+	c.Check(results[1].Name(), gc.Equals, "smartctl-validate")
+	c.Check(string(results[1].Stderr()), gc.Equals, "SMART support is unavailable\n")
+}
+
+func (s *machineSuite) TestScriptResultsArgs(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	// This will fail with a 404 due to the test server not having something at
+	// that address, but we don't care, all we want to do is capture the request
+	// and make sure that all the values were set.
+	machine.ScriptResults(ScriptResultsArgs{
+		Type:         ScriptTypeTesting,
+		HardwareType: HardwareTypeStorage,
+		Scripts:      []string{"smartctl-validate", "badblocks"},
+	})
+	request := server.LastRequest()
+	c.Assert(request.URL.Query(), jc.DeepEquals, url.Values{
+		"type":           {"testing"},
+		"hardware_type":  {"storage"},
+		"filters":        {"smartctl-validate,badblocks"},
+		"include_output": {"true"},
+	})
+}
+
+func (s *machineSuite) TestScriptResultsNotFound(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	_, err := machine.ScriptResults(ScriptResultsArgs{})
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *machineSuite) TestDetails(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	body := machineDetailsBSON(c, lshwDetails, lldpDetails)
+	server.AddGetResponse("/MAAS/api/2.0/machines/4y3ha3/?op=details", http.StatusOK, string(body))
+	details, err := machine.Details()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.Hardware.Serial, gc.Equals, "7XKQ2Y2")
+	c.Check(details.Hardware.Disks, gc.HasLen, 1)
+	c.Check(details.LLDPNeighbors, gc.HasLen, 2)
+}
+
+func (s *machineSuite) TestDetailsNotFound(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	_, err := machine.Details()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *machineSuite) TestInterfaceLinkSubnetCallsServer(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	subnet := machine.InterfaceSet()[0].Links()[0].Subnet()
+	server.AddGetResponse("/MAAS/api/2.0/subnets/1/?op=statistics", http.StatusOK, subnetStatisticsResponse)
+	server.AddGetResponse("/MAAS/api/2.0/subnets/1/?op=reserved_ip_ranges", http.StatusOK, reservedIPRangesResponse)
+	server.AddGetResponse("/MAAS/api/2.0/subnets/1/?op=unreserved_ip_ranges", http.StatusOK, unreservedIPRangesResponse)
+	server.AddPutResponse("/MAAS/api/2.0/subnets/1/", http.StatusOK, subnetItemResponse)
+	server.AddDeleteResponse("/MAAS/api/2.0/subnets/1/", http.StatusNoContent, "")
+
+	stats, err := subnet.Statistics(false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(stats.TotalAddresses, gc.Equals, uint(254))
+	reserved, err := subnet.ReservedIPRanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(reserved, gc.HasLen, 2)
+	unreserved, err := subnet.UnreservedIPRanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(unreserved, gc.HasLen, 2)
+	err = subnet.Update(UpdateSubnetArgs{Name: "renamed"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = subnet.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *machineSuite) TestInterfaceLinkSubnetVLANCallsServer(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	vlan := machine.InterfaceSet()[0].Links()[0].Subnet().VLAN()
+	response := updateJSONMap(c, vlanItemResponse, map[string]interface{}{
+		"resource_uri": "/MAAS/api/2.0/vlans/1/",
+	})
+	server.AddPutResponse("/MAAS/api/2.0/vlans/1/", http.StatusOK, response)
+	server.AddPutResponse("/MAAS/api/2.0/vlans/1/", http.StatusOK, response)
+	server.AddDeleteResponse("/MAAS/api/2.0/vlans/1/", http.StatusNoContent, "")
+
+	err := vlan.Update(UpdateVLANArgs{Name: "renamed"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = vlan.SetSpace(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = vlan.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *machineSuite) TestVolumeGroups(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse("/MAAS/api/2.0/nodes/4y3ha3/volume-groups/", http.StatusOK, volumeGroupsResponse)
+	volumeGroups, err := machine.VolumeGroups()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeGroups, gc.HasLen, 1)
+	c.Check(volumeGroups[0].Name(), gc.Equals, "vg0")
+}
+
+func (s *machineSuite) TestCreateVolumeGroupArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    CreateVolumeGroupArgs
+		errText string
+	}{{
+		errText: "missing Name not valid",
+	}, {
+		args:    CreateVolumeGroupArgs{Name: "vg0"},
+		errText: "missing BlockDevices or Partitions not valid",
+	}, {
+		args: CreateVolumeGroupArgs{Name: "vg0", BlockDevices: []BlockDevice{&blockdevice{}}},
+	}, {
+		args: CreateVolumeGroupArgs{Name: "vg0", Partitions: []Partition{&partition{}}},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *machineSuite) TestCreateVolumeGroup(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/volume-groups/?op=", http.StatusOK, volumeGroupResponse)
+	vg, err := machine.CreateVolumeGroup(CreateVolumeGroupArgs{
+		Name:         "vg0",
+		BlockDevices: []BlockDevice{machine.BlockDevice(98)},
+		Partitions:   []Partition{machine.Partition(1)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(vg.ID(), gc.Equals, 5)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 3)
+	c.Check(form.Get("name"), gc.Equals, "vg0")
+	c.Check(form["block_devices"], gc.DeepEquals, []string{"98"})
+	c.Check(form["partitions"], gc.DeepEquals, []string{"1"})
+}
+
+func (s *machineSuite) TestCreateVolumeGroupValidates(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	_, err := machine.CreateVolumeGroup(CreateVolumeGroupArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *machineSuite) TestCreateVolumeGroupBadRequest(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/volume-groups/?op=", http.StatusBadRequest, "device in use")
+	_, err := machine.CreateVolumeGroup(CreateVolumeGroupArgs{
+		Name:       "vg0",
+		Partitions: []Partition{machine.Partition(1)},
+	})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "device in use")
+}
+
+func (s *machineSuite) TestCreateBondArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    CreateBondArgs
+		errText string
+	}{{
+		errText: "missing Name not valid",
+	}, {
+		args:    CreateBondArgs{Name: "bond0"},
+		errText: "missing Parents not valid",
+	}, {
+		args: CreateBondArgs{Name: "bond0", Parents: []Interface{&interface_{}}},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *machineSuite) TestCreateBond(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, interfaceResponse, map[string]interface{}{
+		"name":     "bond0",
+		"type":     "bond",
+		"id":       101,
+		"parents":  []string{"eth0"},
+		"children": []string{},
+	})
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/interfaces/?op=create_bond", http.StatusOK, response)
+
+	bond, err := machine.CreateBond(CreateBondArgs{
+		Name:       "bond0",
+		Parents:    []Interface{machine.Interface(35), machine.Interface(99)},
+		Mode:       Bond8023AD,
+		MIIMon:     100,
+		LACPRate:   BondLACPRateFast,
+		HashPolicy: BondHashLayer34,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bond.Name(), gc.Equals, "bond0")
+	c.Check(bond.Type(), gc.Equals, "bond")
+	c.Check(bond.Parents(), jc.DeepEquals, []string{"eth0"})
+	c.Check(machine.Interface(35).Children(), jc.DeepEquals, []string{"bond0"})
+	c.Check(machine.Interface(101), gc.NotNil)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 6)
+	c.Check(form.Get("name"), gc.Equals, "bond0")
+	c.Check(form["parents"], jc.DeepEquals, []string{"35", "99"})
+	c.Check(form.Get("bond_mode"), gc.Equals, "802.3ad")
+	c.Check(form.Get("bond_miimon"), gc.Equals, "100")
+	c.Check(form.Get("bond_lacp_rate"), gc.Equals, "fast")
+	c.Check(form.Get("bond_xmit_hash_policy"), gc.Equals, "layer3+4")
+}
+
+func (s *machineSuite) TestCreateBondValidates(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	_, err := machine.CreateBond(CreateBondArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *machineSuite) TestCreateBondBadRequest(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/interfaces/?op=create_bond", http.StatusBadRequest, "parents on different VLANs")
+	_, err := machine.CreateBond(CreateBondArgs{
+		Name:    "bond0",
+		Parents: []Interface{machine.Interface(35)},
+	})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "parents on different VLANs")
+}
+
+func (s *machineSuite) TestCreateBridge(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, interfaceResponse, map[string]interface{}{
+		"name":     "br0",
+		"type":     "bridge",
+		"id":       102,
+		"parents":  []string{"eth0"},
+		"children": []string{},
+	})
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/interfaces/?op=create_bridge", http.StatusOK, response)
+
+	bridge, err := machine.CreateBridge(CreateBridgeArgs{
+		Name:         "br0",
+		Parent:       machine.Interface(35),
+		STP:          true,
+		ForwardDelay: 15,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bridge.Type(), gc.Equals, "bridge")
+	c.Check(machine.Interface(35).Children(), jc.DeepEquals, []string{"br0"})
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 4)
+	c.Check(form.Get("name"), gc.Equals, "br0")
+	c.Check(form.Get("parent"), gc.Equals, "35")
+	c.Check(form.Get("bridge_stp"), gc.Equals, "true")
+	c.Check(form.Get("bridge_fd"), gc.Equals, "15")
+}
+
+func (s *machineSuite) TestCreateBridgeValidates(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	_, err := machine.CreateBridge(CreateBridgeArgs{Name: "br0"})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing Parent not valid")
+}
+
+func (s *machineSuite) TestCreateVLANInterface(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	response := updateJSONMap(c, interfaceResponse, map[string]interface{}{
+		"name":     "eth0.42",
+		"type":     "vlan",
+		"id":       103,
+		"parents":  []string{"eth0"},
+		"children": []string{},
+	})
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/interfaces/?op=create_vlan", http.StatusOK, response)
+
+	iface, err := machine.CreateVLANInterface(machine.Interface(35), &vlan{id: 42})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(iface.Name(), gc.Equals, "eth0.42")
+	c.Check(machine.Interface(35).Children(), jc.DeepEquals, []string{"eth0.42"})
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 2)
+	c.Check(form.Get("parent"), gc.Equals, "35")
+	c.Check(form.Get("vlan"), gc.Equals, "42")
+}
+
+func (s *machineSuite) TestCreateVLANInterfaceValidates(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	_, err := machine.CreateVLANInterface(machine.Interface(35), nil)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing VLAN not valid")
+}
+
+func (s *machineSuite) TestRAIDs(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse("/MAAS/api/2.0/nodes/4y3ha3/raids/", http.StatusOK, raidsResponse)
+	raids, err := machine.RAIDs()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(raids, gc.HasLen, 1)
+	c.Check(raids[0].Name(), gc.Equals, "md0")
+}
+
+func (s *machineSuite) TestCreateRAID(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/raids/?op=", http.StatusOK, raidResponse)
+	raid, err := machine.CreateRAID(CreateRAIDArgs{
+		Name:            "md0",
+		Level:           RAID1,
+		BlockDevices:    []BlockDevice{machine.BlockDevice(98)},
+		Partitions:      []Partition{machine.Partition(1)},
+		SparePartitions: []Partition{machine.Partition(101)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(raid.ID(), gc.Equals, 63)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 5)
+	c.Check(form.Get("name"), gc.Equals, "md0")
+	c.Check(form.Get("level"), gc.Equals, "raid-1")
+	c.Check(form["block_devices"], gc.DeepEquals, []string{"98"})
+	c.Check(form["partitions"], gc.DeepEquals, []string{"1"})
+	c.Check(form["spare_partitions"], gc.DeepEquals, []string{"101"})
+
+	c.Check(raid.VirtualDevice().ID(), gc.Equals, 64)
+	// The machine's block devices are only updated when it is read again.
+	c.Check(machine.BlockDevice(64), gc.IsNil)
+}
+
+func (s *machineSuite) TestCreateRAIDValidates(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	_, err := machine.CreateRAID(CreateRAIDArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *machineSuite) TestCreateRAIDNotReady(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/raids/?op=", http.StatusConflict, "machine must be Ready")
+	_, err := machine.CreateRAID(CreateRAIDArgs{
+		Level:        RAID0,
+		BlockDevices: []BlockDevice{machine.BlockDevice(98)},
+	})
+	c.Assert(err, jc.Satisfies, IsCannotCompleteError)
+}
+
+func (s *machineSuite) TestBcacheCacheSets(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse("/MAAS/api/2.0/nodes/4y3ha3/bcache-cache-sets/", http.StatusOK, bcacheCacheSetsResponse)
+	cacheSets, err := machine.BcacheCacheSets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cacheSets, gc.HasLen, 1)
+	c.Check(cacheSets[0].Name(), gc.Equals, "cache0")
+}
+
+func (s *machineSuite) TestCreateBcacheCacheSet(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/bcache-cache-sets/?op=", http.StatusOK, bcacheCacheSetResponse)
+	cacheSet, err := machine.CreateBcacheCacheSet(BcacheCacheSetArgs{
+		CachePartition: machine.Partition(101),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(cacheSet.ID(), gc.Equals, 7)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 1)
+	c.Check(form.Get("cache_partition"), gc.Equals, "101")
+}
+
+func (s *machineSuite) TestBcaches(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddGetResponse("/MAAS/api/2.0/nodes/4y3ha3/bcaches/", http.StatusOK, bcachesResponse)
+	bcaches, err := machine.Bcaches()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(bcaches, gc.HasLen, 1)
+	c.Check(bcaches[0].Name(), gc.Equals, "bcache0")
+}
+
+func (s *machineSuite) TestCreateBcache(c *gc.C) {
+	server, machine := s.getServerAndMachine(c)
+	server.AddPostResponse("/MAAS/api/2.0/nodes/4y3ha3/bcaches/?op=", http.StatusOK, bcacheResponse)
+	bcache, err := machine.CreateBcache(CreateBcacheArgs{
+		Name:          "bcache0",
+		CacheSet:      &bcacheCacheSet{id: 7},
+		BackingDevice: machine.BlockDevice(34),
+		CacheMode:     BcacheWriteBack,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(bcache.ID(), gc.Equals, 66)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 4)
+	c.Check(form.Get("name"), gc.Equals, "bcache0")
+	c.Check(form.Get("cache_set"), gc.Equals, "7")
+	c.Check(form.Get("backing_device"), gc.Equals, "34")
+	c.Check(form.Get("cache_mode"), gc.Equals, "writeback")
+
+	c.Check(bcache.VirtualDevice().ID(), gc.Equals, 67)
+	c.Check(machine.BlockDevice(67), gc.IsNil)
+}
+
+func (s *machineSuite) TestCreateBcacheValidates(c *gc.C) {
+	_, machine := s.getServerAndMachine(c)
+	_, err := machine.CreateBcache(CreateBcacheArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
 func (s *machineSuite) TestDevices(c *gc.C) {
 	server, machine := s.getServerAndMachine(c)
 	server.AddGetResponse("/api/2.0/devices/", http.StatusOK, devicesResponse)
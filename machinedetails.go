@@ -0,0 +1,343 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/mgo/v2/bson"
+)
+
+// MachineDetails is the hardware of a machine as it was found by lshw, and
+// the LLDP neighbors of its interfaces, when the machine was commissioned.
+type MachineDetails struct {
+	Hardware      HardwareDetails
+	LLDPNeighbors []LLDPNeighbor
+}
+
+// HardwareDetails is the hardware of a machine, as it was found by lshw.
+type HardwareDetails struct {
+	Vendor  string
+	Product string
+	Serial  string
+
+	CPUs        []HardwareCPU
+	MemoryBanks []HardwareMemoryBank
+	NICs        []HardwareNIC
+	Disks       []HardwareDisk
+}
+
+// HardwareCPU is a processor of a machine.
+type HardwareCPU struct {
+	Product string
+	Vendor  string
+	Slot    string
+	// Speed is in Hz.
+	Speed        uint64
+	Cores        int
+	EnabledCores int
+	Threads      int
+	Disabled     bool
+}
+
+// HardwareMemoryBank is a memory slot of a machine. The size of empty
+// slots is zero.
+type HardwareMemoryBank struct {
+	Slot        string
+	Description string
+	Product     string
+	Vendor      string
+	Serial      string
+	// Size is in bytes.
+	Size uint64
+	// Clock is in Hz.
+	Clock uint64
+}
+
+// HardwareNIC is a network interface card of a machine.
+type HardwareNIC struct {
+	// Name is the name of the interface in the commissioning environment,
+	// such as "eno1".
+	Name          string
+	MACAddress    string
+	Product       string
+	Vendor        string
+	BusInfo       string
+	Driver        string
+	DriverVersion string
+	Firmware      string
+	// Speed is in bits per second.
+	Speed uint64
+	Link  bool
+}
+
+// HardwareDisk is a disk of a machine.
+type HardwareDisk struct {
+	// Name is the device name in the commissioning environment, such as
+	// "/dev/sda".
+	Name     string
+	Product  string
+	Vendor   string
+	Serial   string
+	BusInfo  string
+	Firmware string
+	// Size is in bytes.
+	Size uint64
+}
+
+// LLDPNeighbor is a device, usually a switch, that was found on an
+// interface of a machine by LLDP.
+type LLDPNeighbor struct {
+	// Interface is the name of the interface of the machine in the
+	// commissioning environment.
+	Interface string
+
+	ChassisID           string
+	ChassisIDType       string
+	SystemName          string
+	SystemDescription   string
+	ManagementAddresses []string
+
+	PortID          string
+	PortIDType      string
+	PortDescription string
+
+	VLANs []LLDPVLAN
+}
+
+// LLDPVLAN is a VLAN that an LLDP neighbor reports for its port.
+type LLDPVLAN struct {
+	ID   int
+	Name string
+	// PVID is true for the VLAN of the untagged traffic of the port.
+	PVID bool
+}
+
+// readMachineDetails decodes the BSON document of the lshw and LLDP XML of
+// a machine.
+func readMachineDetails(source []byte) (MachineDetails, error) {
+	var document map[string]interface{}
+	if err := bson.Unmarshal(source, &document); err != nil {
+		return MachineDetails{}, WrapWithDeserializationError(err, "machine details")
+	}
+	lshw, err := detailsXML(document, "lshw")
+	if err != nil {
+		return MachineDetails{}, errors.Trace(err)
+	}
+	lldp, err := detailsXML(document, "lldp")
+	if err != nil {
+		return MachineDetails{}, errors.Trace(err)
+	}
+
+	var result MachineDetails
+	if result.Hardware, err = readHardwareDetails(lshw); err != nil {
+		return MachineDetails{}, errors.Trace(err)
+	}
+	if result.LLDPNeighbors, err = readLLDPNeighbors(lldp); err != nil {
+		return MachineDetails{}, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// detailsXML returns the XML with the specified name, which is binary, or
+// empty if the machine doesn't have it.
+func detailsXML(document map[string]interface{}, name string) ([]byte, error) {
+	var data []byte
+	switch value := document[name].(type) {
+	case nil:
+	case []byte:
+		data = value
+	case bson.Binary:
+		data = value.Data
+	case string:
+		data = []byte(value)
+	default:
+		return nil, NewDeserializationError("unexpected value for %s details, %T", name, value)
+	}
+	return bytes.TrimSpace(data), nil
+}
+
+type lshwNode struct {
+	XMLName     xml.Name
+	ID          string        `xml:"id,attr"`
+	Class       string        `xml:"class,attr"`
+	Disabled    bool          `xml:"disabled,attr"`
+	Description string        `xml:"description"`
+	Product     string        `xml:"product"`
+	Vendor      string        `xml:"vendor"`
+	Serial      string        `xml:"serial"`
+	Slot        string        `xml:"slot"`
+	BusInfo     string        `xml:"businfo"`
+	LogicalName []string      `xml:"logicalname"`
+	Version     string        `xml:"version"`
+	Size        uint64        `xml:"size"`
+	Clock       uint64        `xml:"clock"`
+	Settings    []lshwSetting `xml:"configuration>setting"`
+	Children    []lshwNode    `xml:"node"`
+}
+
+type lshwSetting struct {
+	ID    string `xml:"id,attr"`
+	Value string `xml:"value,attr"`
+}
+
+func (n *lshwNode) setting(id string) string {
+	for _, s := range n.Settings {
+		if s.ID == id {
+			return s.Value
+		}
+	}
+	return ""
+}
+
+func (n *lshwNode) intSetting(id string) int {
+	value, _ := strconv.Atoi(n.setting(id))
+	return value
+}
+
+func (n *lshwNode) logicalName() string {
+	if len(n.LogicalName) == 0 {
+		return ""
+	}
+	return n.LogicalName[0]
+}
+
+func (n *lshwNode) walk(visit func(*lshwNode)) {
+	visit(n)
+	for i := range n.Children {
+		n.Children[i].walk(visit)
+	}
+}
+
+func readHardwareDetails(source []byte) (HardwareDetails, error) {
+	var result HardwareDetails
+	if len(source) == 0 {
+		return result, nil
+	}
+	var root lshwNode
+	if err := xml.Unmarshal(source, &root); err != nil {
+		return result, WrapWithDeserializationError(err, "lshw details")
+	}
+	// Newer versions of lshw put the system node in a list.
+	if root.XMLName.Local == "list" && len(root.Children) > 0 {
+		root = root.Children[0]
+	}
+	result.Vendor = root.Vendor
+	result.Product = root.Product
+	result.Serial = root.Serial
+	root.walk(func(n *lshwNode) {
+		switch {
+		case n.Class == "processor":
+			result.CPUs = append(result.CPUs, HardwareCPU{
+				Product:      n.Product,
+				Vendor:       n.Vendor,
+				Slot:         n.Slot,
+				Speed:        n.Size,
+				Cores:        n.intSetting("cores"),
+				EnabledCores: n.intSetting("enabledcores"),
+				Threads:      n.intSetting("threads"),
+				Disabled:     n.Disabled,
+			})
+		case n.Class == "memory" && strings.HasPrefix(n.ID, "bank"):
+			result.MemoryBanks = append(result.MemoryBanks, HardwareMemoryBank{
+				Slot:        n.Slot,
+				Description: n.Description,
+				Product:     n.Product,
+				Vendor:      n.Vendor,
+				Serial:      n.Serial,
+				Size:        n.Size,
+				Clock:       n.Clock,
+			})
+		case n.Class == "network":
+			result.NICs = append(result.NICs, HardwareNIC{
+				Name:          n.logicalName(),
+				MACAddress:    n.Serial,
+				Product:       n.Product,
+				Vendor:        n.Vendor,
+				BusInfo:       n.BusInfo,
+				Driver:        n.setting("driver"),
+				DriverVersion: n.setting("driverversion"),
+				Firmware:      n.setting("firmware"),
+				Speed:         n.Size,
+				Link:          n.setting("link") == "yes",
+			})
+		case n.Class == "disk":
+			result.Disks = append(result.Disks, HardwareDisk{
+				Name:     n.logicalName(),
+				Product:  n.Product,
+				Vendor:   n.Vendor,
+				Serial:   n.Serial,
+				BusInfo:  n.BusInfo,
+				Firmware: n.Version,
+				Size:     n.Size,
+			})
+		}
+	})
+	return result, nil
+}
+
+type lldpDocument struct {
+	Interfaces []lldpInterface `xml:"interface"`
+}
+
+type lldpInterface struct {
+	Name    string `xml:"name,attr"`
+	Chassis struct {
+		ID      lldpID   `xml:"id"`
+		Name    string   `xml:"name"`
+		Descr   string   `xml:"descr"`
+		MgmtIPs []string `xml:"mgmt-ip"`
+	} `xml:"chassis"`
+	Port struct {
+		ID    lldpID `xml:"id"`
+		Descr string `xml:"descr"`
+	} `xml:"port"`
+	VLANs []struct {
+		ID   int    `xml:"vlan-id,attr"`
+		PVID string `xml:"pvid,attr"`
+		Name string `xml:",chardata"`
+	} `xml:"vlan"`
+}
+
+type lldpID struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+func readLLDPNeighbors(source []byte) ([]LLDPNeighbor, error) {
+	if len(source) == 0 {
+		return nil, nil
+	}
+	var document lldpDocument
+	if err := xml.Unmarshal(source, &document); err != nil {
+		return nil, WrapWithDeserializationError(err, "lldp details")
+	}
+	var result []LLDPNeighbor
+	for _, iface := range document.Interfaces {
+		neighbor := LLDPNeighbor{
+			Interface:           iface.Name,
+			ChassisID:           strings.TrimSpace(iface.Chassis.ID.Value),
+			ChassisIDType:       iface.Chassis.ID.Type,
+			SystemName:          strings.TrimSpace(iface.Chassis.Name),
+			SystemDescription:   strings.TrimSpace(iface.Chassis.Descr),
+			ManagementAddresses: iface.Chassis.MgmtIPs,
+			PortID:              strings.TrimSpace(iface.Port.ID.Value),
+			PortIDType:          iface.Port.ID.Type,
+			PortDescription:     strings.TrimSpace(iface.Port.Descr),
+		}
+		for _, vlan := range iface.VLANs {
+			neighbor.VLANs = append(neighbor.VLANs, LLDPVLAN{
+				ID:   vlan.ID,
+				Name: strings.TrimSpace(vlan.Name),
+				PVID: vlan.PVID == "yes",
+			})
+		}
+		result = append(result, neighbor)
+	}
+	return result, nil
+}
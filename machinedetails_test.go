@@ -0,0 +1,300 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/mgo/v2/bson"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type machineDetailsSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&machineDetailsSuite{})
+
+func machineDetailsBSON(c *gc.C, lshw, lldp string) []byte {
+	document := map[string]interface{}{
+		"lshw": []byte(lshw),
+		"lldp": []byte(lldp),
+	}
+	data, err := bson.Marshal(document)
+	c.Assert(err, jc.ErrorIsNil)
+	return data
+}
+
+func (*machineDetailsSuite) TestReadMachineDetailsBadBSON(c *gc.C) {
+	_, err := readMachineDetails([]byte("wat?"))
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+}
+
+func (*machineDetailsSuite) TestReadMachineDetailsBadXML(c *gc.C) {
+	_, err := readMachineDetails(machineDetailsBSON(c, "<node", ""))
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err, gc.ErrorMatches, "lshw details: .*")
+}
+
+func (*machineDetailsSuite) TestReadMachineDetailsEmpty(c *gc.C) {
+	data, err := bson.Marshal(map[string]interface{}{"lshw": nil, "lldp": nil})
+	c.Assert(err, jc.ErrorIsNil)
+	details, err := readMachineDetails(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details, jc.DeepEquals, MachineDetails{})
+}
+
+func (*machineDetailsSuite) TestReadMachineDetailsStrings(c *gc.C) {
+	// Older versions of MAAS stored the LLDP details as text.
+	data, err := bson.Marshal(map[string]interface{}{
+		"lshw": []byte(lshwDetails),
+		"lldp": "\n" + lldpDetails,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	details, err := readMachineDetails(data)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.LLDPNeighbors, gc.HasLen, 2)
+}
+
+func (*machineDetailsSuite) TestReadHardwareDetails(c *gc.C) {
+	details, err := readMachineDetails(machineDetailsBSON(c, lshwDetails, ""))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.LLDPNeighbors, gc.HasLen, 0)
+
+	hardware := details.Hardware
+	c.Check(hardware.Vendor, gc.Equals, "Dell Inc.")
+	c.Check(hardware.Product, gc.Equals, "PowerEdge R640 (SKU=086D;ModelName=PowerEdge R640)")
+	c.Check(hardware.Serial, gc.Equals, "7XKQ2Y2")
+
+	c.Check(hardware.CPUs, jc.DeepEquals, []HardwareCPU{{
+		Product:      "Intel(R) Xeon(R) Gold 6130 CPU @ 2.10GHz",
+		Vendor:       "Intel Corp.",
+		Slot:         "CPU1",
+		Speed:        2100000000,
+		Cores:        16,
+		EnabledCores: 16,
+		Threads:      32,
+	}, {
+		Vendor:   "Intel",
+		Slot:     "CPU2",
+		Disabled: true,
+	}})
+	c.Check(hardware.MemoryBanks, jc.DeepEquals, []HardwareMemoryBank{{
+		Slot:        "A1",
+		Description: "DIMM DDR4 Synchronous Registered (Buffered) 2666 MHz (0.4 ns)",
+		Product:     "36ASF2G72PZ-2G6D1",
+		Vendor:      "002C00B3002C",
+		Serial:      "1E2D8A17",
+		Size:        17179869184,
+		Clock:       2666000000,
+	}, {
+		Slot:        "A2",
+		Description: "[empty]",
+	}})
+	c.Check(hardware.NICs, jc.DeepEquals, []HardwareNIC{{
+		Name:          "eno1",
+		MACAddress:    "24:6e:96:a1:b2:c0",
+		Product:       "Ethernet Controller X710 for 10GbE SFP+",
+		Vendor:        "Intel Corporation",
+		BusInfo:       "pci@0000:19:00.0",
+		Driver:        "i40e",
+		DriverVersion: "2.8.20-k",
+		Firmware:      "6.80 0x80003d05 18.8.9",
+		Speed:         10000000000,
+		Link:          true,
+	}, {
+		Name:          "eno2",
+		MACAddress:    "24:6e:96:a1:b2:c2",
+		Product:       "Ethernet Controller X710 for 10GbE SFP+",
+		Vendor:        "Intel Corporation",
+		BusInfo:       "pci@0000:19:00.1",
+		Driver:        "i40e",
+		DriverVersion: "2.8.20-k",
+		Firmware:      "6.80 0x80003d05 18.8.9",
+	}})
+	c.Check(hardware.Disks, jc.DeepEquals, []HardwareDisk{{
+		Name:     "/dev/sda",
+		Product:  "MZ7KH480HAHQ0D3",
+		Vendor:   "Samsung",
+		Serial:   "S47MNA0M812345",
+		BusInfo:  "scsi@0:0.0.0",
+		Firmware: "HG58",
+		Size:     480103981056,
+	}})
+}
+
+func (*machineDetailsSuite) TestReadHardwareDetailsList(c *gc.C) {
+	details, err := readMachineDetails(machineDetailsBSON(c, "<list>"+lshwDetails[len(xmlHeader):]+"</list>", ""))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.Hardware.Serial, gc.Equals, "7XKQ2Y2")
+	c.Check(details.Hardware.CPUs, gc.HasLen, 2)
+}
+
+func (*machineDetailsSuite) TestReadLLDPNeighbors(c *gc.C) {
+	details, err := readMachineDetails(machineDetailsBSON(c, "", lldpDetails))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(details.Hardware, jc.DeepEquals, HardwareDetails{})
+	c.Check(details.LLDPNeighbors, jc.DeepEquals, []LLDPNeighbor{{
+		Interface:           "eno1",
+		ChassisID:           "00:1c:73:aa:bb:cc",
+		ChassisIDType:       "mac",
+		SystemName:          "leaf-01",
+		SystemDescription:   "Arista Networks EOS version 4.24.2F",
+		ManagementAddresses: []string{"10.0.0.11", "fd00::11"},
+		PortID:              "Ethernet12",
+		PortIDType:          "ifname",
+		PortDescription:     "rack-3 r640-07 eno1",
+		VLANs: []LLDPVLAN{{
+			ID:   100,
+			Name: "provisioning",
+			PVID: true,
+		}, {
+			ID:   200,
+			Name: "storage",
+		}},
+	}, {
+		Interface:     "eno2",
+		ChassisID:     "00:1c:73:dd:ee:ff",
+		ChassisIDType: "mac",
+		SystemName:    "leaf-02",
+		PortID:        "Ethernet12",
+		PortIDType:    "ifname",
+	}})
+}
+
+const xmlHeader = `<?xml version="1.0" standalone="yes" ?>`
+
+const lshwDetails = xmlHeader + `
+<node id="r640-07" claimed="true" class="system" handle="DMI:0100">
+  <description>Rack Mount Chassis</description>
+  <product>PowerEdge R640 (SKU=086D;ModelName=PowerEdge R640)</product>
+  <vendor>Dell Inc.</vendor>
+  <serial>7XKQ2Y2</serial>
+  <width units="bits">64</width>
+  <configuration>
+    <setting id="boot" value="normal" />
+    <setting id="chassis" value="rackmount" />
+  </configuration>
+  <node id="core" claimed="true" class="bus" handle="DMI:0200">
+    <description>Motherboard</description>
+    <product>0CRT1G</product>
+    <vendor>Dell Inc.</vendor>
+    <node id="cpu:0" claimed="true" class="processor" handle="DMI:0400">
+      <description>CPU</description>
+      <product>Intel(R) Xeon(R) Gold 6130 CPU @ 2.10GHz</product>
+      <vendor>Intel Corp.</vendor>
+      <slot>CPU1</slot>
+      <size units="Hz">2100000000</size>
+      <capacity units="Hz">4000000000</capacity>
+      <configuration>
+        <setting id="cores" value="16" />
+        <setting id="enabledcores" value="16" />
+        <setting id="threads" value="32" />
+      </configuration>
+    </node>
+    <node id="cpu:1" disabled="true" claimed="true" class="processor" handle="DMI:0401">
+      <description>CPU</description>
+      <vendor>Intel</vendor>
+      <slot>CPU2</slot>
+    </node>
+    <node id="memory" claimed="true" class="memory" handle="DMI:1000">
+      <description>System Memory</description>
+      <slot>System board or motherboard</slot>
+      <size units="bytes">17179869184</size>
+      <node id="bank:0" claimed="true" class="memory" handle="DMI:1100">
+        <description>DIMM DDR4 Synchronous Registered (Buffered) 2666 MHz (0.4 ns)</description>
+        <product>36ASF2G72PZ-2G6D1</product>
+        <vendor>002C00B3002C</vendor>
+        <serial>1E2D8A17</serial>
+        <slot>A1</slot>
+        <size units="bytes">17179869184</size>
+        <clock units="Hz">2666000000</clock>
+      </node>
+      <node id="bank:1" claimed="true" class="memory" handle="DMI:1101">
+        <description>[empty]</description>
+        <slot>A2</slot>
+      </node>
+    </node>
+    <node id="pci:0" claimed="true" class="bridge" handle="PCIBUS:0000:17">
+      <description>PCI bridge</description>
+      <node id="network:0" claimed="true" class="network" handle="PCI:0000:19:00.0">
+        <description>Ethernet interface</description>
+        <product>Ethernet Controller X710 for 10GbE SFP+</product>
+        <vendor>Intel Corporation</vendor>
+        <businfo>pci@0000:19:00.0</businfo>
+        <logicalname>eno1</logicalname>
+        <version>02</version>
+        <serial>24:6e:96:a1:b2:c0</serial>
+        <size units="bit/s">10000000000</size>
+        <configuration>
+          <setting id="autonegotiation" value="off" />
+          <setting id="driver" value="i40e" />
+          <setting id="driverversion" value="2.8.20-k" />
+          <setting id="firmware" value="6.80 0x80003d05 18.8.9" />
+          <setting id="link" value="yes" />
+        </configuration>
+      </node>
+      <node id="network:1" disabled="true" claimed="true" class="network" handle="PCI:0000:19:00.1">
+        <description>Ethernet interface</description>
+        <product>Ethernet Controller X710 for 10GbE SFP+</product>
+        <vendor>Intel Corporation</vendor>
+        <businfo>pci@0000:19:00.1</businfo>
+        <logicalname>eno2</logicalname>
+        <serial>24:6e:96:a1:b2:c2</serial>
+        <configuration>
+          <setting id="driver" value="i40e" />
+          <setting id="driverversion" value="2.8.20-k" />
+          <setting id="firmware" value="6.80 0x80003d05 18.8.9" />
+          <setting id="link" value="no" />
+        </configuration>
+      </node>
+    </node>
+    <node id="scsi" claimed="true" class="storage" handle="">
+      <physid>0</physid>
+      <logicalname>scsi0</logicalname>
+      <node id="disk" claimed="true" class="disk" handle="SCSI:00:00:00:00">
+        <description>ATA Disk</description>
+        <product>MZ7KH480HAHQ0D3</product>
+        <vendor>Samsung</vendor>
+        <businfo>scsi@0:0.0.0</businfo>
+        <logicalname>/dev/sda</logicalname>
+        <version>HG58</version>
+        <serial>S47MNA0M812345</serial>
+        <size units="bytes">480103981056</size>
+      </node>
+    </node>
+  </node>
+</node>
+`
+
+const lldpDetails = `<?xml version="1.0" encoding="UTF-8"?>
+<lldp label="LLDP neighbors">
+ <interface label="Interface" name="eno1" via="LLDP" rid="1" age="0 day, 00:12:01">
+  <chassis label="Chassis">
+   <id label="ChassisID" type="mac">00:1c:73:aa:bb:cc</id>
+   <name label="SysName">leaf-01</name>
+   <descr label="SysDescr">Arista Networks EOS version 4.24.2F</descr>
+   <mgmt-ip label="MgmtIP">10.0.0.11</mgmt-ip>
+   <mgmt-ip label="MgmtIP">fd00::11</mgmt-ip>
+   <capability label="Capability" type="Bridge" enabled="on"/>
+  </chassis>
+  <port label="Port">
+   <id label="PortID" type="ifname">Ethernet12</id>
+   <descr label="PortDescr">rack-3 r640-07 eno1</descr>
+   <ttl label="TTL">120</ttl>
+  </port>
+  <vlan label="VLAN" vlan-id="100" pvid="yes">provisioning</vlan>
+  <vlan label="VLAN" vlan-id="200">storage</vlan>
+ </interface>
+ <interface label="Interface" name="eno2" via="LLDP" rid="2" age="0 day, 00:12:01">
+  <chassis label="Chassis">
+   <id label="ChassisID" type="mac">00:1c:73:dd:ee:ff</id>
+   <name label="SysName">leaf-02</name>
+  </chassis>
+  <port label="Port">
+   <id label="PortID" type="ifname">Ethernet12</id>
+  </port>
+ </interface>
+</lldp>
+`
@@ -10,6 +10,8 @@ import (
 )
 
 type partition struct {
+	controller *controller
+
 	resourceURI string
 
 	id      int
@@ -22,6 +24,17 @@ type partition struct {
 	filesystem *filesystem
 }
 
+func (p *partition) updateFrom(other *partition) {
+	p.resourceURI = other.resourceURI
+	p.id = other.id
+	p.path = other.path
+	p.uuid = other.uuid
+	p.usedFor = other.usedFor
+	p.size = other.size
+	p.tags = other.tags
+	p.filesystem = other.filesystem
+}
+
 // Type implements Partition.
 func (p *partition) Type() string {
 	return "partition"
@@ -65,6 +78,79 @@ func (p *partition) Tags() []string {
 	return p.tags
 }
 
+// Format implements Partition.
+func (p *partition) Format(args FormatStorageDeviceArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("fstype", args.FSType)
+	params.MaybeAdd("uuid", args.UUID)
+	params.MaybeAdd("label", args.Label)
+	return errors.Trace(p.postOp("format", params))
+}
+
+// Unformat implements Partition.
+func (p *partition) Unformat() error {
+	return errors.Trace(p.postOp("unformat", NewURLParams()))
+}
+
+// Mount implements Partition.
+func (p *partition) Mount(args MountStorageDeviceArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("mount_point", args.MountPoint)
+	params.MaybeAdd("mount_options", args.MountOptions)
+	return errors.Trace(p.postOp("mount", params))
+}
+
+// Unmount implements Partition.
+func (p *partition) Unmount() error {
+	return errors.Trace(p.postOp("unmount", NewURLParams()))
+}
+
+// Delete implements Partition.
+func (p *partition) Delete() error {
+	err := p.controller.delete(p.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// postOp calls the specified operation on the partition and refreshes the
+// partition from the response.
+func (p *partition) postOp(op string, params *URLParams) error {
+	source, err := p.controller.post(p.resourceURI, op, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readPartition(p.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p.updateFrom(response)
+	return nil
+}
+
+func readPartition(controllerVersion version.Number, source interface{}) (*partition, error) {
+	readFunc, err := getPartitionDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "partition base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readPartitions(controllerVersion version.Number, source interface{}) ([]*partition, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -73,6 +159,14 @@ func readPartitions(controllerVersion version.Number, source interface{}) ([]*pa
 	}
 	valid := coerced.([]interface{})
 
+	readFunc, err := getPartitionDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readPartitionList(valid, readFunc)
+}
+
+func getPartitionDeserializationFunc(controllerVersion version.Number) (partitionDeserializationFunc, error) {
 	var deserialisationVersion version.Number
 	for v := range partitionDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
@@ -82,8 +176,7 @@ func readPartitions(controllerVersion version.Number, source interface{}) ([]*pa
 	if deserialisationVersion == version.Zero {
 		return nil, NewUnsupportedVersionError("no partition read func for version %s", controllerVersion)
 	}
-	readFunc := partitionDeserializationFuncs[deserialisationVersion]
-	return readPartitionList(valid, readFunc)
+	return partitionDeserializationFuncs[deserialisationVersion], nil
 }
 
 // readPartitionList expects the values of the sourceList to be string maps.
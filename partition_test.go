@@ -4,12 +4,18 @@
 package gomaasapi
 
 import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 )
 
-type partitionSuite struct{}
+type partitionSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&partitionSuite{})
 
@@ -70,8 +76,116 @@ func (*partitionSuite) TestHighVersion(c *gc.C) {
 	c.Assert(partitions, gc.HasLen, 1)
 }
 
-var partitionsResponse = `
-[
+func (s *partitionSuite) getServerAndPartition(c *gc.C) (*SimpleTestServer, *partition) {
+	server, controller := createTestServerController(c, s)
+	server.AddGetResponse("/api/2.0/machines/", http.StatusOK, "["+machineResponse+"]")
+	machines, err := controller.Machines(MachinesArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 1)
+	partition := machines[0].Partition(1).(*partition)
+	server.ResetRequests()
+	return server, partition
+}
+
+func (s *partitionSuite) TestFormat(c *gc.C) {
+	server, partition := s.getServerAndPartition(c)
+	response := updateJSONMap(c, partitionResponse, map[string]interface{}{
+		"filesystem": map[string]interface{}{
+			"fstype": "xfs",
+			"label":  "data",
+			"uuid":   "d2c0b4a5-0e9e-4c10-9c5b-77e1e6c08f6b",
+		},
+	})
+	server.AddPostResponse(partition.resourceURI+"/?op=format", http.StatusOK, response)
+
+	err := partition.Format(FormatStorageDeviceArgs{FSType: "xfs", Label: "data"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(partition.FileSystem().Type(), gc.Equals, "xfs")
+	c.Assert(partition.FileSystem().Label(), gc.Equals, "data")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 2)
+	c.Check(form.Get("fstype"), gc.Equals, "xfs")
+	c.Check(form.Get("label"), gc.Equals, "data")
+}
+
+func (s *partitionSuite) TestFormatValidates(c *gc.C) {
+	_, partition := s.getServerAndPartition(c)
+	err := partition.Format(FormatStorageDeviceArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *partitionSuite) TestUnformat(c *gc.C) {
+	server, partition := s.getServerAndPartition(c)
+	response := updateJSONMap(c, partitionResponse, map[string]interface{}{
+		"filesystem": nil,
+	})
+	server.AddPostResponse(partition.resourceURI+"/?op=unformat", http.StatusOK, response)
+
+	err := partition.Unformat()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(partition.FileSystem(), gc.IsNil)
+}
+
+func (s *partitionSuite) TestMount(c *gc.C) {
+	server, partition := s.getServerAndPartition(c)
+	response := updateJSONMap(c, partitionResponse, map[string]interface{}{
+		"filesystem": map[string]interface{}{
+			"fstype":      "ext4",
+			"mount_point": "/srv",
+			"uuid":        "fcd7745e-f1b5-4f5d-9575-9b0bb796b752",
+		},
+	})
+	server.AddPostResponse(partition.resourceURI+"/?op=mount", http.StatusOK, response)
+
+	err := partition.Mount(MountStorageDeviceArgs{MountPoint: "/srv"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(partition.FileSystem().MountPoint(), gc.Equals, "/srv")
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 1)
+	c.Check(form.Get("mount_point"), gc.Equals, "/srv")
+}
+
+func (s *partitionSuite) TestMountConflict(c *gc.C) {
+	server, partition := s.getServerAndPartition(c)
+	server.AddPostResponse(partition.resourceURI+"/?op=mount", http.StatusConflict, "machine not ready")
+	err := partition.Mount(MountStorageDeviceArgs{MountPoint: "/srv"})
+	c.Assert(err, jc.Satisfies, IsCannotCompleteError)
+}
+
+func (s *partitionSuite) TestUnmount(c *gc.C) {
+	server, partition := s.getServerAndPartition(c)
+	response := updateJSONMap(c, partitionResponse, map[string]interface{}{
+		"filesystem": map[string]interface{}{
+			"fstype": "ext4",
+			"uuid":   "fcd7745e-f1b5-4f5d-9575-9b0bb796b752",
+		},
+	})
+	server.AddPostResponse(partition.resourceURI+"/?op=unmount", http.StatusOK, response)
+
+	err := partition.Unmount()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(partition.FileSystem().MountPoint(), gc.Equals, "")
+}
+
+func (s *partitionSuite) TestDelete(c *gc.C) {
+	server, partition := s.getServerAndPartition(c)
+	server.AddDeleteResponse(partition.resourceURI+"/", http.StatusNoContent, "")
+	err := partition.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *partitionSuite) TestDeleteForbidden(c *gc.C) {
+	server, partition := s.getServerAndPartition(c)
+	server.AddDeleteResponse(partition.resourceURI+"/", http.StatusForbidden, "")
+	err := partition.Delete()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+var (
+	partitionsResponse = "[" + partitionResponse + "]"
+	partitionResponse  = `
     {
         "bootable": false,
         "id": 1,
@@ -90,5 +204,5 @@ var partitionsResponse = `
 		"size": 8581545984,
 		"tags": ["ssd-part", "osd-part"]
     }
-]
 `
+)
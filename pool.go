@@ -10,13 +10,31 @@ import (
 )
 
 type pool struct {
-	// Add the controller in when we need to do things with the pool.
-	// controller Controller
+	controller *controller
 
 	resourceURI string
 
+	id          int
 	name        string
 	description string
+
+	// The machine and device counts are only included in the pool
+	// listing by some versions of MAAS, hasCounts records whether they were.
+	hasCounts bool
+	machines  int
+	devices   int
+}
+
+func (p *pool) updateFrom(other *pool) {
+	p.resourceURI = other.resourceURI
+	p.id = other.id
+	p.name = other.name
+	p.description = other.description
+}
+
+// ID implements Pool.
+func (p *pool) ID() int {
+	return p.id
 }
 
 // Name implements Pool.
@@ -29,9 +47,63 @@ func (p *pool) Description() string {
 	return p.description
 }
 
-func readPools(controllerVersion version.Number, source interface{}) ([]*pool, error) {
-	var deserialisationVersion version.Number
+// UpdatePoolArgs is an argument struct for passing parameters to the
+// Pool.Update method. Only the values that are specified are changed.
+type UpdatePoolArgs struct {
+	Name string
+	// Description replaces the existing description when not nil. An empty
+	// string removes the description.
+	Description *string
+}
+
+// Update implements Pool.
+func (p *pool) Update(args UpdatePoolArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	if args.Description != nil {
+		params.Values.Add("description", *args.Description)
+	}
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := p.controller.put(p.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readPool(p.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	p.updateFrom(response)
+	return nil
+}
+
+// Delete implements Pool.
+func (p *pool) Delete() error {
+	err := p.controller.delete(p.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
 
+func readPool(controllerVersion version.Number, source interface{}) (*pool, error) {
+	readFunc, err := getPoolDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "pool base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readPools(controllerVersion version.Number, source interface{}) ([]*pool, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
 
@@ -41,6 +113,16 @@ func readPools(controllerVersion version.Number, source interface{}) ([]*pool, e
 
 	valid := coerced.([]interface{})
 
+	readFunc, err := getPoolDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readPoolList(valid, readFunc)
+}
+
+func getPoolDeserializationFunc(controllerVersion version.Number) (poolDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+
 	for v := range poolDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
 			deserialisationVersion = v
@@ -51,8 +133,7 @@ func readPools(controllerVersion version.Number, source interface{}) ([]*pool, e
 		return nil, errors.Errorf("no pool read func for version %s", controllerVersion)
 	}
 
-	readFunc := poolDeserializationFuncs[deserialisationVersion]
-	return readPoolList(valid, readFunc)
+	return poolDeserializationFuncs[deserialisationVersion], nil
 }
 
 // readPoolList expects the values of the sourceList to be string maps.
@@ -81,12 +162,21 @@ var poolDeserializationFuncs = map[version.Number]poolDeserializationFunc{
 
 func pool_2_0(source map[string]interface{}) (*pool, error) {
 	fields := schema.Fields{
+		"id":           schema.ForceInt(),
 		"name":         schema.String(),
 		"description":  schema.String(),
 		"resource_uri": schema.String(),
+		"machines":     schema.OneOf(schema.Nil(""), schema.ForceInt()),
+		"devices":      schema.OneOf(schema.Nil(""), schema.ForceInt()),
 	}
 
-	checker := schema.FieldMap(fields, nil) // no defaults
+	defaults := schema.Defaults{
+		"id":       0,
+		"machines": nil,
+		"devices":  nil,
+	}
+
+	checker := schema.FieldMap(fields, defaults)
 
 	coerced, err := checker.Coerce(source, nil)
 	if err != nil {
@@ -96,10 +186,17 @@ func pool_2_0(source map[string]interface{}) (*pool, error) {
 	// From here we know that the map returned from the schema coercion
 	// contains fields of the right type.
 
+	machines, hasMachines := valid["machines"].(int)
+	devices, hasDevices := valid["devices"].(int)
+
 	result := &pool{
+		id:          valid["id"].(int),
 		name:        valid["name"].(string),
 		description: valid["description"].(string),
 		resourceURI: valid["resource_uri"].(string),
+		hasCounts:   hasMachines && hasDevices,
+		machines:    machines,
+		devices:     devices,
 	}
 	return result, nil
 }
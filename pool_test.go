@@ -4,12 +4,18 @@
 package gomaasapi
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 )
 
-type poolSuite struct{}
+type poolSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&poolSuite{})
 
@@ -44,6 +50,81 @@ func (*poolSuite) TestHighVersion(c *gc.C) {
 	c.Assert(pools, gc.HasLen, 2)
 }
 
+func (s *poolSuite) getServerAndPool(c *gc.C) (*SimpleTestServer, *pool) {
+	server, ctrl := createTestServerController(c, s)
+	pools, err := readPools(twoDotOh, parseJSON(c, poolResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	pools[1].controller = ctrl.(*controller)
+	return server, pools[1]
+}
+
+func (s *poolSuite) TestUpdate(c *gc.C) {
+	server, pool := s.getServerAndPool(c)
+	server.AddPutResponse(pool.resourceURI, http.StatusOK, `{
+        "id": 2,
+        "name": "renamed",
+        "description": "new description",
+        "resource_uri": "/MAAS/api/2.0/pools/renamed/"
+    }`)
+	description := "new description"
+	err := pool.Update(UpdatePoolArgs{Name: "renamed", Description: &description})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(pool.ID(), gc.Equals, 2)
+	c.Check(pool.Name(), gc.Equals, "renamed")
+	c.Check(pool.Description(), gc.Equals, "new description")
+	c.Check(pool.resourceURI, gc.Equals, "/MAAS/api/2.0/pools/renamed/")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("name"), gc.Equals, "renamed")
+	c.Check(form.Get("description"), gc.Equals, "new description")
+}
+
+func (s *poolSuite) TestUpdateClearDescription(c *gc.C) {
+	server, pool := s.getServerAndPool(c)
+	server.AddPutResponse(pool.resourceURI, http.StatusOK, `{
+        "description": "",
+        "resource_uri": "/MAAS/api/2.0/pools/swimming_is_fun/",
+        "name": "swimming_is_fun"
+    }`)
+	description := ""
+	err := pool.Update(UpdatePoolArgs{Description: &description})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(pool.Description(), gc.Equals, "")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{"description": {""}})
+}
+
+func (s *poolSuite) TestUpdateNothing(c *gc.C) {
+	server, pool := s.getServerAndPool(c)
+	count := server.RequestCount()
+	err := pool.Update(UpdatePoolArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *poolSuite) TestUpdateBadRequest(c *gc.C) {
+	server, pool := s.getServerAndPool(c)
+	server.AddPutResponse(pool.resourceURI, http.StatusBadRequest, "name in use")
+	err := pool.Update(UpdatePoolArgs{Name: "default"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "name in use")
+}
+
+func (s *poolSuite) TestDelete(c *gc.C) {
+	server, pool := s.getServerAndPool(c)
+	server.AddDeleteResponse(pool.resourceURI, http.StatusNoContent, "")
+	err := pool.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *poolSuite) TestDeleteMissing(c *gc.C) {
+	_, pool := s.getServerAndPool(c)
+	err := pool.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
 var poolResponse = `
 [
     {
@@ -0,0 +1,323 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+// RAIDLevel is the level of a software RAID.
+type RAIDLevel string
+
+const (
+	// RAID0 stripes data across the members.
+	RAID0 RAIDLevel = "raid-0"
+
+	// RAID1 mirrors data across the members.
+	RAID1 RAIDLevel = "raid-1"
+
+	// RAID5 stripes data with distributed parity.
+	RAID5 RAIDLevel = "raid-5"
+
+	// RAID6 stripes data with double distributed parity.
+	RAID6 RAIDLevel = "raid-6"
+
+	// RAID10 stripes data across mirrored members.
+	RAID10 RAIDLevel = "raid-10"
+)
+
+type raid struct {
+	controller *controller
+
+	resourceURI string
+
+	id    int
+	name  string
+	uuid  string
+	level string
+	size  uint64
+
+	blockDevices      []*blockdevice
+	partitions        []*partition
+	spareBlockDevices []*blockdevice
+	sparePartitions   []*partition
+
+	virtualDevice *blockdevice
+}
+
+func (r *raid) updateFrom(other *raid) {
+	r.resourceURI = other.resourceURI
+	r.id = other.id
+	r.name = other.name
+	r.uuid = other.uuid
+	r.level = other.level
+	r.size = other.size
+	r.blockDevices = other.blockDevices
+	r.partitions = other.partitions
+	r.spareBlockDevices = other.spareBlockDevices
+	r.sparePartitions = other.sparePartitions
+	r.virtualDevice = other.virtualDevice
+}
+
+// ID implements RAID.
+func (r *raid) ID() int {
+	return r.id
+}
+
+// Name implements RAID.
+func (r *raid) Name() string {
+	return r.name
+}
+
+// UUID implements RAID.
+func (r *raid) UUID() string {
+	return r.uuid
+}
+
+// Level implements RAID.
+func (r *raid) Level() string {
+	return r.level
+}
+
+// Size implements RAID.
+func (r *raid) Size() uint64 {
+	return r.size
+}
+
+// Devices implements RAID.
+func (r *raid) Devices() []StorageDevice {
+	return storageDevices(r.controller, r.blockDevices, r.partitions)
+}
+
+// SpareDevices implements RAID.
+func (r *raid) SpareDevices() []StorageDevice {
+	return storageDevices(r.controller, r.spareBlockDevices, r.sparePartitions)
+}
+
+// VirtualDevice implements RAID.
+func (r *raid) VirtualDevice() BlockDevice {
+	if r.virtualDevice == nil {
+		return nil
+	}
+	r.virtualDevice.controller = r.controller
+	return r.virtualDevice
+}
+
+// CreateRAIDArgs is an argument struct for passing parameters to the
+// Machine.CreateRAID method.
+type CreateRAIDArgs struct {
+	// Name is optional, and MAAS generates one if it isn't specified.
+	Name string
+	// UUID is optional, and MAAS generates one if it isn't specified.
+	UUID string
+	// Level is required.
+	Level RAIDLevel
+
+	// BlockDevices and Partitions are the active members of the RAID. At
+	// least one block device or partition is required.
+	BlockDevices []BlockDevice
+	Partitions   []Partition
+
+	// SpareBlockDevices and SparePartitions are optional.
+	SpareBlockDevices []BlockDevice
+	SparePartitions   []Partition
+}
+
+// Validate ensures that the Level is known and that there is at least one
+// member of the RAID.
+func (a *CreateRAIDArgs) Validate() error {
+	switch a.Level {
+	case RAID0, RAID1, RAID5, RAID6, RAID10:
+	case "":
+		return errors.NotValidf("missing Level")
+	default:
+		return errors.NotValidf("unknown Level value (%q)", a.Level)
+	}
+	if len(a.BlockDevices) == 0 && len(a.Partitions) == 0 {
+		return errors.NotValidf("missing BlockDevices or Partitions")
+	}
+	return nil
+}
+
+// UpdateRAIDArgs is an argument struct for passing parameters to the
+// RAID.Update method. Only the values that are specified are changed.
+type UpdateRAIDArgs struct {
+	Name string
+	UUID string
+
+	AddBlockDevices    []BlockDevice
+	RemoveBlockDevices []BlockDevice
+	AddPartitions      []Partition
+	RemovePartitions   []Partition
+
+	AddSpareBlockDevices    []BlockDevice
+	RemoveSpareBlockDevices []BlockDevice
+	AddSparePartitions      []Partition
+	RemoveSparePartitions   []Partition
+}
+
+// Update implements RAID.
+func (r *raid) Update(args UpdateRAIDArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("uuid", args.UUID)
+	addBlockDeviceIDs(params, "add_block_devices", args.AddBlockDevices)
+	addBlockDeviceIDs(params, "remove_block_devices", args.RemoveBlockDevices)
+	addPartitionIDs(params, "add_partitions", args.AddPartitions)
+	addPartitionIDs(params, "remove_partitions", args.RemovePartitions)
+	addBlockDeviceIDs(params, "add_spare_devices", args.AddSpareBlockDevices)
+	addBlockDeviceIDs(params, "remove_spare_devices", args.RemoveSpareBlockDevices)
+	addPartitionIDs(params, "add_spare_partitions", args.AddSparePartitions)
+	addPartitionIDs(params, "remove_spare_partitions", args.RemoveSparePartitions)
+	source, err := r.controller.put(r.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readRAID(r.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	r.updateFrom(response)
+	return nil
+}
+
+// Delete implements RAID.
+func (r *raid) Delete() error {
+	err := r.controller.delete(r.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+func readRAID(controllerVersion version.Number, source interface{}) (*raid, error) {
+	readFunc, err := getRAIDDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "raid base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readRAIDs(controllerVersion version.Number, source interface{}) ([]*raid, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "raid base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getRAIDDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readRAIDList(valid, readFunc)
+}
+
+func getRAIDDeserializationFunc(controllerVersion version.Number) (raidDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range raidDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no raid read func for version %s", controllerVersion)
+	}
+	return raidDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readRAIDList expects the values of the sourceList to be string maps.
+func readRAIDList(sourceList []interface{}, readFunc raidDeserializationFunc) ([]*raid, error) {
+	result := make([]*raid, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for raid %d, %T", i, value)
+		}
+		raid, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "raid %d", i)
+		}
+		result = append(result, raid)
+	}
+	return result, nil
+}
+
+type raidDeserializationFunc func(map[string]interface{}) (*raid, error)
+
+var raidDeserializationFuncs = map[version.Number]raidDeserializationFunc{
+	twoDotOh: raid_2_0,
+}
+
+func raid_2_0(source map[string]interface{}) (*raid, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":    schema.ForceInt(),
+		"name":  schema.String(),
+		"uuid":  schema.OneOf(schema.Nil(""), schema.String()),
+		"level": schema.String(),
+		"size":  schema.ForceUint(),
+
+		"devices":        schema.List(schema.StringMap(schema.Any())),
+		"spare_devices":  schema.List(schema.StringMap(schema.Any())),
+		"virtual_device": schema.OneOf(schema.Nil(""), schema.StringMap(schema.Any())),
+	}
+	defaults := schema.Defaults{
+		"spare_devices":  []interface{}{},
+		"virtual_device": nil,
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "raid 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	blockDevices, partitions, err := storageDeviceList_2_0(valid["devices"].([]interface{}))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	spareBlockDevices, spareParts, err := storageDeviceList_2_0(valid["spare_devices"].([]interface{}))
+	if err != nil {
+		return nil, errors.Annotate(err, "spare")
+	}
+	var virtualDevice *blockdevice
+	if deviceSource, ok := valid["virtual_device"].(map[string]interface{}); ok {
+		if virtualDevice, err = blockdevice_2_0(deviceSource); err != nil {
+			return nil, errors.Annotate(err, "virtual device")
+		}
+	}
+
+	uuid, _ := valid["uuid"].(string)
+	result := &raid{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:    valid["id"].(int),
+		name:  valid["name"].(string),
+		uuid:  uuid,
+		level: valid["level"].(string),
+		size:  valid["size"].(uint64),
+
+		blockDevices:      blockDevices,
+		partitions:        partitions,
+		spareBlockDevices: spareBlockDevices,
+		sparePartitions:   spareParts,
+
+		virtualDevice: virtualDevice,
+	}
+	return result, nil
+}
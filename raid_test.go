@@ -0,0 +1,255 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type raidSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&raidSuite{})
+
+func (*raidSuite) TestReadRAIDsBadSchema(c *gc.C) {
+	_, err := readRAIDs(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `raid base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*raidSuite) TestReadRAIDs(c *gc.C) {
+	raids, err := readRAIDs(twoDotOh, parseJSON(c, raidsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(raids, gc.HasLen, 1)
+	raid := raids[0]
+
+	c.Check(raid.ID(), gc.Equals, 63)
+	c.Check(raid.Name(), gc.Equals, "md0")
+	c.Check(raid.UUID(), gc.Equals, "3b9d4d8e-8f5c-4a1c-a0d4-1a3e8f0b4c2d")
+	c.Check(raid.Level(), gc.Equals, "raid-1")
+	c.Check(raid.Size(), gc.Equals, uint64(8581545984))
+
+	devices := raid.Devices()
+	c.Assert(devices, gc.HasLen, 2)
+	c.Check(devices[0].Type(), gc.Equals, "blockdevice")
+	c.Check(devices[0].ID(), gc.Equals, 98)
+	c.Check(devices[1].Type(), gc.Equals, "partition")
+	c.Check(devices[1].ID(), gc.Equals, 102)
+
+	spares := raid.SpareDevices()
+	c.Assert(spares, gc.HasLen, 1)
+	c.Check(spares[0].ID(), gc.Equals, 103)
+
+	virtual := raid.VirtualDevice()
+	c.Assert(virtual, gc.NotNil)
+	c.Check(virtual.ID(), gc.Equals, 64)
+	c.Check(virtual.Name(), gc.Equals, "md0")
+}
+
+func (*raidSuite) TestReadRAIDsNoVirtualDevice(c *gc.C) {
+	json := parseJSON(c, raidsResponse)
+	delete(json.([]interface{})[0].(map[string]interface{}), "virtual_device")
+	raids, err := readRAIDs(twoDotOh, json)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(raids[0].VirtualDevice(), gc.IsNil)
+}
+
+func (*raidSuite) TestLowVersion(c *gc.C) {
+	_, err := readRAIDs(version.MustParse("1.9.0"), parseJSON(c, raidsResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*raidSuite) TestHighVersion(c *gc.C) {
+	raids, err := readRAIDs(version.MustParse("2.1.9"), parseJSON(c, raidsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(raids, gc.HasLen, 1)
+}
+
+func (*raidSuite) TestCreateRAIDArgsValidate(c *gc.C) {
+	member := []BlockDevice{&blockdevice{}}
+	for i, test := range []struct {
+		args    CreateRAIDArgs
+		errText string
+	}{{
+		errText: "missing Level not valid",
+	}, {
+		args:    CreateRAIDArgs{Level: "raid-4", BlockDevices: member},
+		errText: `unknown Level value ("raid-4") not valid`,
+	}, {
+		args:    CreateRAIDArgs{Level: RAID1},
+		errText: "missing BlockDevices or Partitions not valid",
+	}, {
+		args: CreateRAIDArgs{Level: RAID1, BlockDevices: member},
+	}, {
+		args: CreateRAIDArgs{Level: RAID0, Partitions: []Partition{&partition{}}},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *raidSuite) getServerAndRAID(c *gc.C) (*SimpleTestServer, *raid) {
+	server, ctrl := createTestServerController(c, s)
+	raid, err := readRAID(twoDotOh, parseJSON(c, raidResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	raid.controller = ctrl.(*controller)
+	return server, raid
+}
+
+func (s *raidSuite) TestUpdate(c *gc.C) {
+	server, raid := s.getServerAndRAID(c)
+	response := updateJSONMap(c, raidResponse, map[string]interface{}{
+		"name":          "md-data",
+		"spare_devices": []interface{}{},
+	})
+	server.AddPutResponse(raid.resourceURI, http.StatusOK, response)
+
+	err := raid.Update(UpdateRAIDArgs{
+		Name:                  "md-data",
+		RemoveSparePartitions: []Partition{&partition{id: 103}},
+		AddBlockDevices:       []BlockDevice{&blockdevice{id: 99}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(raid.Name(), gc.Equals, "md-data")
+	c.Check(raid.SpareDevices(), gc.HasLen, 0)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 3)
+	c.Check(form.Get("name"), gc.Equals, "md-data")
+	c.Check(form["add_block_devices"], gc.DeepEquals, []string{"99"})
+	c.Check(form["remove_spare_partitions"], gc.DeepEquals, []string{"103"})
+}
+
+func (s *raidSuite) TestUpdateBadRequest(c *gc.C) {
+	server, raid := s.getServerAndRAID(c)
+	server.AddPutResponse(raid.resourceURI, http.StatusBadRequest, "device in use")
+	err := raid.Update(UpdateRAIDArgs{AddBlockDevices: []BlockDevice{&blockdevice{id: 99}}})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "device in use")
+}
+
+func (s *raidSuite) TestDelete(c *gc.C) {
+	server, raid := s.getServerAndRAID(c)
+	server.AddDeleteResponse(raid.resourceURI, http.StatusNoContent, "")
+	err := raid.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *raidSuite) TestDeleteForbidden(c *gc.C) {
+	server, raid := s.getServerAndRAID(c)
+	server.AddDeleteResponse(raid.resourceURI, http.StatusForbidden, "")
+	err := raid.Delete()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+var (
+	raidsResponse = "[" + raidResponse + "]"
+	raidResponse  = `
+    {
+        "id": 63,
+        "uuid": "3b9d4d8e-8f5c-4a1c-a0d4-1a3e8f0b4c2d",
+        "name": "md0",
+        "level": "raid-1",
+        "size": 8581545984,
+        "human_size": "8.6 GB",
+        "system_id": "4y3ha3",
+        "devices": [
+            {
+                "path": "/dev/disk/by-dname/sdb",
+                "name": "sdb",
+                "used_for": "Active raid-1 device for md0",
+                "partitions": [],
+                "filesystem": {
+                    "fstype": "raid",
+                    "mount_point": null,
+                    "label": null,
+                    "mount_options": null,
+                    "uuid": "0d4a9b53-4c8e-4f0a-9a8c-3b1f5e9c7a21"
+                },
+                "id_path": "/dev/disk/by-id/ata-QEMU_HARDDISK_QM00002",
+                "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/98/",
+                "id": 98,
+                "type": "physical",
+                "block_size": 4096,
+                "used_size": 8589934592,
+                "available_size": 0,
+                "uuid": null,
+                "size": 8589934592,
+                "model": "QEMU HARDDISK",
+                "tags": []
+            },
+            {
+                "bootable": false,
+                "id": 102,
+                "path": "/dev/disk/by-dname/sdc-part1",
+                "filesystem": {
+                    "fstype": "raid",
+                    "mount_point": null,
+                    "label": null,
+                    "mount_options": null,
+                    "uuid": "6f1e2d3c-4b5a-4c7d-8e9f-0a1b2c3d4e5f"
+                },
+                "type": "partition",
+                "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/99/partition/102",
+                "uuid": "7a0b8c35-2cd1-4a4c-86a2-6db0f1b2c7a1",
+                "used_for": "Active raid-1 device for md0",
+                "size": 8589934592,
+                "tags": []
+            }
+        ],
+        "spare_devices": [
+            {
+                "bootable": false,
+                "id": 103,
+                "path": "/dev/disk/by-dname/sdd-part1",
+                "filesystem": {
+                    "fstype": "raid-spare",
+                    "mount_point": null,
+                    "label": null,
+                    "mount_options": null,
+                    "uuid": "9c8b7a6f-5e4d-4c3b-2a1f-0e9d8c7b6a5f"
+                },
+                "type": "partition",
+                "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/100/partition/103",
+                "uuid": "1d2c3b4a-5f6e-4d7c-8b9a-0f1e2d3c4b5a",
+                "used_for": "Spare raid-1 device for md0",
+                "size": 8589934592,
+                "tags": []
+            }
+        ],
+        "virtual_device": {
+            "path": "/dev/disk/by-dname/md0",
+            "name": "md0",
+            "used_for": "Unused",
+            "partitions": [],
+            "filesystem": null,
+            "id_path": null,
+            "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/64/",
+            "id": 64,
+            "type": "virtual",
+            "block_size": 4096,
+            "used_size": 0,
+            "available_size": 8581545984,
+            "uuid": "3b9d4d8e-8f5c-4a1c-a0d4-1a3e8f0b4c2d",
+            "size": 8581545984,
+            "model": null,
+            "tags": ["raid-1"]
+        },
+        "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/raid/63/"
+    }
+`
+)
@@ -0,0 +1,265 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+// ScriptType is the kind of scripts that MAAS runs on a machine.
+type ScriptType string
+
+const (
+	ScriptTypeCommissioning ScriptType = "commissioning"
+	ScriptTypeTesting       ScriptType = "testing"
+	ScriptTypeInstallation  ScriptType = "installation"
+)
+
+// scriptTypes maps the numeric types of the script result sets to the script
+// types, for the result sets that don't have a type name.
+var scriptTypes = map[int]ScriptType{
+	0: ScriptTypeCommissioning,
+	1: ScriptTypeInstallation,
+	2: ScriptTypeTesting,
+}
+
+// HardwareType is the kind of hardware that a script tests.
+type HardwareType string
+
+const (
+	HardwareTypeNode    HardwareType = "node"
+	HardwareTypeCPU     HardwareType = "cpu"
+	HardwareTypeMemory  HardwareType = "memory"
+	HardwareTypeStorage HardwareType = "storage"
+	HardwareTypeNetwork HardwareType = "network"
+)
+
+type scriptResult struct {
+	id         int
+	name       string
+	scriptType ScriptType
+	status     string
+	exitStatus int
+	started    string
+	ended      string
+	runtime    string
+
+	output []byte
+	stdout []byte
+	stderr []byte
+}
+
+// ID implements ScriptResult.
+func (r *scriptResult) ID() int {
+	return r.id
+}
+
+// Name implements ScriptResult.
+func (r *scriptResult) Name() string {
+	return r.name
+}
+
+// Type implements ScriptResult.
+func (r *scriptResult) Type() ScriptType {
+	return r.scriptType
+}
+
+// Status implements ScriptResult.
+func (r *scriptResult) Status() string {
+	return r.status
+}
+
+// ExitStatus implements ScriptResult.
+func (r *scriptResult) ExitStatus() int {
+	return r.exitStatus
+}
+
+// Started implements ScriptResult.
+func (r *scriptResult) Started() string {
+	return r.started
+}
+
+// Ended implements ScriptResult.
+func (r *scriptResult) Ended() string {
+	return r.ended
+}
+
+// Runtime implements ScriptResult.
+func (r *scriptResult) Runtime() string {
+	return r.runtime
+}
+
+// Output implements ScriptResult.
+func (r *scriptResult) Output() []byte {
+	return r.output
+}
+
+// Stdout implements ScriptResult.
+func (r *scriptResult) Stdout() []byte {
+	return r.stdout
+}
+
+// Stderr implements ScriptResult.
+func (r *scriptResult) Stderr() []byte {
+	return r.stderr
+}
+
+// readScriptResults reads the script result sets of a node, and returns the
+// results of all the sets.
+func readScriptResults(controllerVersion version.Number, source interface{}) ([]*scriptResult, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "script result set base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getScriptResultSetDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readScriptResultSetList(valid, readFunc)
+}
+
+func getScriptResultSetDeserializationFunc(controllerVersion version.Number) (scriptResultSetDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range scriptResultSetDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no script result set read func for version %s", controllerVersion)
+	}
+	return scriptResultSetDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readScriptResultSetList expects the values of the sourceList to be string
+// maps.
+func readScriptResultSetList(sourceList []interface{}, readFunc scriptResultSetDeserializationFunc) ([]*scriptResult, error) {
+	var result []*scriptResult
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for script result set %d, %T", i, value)
+		}
+		results, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "script result set %d", i)
+		}
+		result = append(result, results...)
+	}
+	return result, nil
+}
+
+type scriptResultSetDeserializationFunc func(map[string]interface{}) ([]*scriptResult, error)
+
+var scriptResultSetDeserializationFuncs = map[version.Number]scriptResultSetDeserializationFunc{
+	twoDotOh: scriptResultSet_2_0,
+}
+
+func scriptResultSet_2_0(source map[string]interface{}) ([]*scriptResult, error) {
+	fields := schema.Fields{
+		"id":        schema.ForceInt(),
+		"type":      schema.ForceInt(),
+		"type_name": schema.String(),
+		"results":   schema.List(schema.StringMap(schema.Any())),
+	}
+	defaults := schema.Defaults{
+		"type_name": "",
+		"results":   []interface{}{},
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "script result set 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	scriptType := ScriptType(strings.ToLower(valid["type_name"].(string)))
+	if scriptType == "" {
+		scriptType = scriptTypes[valid["type"].(int)]
+	}
+	results := valid["results"].([]interface{})
+	result := make([]*scriptResult, 0, len(results))
+	for i, value := range results {
+		scriptResult, err := scriptResult_2_0(value.(map[string]interface{}))
+		if err != nil {
+			return nil, errors.Annotatef(err, "script result %d", i)
+		}
+		scriptResult.scriptType = scriptType
+		result = append(result, scriptResult)
+	}
+	return result, nil
+}
+
+func scriptResult_2_0(source map[string]interface{}) (*scriptResult, error) {
+	optionalString := schema.OneOf(schema.Nil(""), schema.String())
+	fields := schema.Fields{
+		"id":          schema.ForceInt(),
+		"name":        schema.String(),
+		"status_name": schema.String(),
+		"exit_status": schema.OneOf(schema.Nil(""), schema.ForceInt()),
+		"started":     optionalString,
+		"ended":       optionalString,
+		"runtime":     optionalString,
+		// The output is only included when it is asked for, and it is
+		// base64 encoded.
+		"output": schema.String(),
+		"stdout": schema.String(),
+		"stderr": schema.String(),
+	}
+	defaults := schema.Defaults{
+		"exit_status": nil,
+		"started":     "",
+		"ended":       "",
+		"runtime":     "",
+		"output":      "",
+		"stdout":      "",
+		"stderr":      "",
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "script result 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	outputs := make(map[string][]byte)
+	for _, name := range []string{"output", "stdout", "stderr"} {
+		decoded, err := base64.StdEncoding.DecodeString(valid[name].(string))
+		if err != nil {
+			return nil, WrapWithDeserializationError(err, "script result %s", name)
+		}
+		outputs[name] = decoded
+	}
+
+	exitStatus, _ := valid["exit_status"].(int)
+	started, _ := valid["started"].(string)
+	ended, _ := valid["ended"].(string)
+	runtime, _ := valid["runtime"].(string)
+	result := &scriptResult{
+		id:         valid["id"].(int),
+		name:       valid["name"].(string),
+		status:     valid["status_name"].(string),
+		exitStatus: exitStatus,
+		started:    started,
+		ended:      ended,
+		runtime:    runtime,
+
+		output: outputs["output"],
+		stdout: outputs["stdout"],
+		stderr: outputs["stderr"],
+	}
+	return result, nil
+}
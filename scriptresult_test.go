@@ -0,0 +1,196 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type scriptResultSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&scriptResultSuite{})
+
+func (*scriptResultSuite) TestReadScriptResultsBadSchema(c *gc.C) {
+	_, err := readScriptResults(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `script result set base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*scriptResultSuite) TestReadScriptResults(c *gc.C) {
+	results, err := readScriptResults(twoDotOh, parseJSON(c, scriptResultsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+
+	result := results[0]
+	c.Check(result.ID(), gc.Equals, 11)
+	c.Check(result.Name(), gc.Equals, "00-maas-01-cpuinfo")
+	c.Check(result.Type(), gc.Equals, ScriptTypeCommissioning)
+	c.Check(result.Status(), gc.Equals, "Passed")
+	c.Check(result.ExitStatus(), gc.Equals, 0)
+	c.Check(result.Started(), gc.Equals, "Tue, 11 Oct. 2022 09:58:12")
+	c.Check(result.Ended(), gc.Equals, "Tue, 11 Oct. 2022 09:58:13")
+	c.Check(result.Runtime(), gc.Equals, "0:00:01")
+	c.Check(string(result.Output()), gc.Equals, "Hardware info collected\n")
+	c.Check(string(result.Stdout()), gc.Equals, "Hardware info collected\n")
+	c.Check(result.Stderr(), gc.HasLen, 0)
+
+	result = results[1]
+	c.Check(result.ID(), gc.Equals, 23)
+	c.Check(result.Name(), gc.Equals, "smartctl-validate")
+	c.Check(result.Type(), gc.Equals, ScriptTypeTesting)
+	c.Check(result.Status(), gc.Equals, "Failed")
+	c.Check(result.ExitStatus(), gc.Equals, 1)
+	c.Check(string(result.Output()), gc.Equals, "INFO: Verifying SMART support\nSMART support is unavailable\n")
+	c.Check(string(result.Stdout()), gc.Equals, "INFO: Verifying SMART support\n")
+	c.Check(string(result.Stderr()), gc.Equals, "SMART support is unavailable\n")
+
+	result = results[2]
+	c.Check(result.ID(), gc.Equals, 24)
+	c.Check(result.Status(), gc.Equals, "Pending")
+	c.Check(result.ExitStatus(), gc.Equals, 0)
+	c.Check(result.Started(), gc.Equals, "")
+	c.Check(result.Runtime(), gc.Equals, "")
+	c.Check(result.Output(), gc.HasLen, 0)
+}
+
+func (*scriptResultSuite) TestReadScriptResultsTypes(c *gc.C) {
+	results, err := readScriptResults(twoDotOh, parseJSON(c, `[
+        {"id": 1, "type": 0, "results": [{"id": 1, "name": "lshw", "status_name": "Passed"}]},
+        {"id": 2, "type": 1, "results": [{"id": 2, "name": "/tmp/install.log", "status_name": "Passed"}]},
+        {"id": 3, "type": 2, "results": [{"id": 3, "name": "smartctl-validate", "status_name": "Passed"}]},
+        {"id": 4, "type": 1, "type_name": "Installation", "results": [{"id": 4, "name": "/tmp/install.log", "status_name": "Passed"}]}
+    ]`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 4)
+	c.Check(results[0].Type(), gc.Equals, ScriptTypeCommissioning)
+	c.Check(results[1].Type(), gc.Equals, ScriptTypeInstallation)
+	c.Check(results[2].Type(), gc.Equals, ScriptTypeTesting)
+	c.Check(results[3].Type(), gc.Equals, ScriptTypeInstallation)
+}
+
+func (*scriptResultSuite) TestReadScriptResultsBadOutput(c *gc.C) {
+	_, err := readScriptResults(twoDotOh, parseJSON(c, `[{
+        "id": 1,
+        "type": 0,
+        "results": [{"id": 2, "name": "lshw", "status_name": "Passed", "stdout": "not base64!"}]
+    }]`))
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err, gc.ErrorMatches, `script result set 0: script result 0: script result stdout: .*`)
+}
+
+func (*scriptResultSuite) TestLowVersion(c *gc.C) {
+	_, err := readScriptResults(version.MustParse("1.9.0"), parseJSON(c, scriptResultsResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*scriptResultSuite) TestHighVersion(c *gc.C) {
+	results, err := readScriptResults(version.MustParse("2.1.9"), parseJSON(c, scriptResultsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 3)
+}
+
+const scriptResultsResponse = `
+[
+    {
+        "id": 3,
+        "system_id": "4y3ha3",
+        "type": 0,
+        "type_name": "Commissioning",
+        "last_ping": "Tue, 11 Oct. 2022 09:58:40",
+        "status": 2,
+        "status_name": "Passed",
+        "started": "Tue, 11 Oct. 2022 09:58:12",
+        "ended": "Tue, 11 Oct. 2022 09:58:40",
+        "runtime": "0:00:28",
+        "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/results/3/",
+        "results": [
+            {
+                "id": 11,
+                "created": "Tue, 11 Oct. 2022 09:57:01",
+                "updated": "Tue, 11 Oct. 2022 09:58:13",
+                "name": "00-maas-01-cpuinfo",
+                "status": 2,
+                "status_name": "Passed",
+                "exit_status": 0,
+                "started": "Tue, 11 Oct. 2022 09:58:12",
+                "ended": "Tue, 11 Oct. 2022 09:58:13",
+                "runtime": "0:00:01",
+                "estimated_runtime": "0:00:01",
+                "parameters": {},
+                "script_id": null,
+                "script_revision_id": null,
+                "suppressed": false,
+                "output": "SGFyZHdhcmUgaW5mbyBjb2xsZWN0ZWQK",
+                "stdout": "SGFyZHdhcmUgaW5mbyBjb2xsZWN0ZWQK",
+                "stderr": "",
+                "result": ""
+            }
+        ]
+    },
+    {
+        "id": 4,
+        "system_id": "4y3ha3",
+        "type": 2,
+        "type_name": "Testing",
+        "last_ping": "Tue, 11 Oct. 2022 10:01:22",
+        "status": 3,
+        "status_name": "Failed",
+        "started": "Tue, 11 Oct. 2022 10:01:02",
+        "ended": null,
+        "runtime": "",
+        "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/results/4/",
+        "results": [
+            {
+                "id": 23,
+                "created": "Tue, 11 Oct. 2022 09:57:01",
+                "updated": "Tue, 11 Oct. 2022 10:01:22",
+                "name": "smartctl-validate",
+                "status": 3,
+                "status_name": "Failed",
+                "exit_status": 1,
+                "started": "Tue, 11 Oct. 2022 10:01:02",
+                "ended": "Tue, 11 Oct. 2022 10:01:22",
+                "runtime": "0:00:20",
+                "estimated_runtime": "0:00:20",
+                "parameters": {
+                    "storage": {"type": "storage", "value": {"name": "sda", "id_path": "/dev/disk/by-id/wwn-0x55cd2e404c4f4ea8"}}
+                },
+                "script_id": 5,
+                "script_revision_id": 5,
+                "suppressed": false,
+                "output": "SU5GTzogVmVyaWZ5aW5nIFNNQVJUIHN1cHBvcnQKU01BUlQgc3VwcG9ydCBpcyB1bmF2YWlsYWJsZQo=",
+                "stdout": "SU5GTzogVmVyaWZ5aW5nIFNNQVJUIHN1cHBvcnQK",
+                "stderr": "U01BUlQgc3VwcG9ydCBpcyB1bmF2YWlsYWJsZQo=",
+                "result": ""
+            },
+            {
+                "id": 24,
+                "created": "Tue, 11 Oct. 2022 09:57:01",
+                "updated": "Tue, 11 Oct. 2022 09:57:01",
+                "name": "memtester",
+                "status": 0,
+                "status_name": "Pending",
+                "exit_status": null,
+                "started": null,
+                "ended": null,
+                "runtime": "",
+                "estimated_runtime": "Unknown",
+                "parameters": {},
+                "script_id": 7,
+                "script_revision_id": 7,
+                "suppressed": false,
+                "output": "",
+                "stdout": "",
+                "stderr": "",
+                "result": ""
+            }
+        ]
+    }
+]
+`
@@ -10,17 +10,25 @@ import (
 )
 
 type space struct {
-	// Add the controller in when we need to do things with the space.
-	// controller Controller
+	controller *controller
 
 	resourceURI string
 
-	id   int
-	name string
+	id          int
+	name        string
+	description string
 
 	subnets []*subnet
 }
 
+func (s *space) updateFrom(other *space) {
+	s.resourceURI = other.resourceURI
+	s.id = other.id
+	s.name = other.name
+	s.description = other.description
+	s.subnets = other.subnets
+}
+
 // Id implements Space.
 func (s *space) ID() int {
 	return s.id
@@ -31,15 +39,77 @@ func (s *space) Name() string {
 	return s.name
 }
 
+// Description implements Space.
+func (s *space) Description() string {
+	return s.description
+}
+
 // Subnets implements Space.
 func (s *space) Subnets() []Subnet {
 	var result []Subnet
 	for _, subnet := range s.subnets {
+		subnet.controller = s.controller
 		result = append(result, subnet)
 	}
 	return result
 }
 
+// UpdateSpaceArgs is an argument struct for passing parameters to the
+// Space.Update method. Only the values that are specified are changed.
+type UpdateSpaceArgs struct {
+	Name string
+	// Description replaces the existing description when not nil. An empty
+	// string removes the description.
+	Description *string
+}
+
+// Update implements Space.
+func (s *space) Update(args UpdateSpaceArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	if args.Description != nil {
+		params.Values.Add("description", *args.Description)
+	}
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := s.controller.put(s.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readSpace(s.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.updateFrom(response)
+	return nil
+}
+
+// Delete implements Space.
+func (s *space) Delete() error {
+	err := s.controller.delete(s.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+func readSpace(controllerVersion version.Number, source interface{}) (*space, error) {
+	readFunc, err := getSpaceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "space base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readSpaces(controllerVersion version.Number, source interface{}) ([]*space, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -48,6 +118,14 @@ func readSpaces(controllerVersion version.Number, source interface{}) ([]*space,
 	}
 	valid := coerced.([]interface{})
 
+	readFunc, err := getSpaceDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readSpaceList(valid, readFunc)
+}
+
+func getSpaceDeserializationFunc(controllerVersion version.Number) (spaceDeserializationFunc, error) {
 	var deserialisationVersion version.Number
 	for v := range spaceDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
@@ -57,8 +135,7 @@ func readSpaces(controllerVersion version.Number, source interface{}) ([]*space,
 	if deserialisationVersion == version.Zero {
 		return nil, errors.Errorf("no space read func for version %s", controllerVersion)
 	}
-	readFunc := spaceDeserializationFuncs[deserialisationVersion]
-	return readSpaceList(valid, readFunc)
+	return spaceDeserializationFuncs[deserialisationVersion], nil
 }
 
 // readSpaceList expects the values of the sourceList to be string maps.
@@ -89,9 +166,13 @@ func space_2_0(source map[string]interface{}) (*space, error) {
 		"resource_uri": schema.String(),
 		"id":           schema.ForceInt(),
 		"name":         schema.String(),
+		"description":  schema.OneOf(schema.Nil(""), schema.String()),
 		"subnets":      schema.List(schema.StringMap(schema.Any())),
 	}
-	checker := schema.FieldMap(fields, nil) // no defaults
+	defaults := schema.Defaults{
+		"description": "",
+	}
+	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(source, nil)
 	if err != nil {
 		return nil, errors.Annotatef(err, "space 2.0 schema check failed")
@@ -105,10 +186,12 @@ func space_2_0(source map[string]interface{}) (*space, error) {
 		return nil, errors.Trace(err)
 	}
 
+	description, _ := valid["description"].(string)
 	result := &space{
 		resourceURI: valid["resource_uri"].(string),
 		id:          valid["id"].(int),
 		name:        valid["name"].(string),
+		description: description,
 		subnets:     subnets,
 	}
 	return result, nil
@@ -4,12 +4,18 @@
 package gomaasapi
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 )
 
-type spaceSuite struct{}
+type spaceSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&spaceSuite{})
 
@@ -26,6 +32,7 @@ func (*spaceSuite) TestReadSpaces(c *gc.C) {
 	space := spaces[0]
 	c.Assert(space.ID(), gc.Equals, 0)
 	c.Assert(space.Name(), gc.Equals, "space-0")
+	c.Assert(space.Description(), gc.Equals, "")
 	subnets := space.Subnets()
 	c.Assert(subnets, gc.HasLen, 2)
 	c.Assert(subnets[0].ID(), gc.Equals, 34)
@@ -42,6 +49,123 @@ func (*spaceSuite) TestHighVersion(c *gc.C) {
 	c.Assert(spaces, gc.HasLen, 1)
 }
 
+func (s *spaceSuite) getServerAndSpace(c *gc.C) (*SimpleTestServer, *space) {
+	server, ctrl := createTestServerController(c, s)
+	space, err := readSpace(twoDotOh, parseJSON(c, spaceResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	space.controller = ctrl.(*controller)
+	return server, space
+}
+
+func (s *spaceSuite) TestReadSpace(c *gc.C) {
+	_, space := s.getServerAndSpace(c)
+	c.Check(space.ID(), gc.Equals, 2)
+	c.Check(space.Name(), gc.Equals, "storage")
+	c.Check(space.Description(), gc.Equals, "storage network")
+	subnets := space.Subnets()
+	c.Assert(subnets, gc.HasLen, 1)
+	c.Check(subnets[0].(*subnet).controller, gc.Equals, space.controller)
+}
+
+func (s *spaceSuite) TestUpdate(c *gc.C) {
+	server, space := s.getServerAndSpace(c)
+	response := updateJSONMap(c, spaceResponse, map[string]interface{}{
+		"name":        "backup",
+		"description": "backup network",
+	})
+	server.AddPutResponse(space.resourceURI, http.StatusOK, response)
+	description := "backup network"
+	err := space.Update(UpdateSpaceArgs{
+		Name:        "backup",
+		Description: &description,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(space.Name(), gc.Equals, "backup")
+	c.Check(space.Description(), gc.Equals, "backup network")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("name"), gc.Equals, "backup")
+	c.Check(form.Get("description"), gc.Equals, "backup network")
+}
+
+func (s *spaceSuite) TestUpdateClearDescription(c *gc.C) {
+	server, space := s.getServerAndSpace(c)
+	response := updateJSONMap(c, spaceResponse, map[string]interface{}{
+		"description": "",
+	})
+	server.AddPutResponse(space.resourceURI, http.StatusOK, response)
+	description := ""
+	err := space.Update(UpdateSpaceArgs{Description: &description})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(space.Description(), gc.Equals, "")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{"description": {""}})
+}
+
+func (s *spaceSuite) TestUpdateNothing(c *gc.C) {
+	server, space := s.getServerAndSpace(c)
+	count := server.RequestCount()
+	err := space.Update(UpdateSpaceArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *spaceSuite) TestUpdateBadRequest(c *gc.C) {
+	server, space := s.getServerAndSpace(c)
+	server.AddPutResponse(space.resourceURI, http.StatusBadRequest, "name in use")
+	err := space.Update(UpdateSpaceArgs{Name: "space-0"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "name in use")
+}
+
+func (s *spaceSuite) TestDelete(c *gc.C) {
+	server, space := s.getServerAndSpace(c)
+	server.AddDeleteResponse(space.resourceURI, http.StatusNoContent, "")
+	err := space.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *spaceSuite) TestDeleteMissing(c *gc.C) {
+	_, space := s.getServerAndSpace(c)
+	err := space.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+var spaceResponse = `
+{
+    "subnets": [
+        {
+            "gateway_ip": null,
+            "name": "10.20.0.0/24",
+            "vlan": {
+                "fabric": "fabric-1",
+                "resource_uri": "/MAAS/api/2.0/vlans/5010/",
+                "name": "storage",
+                "secondary_rack": null,
+                "primary_rack": null,
+                "vid": 10,
+                "dhcp_on": false,
+                "id": 5010,
+                "mtu": 9000,
+                "space": "storage"
+            },
+            "space": "storage",
+            "id": 40,
+            "resource_uri": "/MAAS/api/2.0/subnets/40/",
+            "dns_servers": [],
+            "cidr": "10.20.0.0/24",
+            "rdns_mode": 2
+        }
+    ],
+    "id": 2,
+    "name": "storage",
+    "description": "storage network",
+    "resource_uri": "/MAAS/api/2.0/spaces/2/"
+}
+`
+
 var spacesResponse = `
 [
     {
@@ -0,0 +1,132 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type sshKey struct {
+	resourceURI string
+
+	id        int
+	key       string
+	keySource string
+}
+
+// ID implements SSHKey.
+func (k *sshKey) ID() int {
+	return k.id
+}
+
+// Key implements SSHKey.
+func (k *sshKey) Key() string {
+	return k.key
+}
+
+// KeySource implements SSHKey.
+func (k *sshKey) KeySource() string {
+	return k.keySource
+}
+
+func readSSHKey(controllerVersion version.Number, source interface{}) (*sshKey, error) {
+	readFunc, err := getSSHKeyDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ssh key base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readSSHKeys(controllerVersion version.Number, source interface{}) ([]*sshKey, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ssh key base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getSSHKeyDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readSSHKeyList(valid, readFunc)
+}
+
+func getSSHKeyDeserializationFunc(controllerVersion version.Number) (sshKeyDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range sshKeyDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no ssh key read func for version %s", controllerVersion)
+	}
+	return sshKeyDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readSSHKeyList expects the values of the sourceList to be string maps.
+func readSSHKeyList(sourceList []interface{}, readFunc sshKeyDeserializationFunc) ([]*sshKey, error) {
+	result := make([]*sshKey, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for ssh key %d, %T", i, value)
+		}
+		key, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "ssh key %d", i)
+		}
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+type sshKeyDeserializationFunc func(map[string]interface{}) (*sshKey, error)
+
+var sshKeyDeserializationFuncs = map[version.Number]sshKeyDeserializationFunc{
+	twoDotOh: sshKey_2_0,
+}
+
+func sshKey_2_0(source map[string]interface{}) (*sshKey, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":  schema.ForceInt(),
+		"key": schema.String(),
+		// The key source is only set for imported keys, and only MAAS 2.2
+		// and later import keys.
+		"keysource": schema.OneOf(schema.Nil(""), schema.String()),
+	}
+	defaults := schema.Defaults{
+		"keysource": "",
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ssh key 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	keySource, _ := valid["keysource"].(string)
+	result := &sshKey{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:        valid["id"].(int),
+		key:       valid["key"].(string),
+		keySource: keySource,
+	}
+	return result, nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type sshKeySuite struct{}
+
+var _ = gc.Suite(&sshKeySuite{})
+
+func (*sshKeySuite) TestReadSSHKeysBadSchema(c *gc.C) {
+	_, err := readSSHKeys(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `ssh key base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*sshKeySuite) TestReadSSHKeys(c *gc.C) {
+	keys, err := readSSHKeys(twoDotOh, parseJSON(c, sshKeysResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 2)
+
+	key := keys[0]
+	c.Check(key.ID(), gc.Equals, 1)
+	c.Check(key.Key(), gc.Equals, "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBEX admin@laptop")
+	c.Check(key.KeySource(), gc.Equals, "")
+
+	key = keys[1]
+	c.Check(key.ID(), gc.Equals, 2)
+	c.Check(key.KeySource(), gc.Equals, "lp:jane")
+}
+
+func (*sshKeySuite) TestLowVersion(c *gc.C) {
+	_, err := readSSHKeys(version.MustParse("1.9.0"), parseJSON(c, sshKeysResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*sshKeySuite) TestHighVersion(c *gc.C) {
+	keys, err := readSSHKeys(version.MustParse("2.1.9"), parseJSON(c, sshKeysResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 2)
+}
+
+const (
+	sshKeyResponse = `
+{
+    "id": 1,
+    "key": "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIBEX admin@laptop",
+    "keysource": null,
+    "resource_uri": "/MAAS/api/2.0/account/prefs/sshkeys/1/"
+}
+`
+	sshKeysResponse = `
+[` + sshKeyResponse + `,
+    {
+        "id": 2,
+        "key": "ssh-rsa AAAAB3NzaC1yc2EAAAADAQABAAABAQC7 jane@desktop",
+        "keysource": "lp:jane",
+        "resource_uri": "/MAAS/api/2.0/account/prefs/sshkeys/2/"
+    }
+]
+`
+)
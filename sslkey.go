@@ -0,0 +1,118 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type sslKey struct {
+	resourceURI string
+
+	id  int
+	key string
+}
+
+// ID implements SSLKey.
+func (k *sslKey) ID() int {
+	return k.id
+}
+
+// Key implements SSLKey.
+func (k *sslKey) Key() string {
+	return k.key
+}
+
+func readSSLKey(controllerVersion version.Number, source interface{}) (*sslKey, error) {
+	readFunc, err := getSSLKeyDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ssl key base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readSSLKeys(controllerVersion version.Number, source interface{}) ([]*sslKey, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ssl key base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getSSLKeyDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readSSLKeyList(valid, readFunc)
+}
+
+func getSSLKeyDeserializationFunc(controllerVersion version.Number) (sslKeyDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range sslKeyDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no ssl key read func for version %s", controllerVersion)
+	}
+	return sslKeyDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readSSLKeyList expects the values of the sourceList to be string maps.
+func readSSLKeyList(sourceList []interface{}, readFunc sslKeyDeserializationFunc) ([]*sslKey, error) {
+	result := make([]*sslKey, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for ssl key %d, %T", i, value)
+		}
+		key, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "ssl key %d", i)
+		}
+		result = append(result, key)
+	}
+	return result, nil
+}
+
+type sslKeyDeserializationFunc func(map[string]interface{}) (*sslKey, error)
+
+var sslKeyDeserializationFuncs = map[version.Number]sslKeyDeserializationFunc{
+	twoDotOh: sslKey_2_0,
+}
+
+func sslKey_2_0(source map[string]interface{}) (*sslKey, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":  schema.ForceInt(),
+		"key": schema.String(),
+	}
+	checker := schema.FieldMap(fields, nil)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "ssl key 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	result := &sslKey{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:  valid["id"].(int),
+		key: valid["key"].(string),
+	}
+	return result, nil
+}
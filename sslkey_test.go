@@ -0,0 +1,50 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type sslKeySuite struct{}
+
+var _ = gc.Suite(&sslKeySuite{})
+
+func (*sslKeySuite) TestReadSSLKeysBadSchema(c *gc.C) {
+	_, err := readSSLKeys(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `ssl key base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*sslKeySuite) TestReadSSLKeys(c *gc.C) {
+	keys, err := readSSLKeys(twoDotOh, parseJSON(c, sslKeysResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 1)
+	c.Check(keys[0].ID(), gc.Equals, 3)
+	c.Check(keys[0].Key(), gc.Equals, "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n")
+}
+
+func (*sslKeySuite) TestLowVersion(c *gc.C) {
+	_, err := readSSLKeys(version.MustParse("1.9.0"), parseJSON(c, sslKeysResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*sslKeySuite) TestHighVersion(c *gc.C) {
+	keys, err := readSSLKeys(version.MustParse("2.1.9"), parseJSON(c, sslKeysResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 1)
+}
+
+const (
+	sslKeyResponse = `
+{
+    "id": 3,
+    "key": "-----BEGIN CERTIFICATE-----\nMIIB\n-----END CERTIFICATE-----\n",
+    "resource_uri": "/MAAS/api/2.0/account/prefs/sslkeys/3/"
+}
+`
+	sslKeysResponse = `[` + sslKeyResponse + `]`
+)
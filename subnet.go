@@ -4,14 +4,31 @@
 package gomaasapi
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/version"
 )
 
+// RDNSMode controls how MAAS generates reverse DNS for a subnet.
+type RDNSMode int
+
+const (
+	// RDNSDisabled does not generate reverse DNS for the subnet.
+	RDNSDisabled RDNSMode = 0
+
+	// RDNSEnabled generates reverse DNS for the subnet only.
+	RDNSEnabled RDNSMode = 1
+
+	// RDNSRFC2317 generates reverse DNS for the subnet, and also the RFC2317
+	// glue if the subnet is smaller than the classful reverse zone.
+	RDNSRFC2317 RDNSMode = 2
+)
+
 type subnet struct {
-	// Add the controller in when we need to do things with the subnet.
-	// controller Controller
+	controller *controller
 
 	resourceURI string
 
@@ -24,6 +41,24 @@ type subnet struct {
 	cidr    string
 
 	dnsServers []string
+
+	allowProxy bool
+	managed    bool
+	rdnsMode   RDNSMode
+}
+
+func (s *subnet) updateFrom(other *subnet) {
+	s.resourceURI = other.resourceURI
+	s.id = other.id
+	s.name = other.name
+	s.space = other.space
+	s.vlan = other.vlan
+	s.gateway = other.gateway
+	s.cidr = other.cidr
+	s.dnsServers = other.dnsServers
+	s.allowProxy = other.allowProxy
+	s.managed = other.managed
+	s.rdnsMode = other.rdnsMode
 }
 
 // ID implements Subnet.
@@ -46,6 +81,7 @@ func (s *subnet) VLAN() VLAN {
 	if s.vlan == nil {
 		return nil
 	}
+	s.vlan.controller = s.controller
 	return s.vlan
 }
 
@@ -64,6 +100,164 @@ func (s *subnet) DNSServers() []string {
 	return s.dnsServers
 }
 
+// AllowProxy implements Subnet.
+func (s *subnet) AllowProxy() bool {
+	return s.allowProxy
+}
+
+// Managed implements Subnet.
+func (s *subnet) Managed() bool {
+	return s.managed
+}
+
+// RDNSMode implements Subnet.
+func (s *subnet) RDNSMode() RDNSMode {
+	return s.rdnsMode
+}
+
+// UpdateSubnetArgs is an argument struct for passing parameters to the
+// Subnet.Update method. Only the values that are specified are changed.
+type UpdateSubnetArgs struct {
+	CIDR      string
+	Name      string
+	VLAN      VLAN
+	Space     string
+	GatewayIP string
+	// DNSServers replace the existing servers when not nil. An empty slice
+	// removes all the servers.
+	DNSServers []string
+
+	AllowProxy *bool
+	Managed    *bool
+	RDNSMode   *RDNSMode
+}
+
+// Update implements Subnet.
+func (s *subnet) Update(args UpdateSubnetArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("cidr", args.CIDR)
+	params.MaybeAdd("name", args.Name)
+	if args.VLAN != nil {
+		params.Values.Add("vlan", fmt.Sprint(args.VLAN.ID()))
+	}
+	params.MaybeAdd("space", args.Space)
+	params.MaybeAdd("gateway_ip", args.GatewayIP)
+	if args.DNSServers != nil {
+		params.Values.Add("dns_servers", strings.Join(args.DNSServers, ","))
+	}
+	addSubnetOptions(params, args.AllowProxy, args.Managed, args.RDNSMode)
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := s.controller.put(s.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readSubnet(s.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	s.updateFrom(response)
+	return nil
+}
+
+// Delete implements Subnet.
+func (s *subnet) Delete() error {
+	err := s.controller.delete(s.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// SubnetStats holds statistics about the addresses in a subnet.
+type SubnetStats struct {
+	NumAvailable     uint    `json:"num_available"`
+	LargestAvailable uint    `json:"largest_available"`
+	NumUnavailable   uint    `json:"num_unavailable"`
+	TotalAddresses   uint    `json:"total_addresses"`
+	Usage            float32 `json:"usage"`
+	UsageString      string  `json:"usage_string"`
+	// Ranges are the unreserved ranges of the subnet, and are only set if
+	// they were asked for.
+	Ranges []AddressRange `json:"ranges"`
+}
+
+// AddressRange is a contiguous range of addresses in a subnet.
+type AddressRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+	// Purpose describes what the addresses of a reserved range are used
+	// for, and is empty for unreserved ranges.
+	Purpose      []string `json:"purpose,omitempty"`
+	NumAddresses uint     `json:"num_addresses"`
+}
+
+// Statistics implements Subnet.
+func (s *subnet) Statistics(includeRanges bool) (SubnetStats, error) {
+	params := NewURLParams()
+	params.MaybeAddBool("include_ranges", includeRanges)
+	source, err := s.controller._get(s.resourceURI, "statistics", params.Values)
+	if err != nil {
+		return SubnetStats{}, translateServerError(err)
+	}
+	stats, err := readSubnetStats(source)
+	if err != nil {
+		return SubnetStats{}, errors.Trace(err)
+	}
+	return stats, nil
+}
+
+// ReservedIPRanges implements Subnet.
+func (s *subnet) ReservedIPRanges() ([]AddressRange, error) {
+	return s.addressRanges("reserved_ip_ranges")
+}
+
+// UnreservedIPRanges implements Subnet.
+func (s *subnet) UnreservedIPRanges() ([]AddressRange, error) {
+	return s.addressRanges("unreserved_ip_ranges")
+}
+
+func (s *subnet) addressRanges(op string) ([]AddressRange, error) {
+	source, err := s.controller.getOp(s.resourceURI, op)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	ranges, err := readAddressRanges(source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return ranges, nil
+}
+
+func addSubnetOptions(params *URLParams, allowProxy, managed *bool, rdnsMode *RDNSMode) {
+	if allowProxy != nil {
+		params.Values.Add("allow_proxy", fmt.Sprint(*allowProxy))
+	}
+	if managed != nil {
+		params.Values.Add("managed", fmt.Sprint(*managed))
+	}
+	if rdnsMode != nil {
+		params.Values.Add("rdns_mode", fmt.Sprint(int(*rdnsMode)))
+	}
+}
+
+func readSubnet(controllerVersion version.Number, source interface{}) (*subnet, error) {
+	readFunc, err := getSubnetDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, errors.Annotatef(err, "subnet base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readSubnets(controllerVersion version.Number, source interface{}) ([]*subnet, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -72,6 +266,14 @@ func readSubnets(controllerVersion version.Number, source interface{}) ([]*subne
 	}
 	valid := coerced.([]interface{})
 
+	readFunc, err := getSubnetDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readSubnetList(valid, readFunc)
+}
+
+func getSubnetDeserializationFunc(controllerVersion version.Number) (subnetDeserializationFunc, error) {
 	var deserialisationVersion version.Number
 	for v := range subnetDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
@@ -81,8 +283,7 @@ func readSubnets(controllerVersion version.Number, source interface{}) ([]*subne
 	if deserialisationVersion == version.Zero {
 		return nil, errors.Errorf("no subnet read func for version %s", controllerVersion)
 	}
-	readFunc := subnetDeserializationFuncs[deserialisationVersion]
-	return readSubnetList(valid, readFunc)
+	return subnetDeserializationFuncs[deserialisationVersion], nil
 }
 
 // readSubnetList expects the values of the sourceList to be string maps.
@@ -118,8 +319,18 @@ func subnet_2_0(source map[string]interface{}) (*subnet, error) {
 		"cidr":         schema.String(),
 		"vlan":         schema.StringMap(schema.Any()),
 		"dns_servers":  schema.OneOf(schema.Nil(""), schema.List(schema.String())),
+		"allow_proxy":  schema.Bool(),
+		"managed":      schema.Bool(),
+		"rdns_mode":    schema.ForceInt(),
 	}
-	checker := schema.FieldMap(fields, nil) // no defaults
+	// Older MAAS servers, and the subnets embedded in other responses, don't
+	// include the options, so default them to what MAAS uses.
+	defaults := schema.Defaults{
+		"allow_proxy": true,
+		"managed":     true,
+		"rdns_mode":   int(RDNSRFC2317),
+	}
+	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(source, nil)
 	if err != nil {
 		return nil, errors.Annotatef(err, "subnet 2.0 schema check failed")
@@ -147,6 +358,83 @@ func subnet_2_0(source map[string]interface{}) (*subnet, error) {
 		gateway:     gateway,
 		cidr:        valid["cidr"].(string),
 		dnsServers:  convertToStringSlice(valid["dns_servers"]),
+		allowProxy:  valid["allow_proxy"].(bool),
+		managed:     valid["managed"].(bool),
+		rdnsMode:    RDNSMode(valid["rdns_mode"].(int)),
+	}
+	return result, nil
+}
+
+func readSubnetStats(source interface{}) (SubnetStats, error) {
+	fields := schema.Fields{
+		"num_available":     schema.ForceUint(),
+		"largest_available": schema.ForceUint(),
+		"num_unavailable":   schema.ForceUint(),
+		"total_addresses":   schema.ForceUint(),
+		"usage":             schema.Float(),
+		"usage_string":      schema.String(),
+		"ranges":            schema.OneOf(schema.Nil(""), schema.List(schema.Any())),
+	}
+	defaults := schema.Defaults{
+		"ranges": nil,
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return SubnetStats{}, WrapWithDeserializationError(err, "subnet statistics schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	var ranges []AddressRange
+	if rangesSource, ok := valid["ranges"].([]interface{}); ok {
+		if ranges, err = readAddressRanges(rangesSource); err != nil {
+			return SubnetStats{}, errors.Trace(err)
+		}
+	}
+
+	return SubnetStats{
+		NumAvailable:     uint(valid["num_available"].(uint64)),
+		LargestAvailable: uint(valid["largest_available"].(uint64)),
+		NumUnavailable:   uint(valid["num_unavailable"].(uint64)),
+		TotalAddresses:   uint(valid["total_addresses"].(uint64)),
+		Usage:            float32(valid["usage"].(float64)),
+		UsageString:      valid["usage_string"].(string),
+		Ranges:           ranges,
+	}, nil
+}
+
+func readAddressRanges(source interface{}) ([]AddressRange, error) {
+	fields := schema.Fields{
+		"start":         schema.String(),
+		"end":           schema.String(),
+		"purpose":       schema.OneOf(schema.Nil(""), schema.List(schema.String())),
+		"num_addresses": schema.ForceUint(),
+	}
+	defaults := schema.Defaults{
+		"purpose": nil,
+	}
+	checker := schema.List(schema.FieldMap(fields, defaults))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "address range schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	result := make([]AddressRange, 0, len(valid))
+	for _, value := range valid {
+		r := value.(map[string]interface{})
+		var purpose []string
+		if r["purpose"] != nil {
+			purpose = convertToStringSlice(r["purpose"])
+		}
+		result = append(result, AddressRange{
+			Start:        r["start"].(string),
+			End:          r["end"].(string),
+			Purpose:      purpose,
+			NumAddresses: uint(r["num_addresses"].(uint64)),
+		})
 	}
 	return result, nil
 }
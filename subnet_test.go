@@ -4,12 +4,19 @@
 package gomaasapi
 
 import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 )
 
-type subnetSuite struct{}
+type subnetSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&subnetSuite{})
 
@@ -38,6 +45,24 @@ func (*subnetSuite) TestReadSubnets(c *gc.C) {
 	c.Assert(vlan, gc.NotNil)
 	c.Assert(vlan.Name(), gc.Equals, "untagged")
 	c.Assert(subnet.DNSServers(), jc.DeepEquals, []string{"8.8.8.8", "8.8.4.4"})
+	c.Assert(subnet.AllowProxy(), jc.IsTrue)
+	c.Assert(subnet.Managed(), jc.IsTrue)
+	c.Assert(subnet.RDNSMode(), gc.Equals, RDNSRFC2317)
+
+	subnet = subnets[1]
+	c.Assert(subnet.AllowProxy(), jc.IsFalse)
+	c.Assert(subnet.Managed(), jc.IsFalse)
+	c.Assert(subnet.RDNSMode(), gc.Equals, RDNSEnabled)
+}
+
+func (*subnetSuite) TestReadSubnetOptionDefaults(c *gc.C) {
+	source := parseJSON(c, subnetResponse).([]interface{})[0].(map[string]interface{})
+	delete(source, "rdns_mode")
+	subnet, err := readSubnet(twoDotOh, source)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(subnet.AllowProxy(), jc.IsTrue)
+	c.Check(subnet.Managed(), jc.IsTrue)
+	c.Check(subnet.RDNSMode(), gc.Equals, RDNSRFC2317)
 }
 
 func (*subnetSuite) TestLowVersion(c *gc.C) {
@@ -51,6 +76,243 @@ func (*subnetSuite) TestHighVersion(c *gc.C) {
 	c.Assert(subnets, gc.HasLen, 2)
 }
 
+func (s *subnetSuite) getServerAndSubnet(c *gc.C) (*SimpleTestServer, *subnet) {
+	server, ctrl := createTestServerController(c, s)
+	subnet, err := readSubnet(twoDotOh, parseJSON(c, subnetItemResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	subnet.controller = ctrl.(*controller)
+	return server, subnet
+}
+
+func (s *subnetSuite) TestUpdate(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	response := updateJSONMap(c, subnetItemResponse, map[string]interface{}{
+		"name":        "updated",
+		"dns_servers": []string{},
+		"allow_proxy": false,
+		"rdns_mode":   0,
+	})
+	server.AddPutResponse(subnet.resourceURI, http.StatusOK, response)
+	allowProxy := false
+	rdnsMode := RDNSDisabled
+	err := subnet.Update(UpdateSubnetArgs{
+		Name:       "updated",
+		DNSServers: []string{},
+		AllowProxy: &allowProxy,
+		RDNSMode:   &rdnsMode,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(subnet.Name(), gc.Equals, "updated")
+	c.Check(subnet.DNSServers(), gc.HasLen, 0)
+	c.Check(subnet.AllowProxy(), jc.IsFalse)
+	c.Check(subnet.RDNSMode(), gc.Equals, RDNSDisabled)
+
+	request := server.LastRequest()
+	form := request.PostForm
+	c.Check(form, gc.HasLen, 4)
+	c.Check(form.Get("name"), gc.Equals, "updated")
+	c.Check(form["dns_servers"], jc.DeepEquals, []string{""})
+	c.Check(form.Get("allow_proxy"), gc.Equals, "false")
+	c.Check(form.Get("rdns_mode"), gc.Equals, "0")
+}
+
+func (s *subnetSuite) TestUpdateNothing(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	count := server.RequestCount()
+	err := subnet.Update(UpdateSubnetArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *subnetSuite) TestUpdateBadRequest(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	server.AddPutResponse(subnet.resourceURI, http.StatusBadRequest, "bad cidr")
+	err := subnet.Update(UpdateSubnetArgs{CIDR: "wat"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "bad cidr")
+}
+
+func (s *subnetSuite) TestDelete(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	server.AddDeleteResponse(subnet.resourceURI, http.StatusNoContent, "")
+	err := subnet.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *subnetSuite) TestDeleteMissing(c *gc.C) {
+	_, subnet := s.getServerAndSubnet(c)
+	err := subnet.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *subnetSuite) TestDeleteForbidden(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	server.AddDeleteResponse(subnet.resourceURI, http.StatusForbidden, "bad user")
+	err := subnet.Delete()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+	c.Assert(errors.Cause(err).Error(), gc.Equals, "bad user")
+}
+
+func (s *subnetSuite) TestStatistics(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	server.AddGetResponse(subnet.resourceURI+"?op=statistics", http.StatusOK, subnetStatisticsResponse)
+	stats, err := subnet.Statistics(false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stats, jc.DeepEquals, SubnetStats{
+		NumAvailable:     250,
+		LargestAvailable: 150,
+		NumUnavailable:   4,
+		TotalAddresses:   254,
+		Usage:            0.015748031,
+		UsageString:      "1.6%",
+	})
+}
+
+func (s *subnetSuite) TestStatisticsWithRanges(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	response := updateJSONMap(c, subnetStatisticsResponse, map[string]interface{}{
+		"ranges": parseJSON(c, unreservedIPRangesResponse),
+	})
+	server.AddGetResponse(subnet.resourceURI+"?include_ranges=true&op=statistics", http.StatusOK, response)
+	stats, err := subnet.Statistics(true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stats.Ranges, jc.DeepEquals, []AddressRange{
+		{Start: "192.168.100.2", End: "192.168.100.99", NumAddresses: 98},
+		{Start: "192.168.100.105", End: "192.168.100.254", NumAddresses: 150},
+	})
+}
+
+func (s *subnetSuite) TestStatisticsMissing(c *gc.C) {
+	_, subnet := s.getServerAndSubnet(c)
+	_, err := subnet.Statistics(false)
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *subnetSuite) TestStatisticsBadSchema(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	server.AddGetResponse(subnet.resourceURI+"?op=statistics", http.StatusOK, `{"usage": "wat"}`)
+	_, err := subnet.Statistics(false)
+	c.Assert(err, jc.Satisfies, IsDeserializationError)
+}
+
+func (*subnetSuite) TestReadSubnetStatsMatchesTestServer(c *gc.C) {
+	// The TestServer serializes the same types that the Controller reads.
+	server := NewTestServer("2.0")
+	defer server.Close()
+	server.NewSubnet(subnetJSON(defaultSubnet()))
+	server.AddFixedAddressRange(1, AddressRange{Start: "192.168.1.100", End: "192.168.1.109", Purpose: []string{"dynamic"}})
+	expected := server.subnetStatistics(server.subnets[1], true)
+
+	expectedJSON, err := json.Marshal(expected)
+	c.Assert(err, jc.ErrorIsNil)
+	stats, err := readSubnetStats(parseJSON(c, string(expectedJSON)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stats.Ranges, gc.HasLen, 2)
+	// The TestServer also records the ranges as integers, so compare the
+	// serialized forms.
+	obtainedJSON, err := json.Marshal(stats)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(obtainedJSON), gc.Equals, string(expectedJSON))
+}
+
+func (s *subnetSuite) TestReservedIPRanges(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	server.AddGetResponse(subnet.resourceURI+"?op=reserved_ip_ranges", http.StatusOK, reservedIPRangesResponse)
+	ranges, err := subnet.ReservedIPRanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ranges, jc.DeepEquals, []AddressRange{
+		{Start: "192.168.100.1", End: "192.168.100.1", Purpose: []string{"gateway-ip"}, NumAddresses: 1},
+		{Start: "192.168.100.100", End: "192.168.100.104", Purpose: []string{"assigned-ip", "dynamic"}, NumAddresses: 5},
+	})
+}
+
+func (s *subnetSuite) TestUnreservedIPRanges(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	server.AddGetResponse(subnet.resourceURI+"?op=unreserved_ip_ranges", http.StatusOK, unreservedIPRangesResponse)
+	ranges, err := subnet.UnreservedIPRanges()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ranges, gc.HasLen, 2)
+	c.Check(ranges[1].Start, gc.Equals, "192.168.100.105")
+	c.Check(ranges[1].Purpose, gc.IsNil)
+}
+
+func (s *subnetSuite) TestUnreservedIPRangesForbidden(c *gc.C) {
+	server, subnet := s.getServerAndSubnet(c)
+	server.AddGetResponse(subnet.resourceURI+"?op=unreserved_ip_ranges", http.StatusForbidden, "bad user")
+	_, err := subnet.UnreservedIPRanges()
+	c.Assert(err, jc.Satisfies, IsPermissionError)
+}
+
+var subnetStatisticsResponse = `
+{
+    "num_available": 250,
+    "largest_available": 150,
+    "num_unavailable": 4,
+    "total_addresses": 254,
+    "usage": 0.015748031,
+    "usage_string": "1.6%",
+    "available_string": "98.4%"
+}
+`
+
+var reservedIPRangesResponse = `
+[
+    {
+        "start": "192.168.100.1",
+        "end": "192.168.100.1",
+        "purpose": ["gateway-ip"],
+        "num_addresses": 1
+    },
+    {
+        "start": "192.168.100.100",
+        "end": "192.168.100.104",
+        "purpose": ["assigned-ip", "dynamic"],
+        "num_addresses": 5
+    }
+]
+`
+
+var unreservedIPRangesResponse = `
+[
+    {
+        "start": "192.168.100.2",
+        "end": "192.168.100.99",
+        "num_addresses": 98
+    },
+    {
+        "start": "192.168.100.105",
+        "end": "192.168.100.254",
+        "num_addresses": 150
+    }
+]
+`
+
+var subnetItemResponse = `
+{
+    "gateway_ip": "192.168.100.1",
+    "name": "192.168.100.0/24",
+    "vlan": {
+        "fabric": "fabric-0",
+        "resource_uri": "/MAAS/api/2.0/vlans/1/",
+        "name": "untagged",
+        "secondary_rack": null,
+        "primary_rack": "4y3h7n",
+        "vid": 0,
+        "dhcp_on": true,
+        "id": 1,
+        "mtu": 1500
+    },
+    "space": "space-0",
+    "id": 1,
+    "resource_uri": "/MAAS/api/2.0/subnets/1/",
+    "dns_servers": ["8.8.8.8", "8.8.4.4"],
+    "cidr": "192.168.100.0/24",
+    "allow_proxy": true,
+    "managed": true,
+    "rdns_mode": 2
+}
+`
+
 var subnetResponse = `
 [
     {
@@ -93,7 +355,9 @@ var subnetResponse = `
         "resource_uri": "/MAAS/api/2.0/subnets/34/",
         "dns_servers": null,
         "cidr": "192.168.122.0/24",
-        "rdns_mode": 2
+        "allow_proxy": false,
+        "managed": false,
+        "rdns_mode": 1
     }
 ]
 `
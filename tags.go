@@ -10,6 +10,8 @@ import (
 )
 
 type tag struct {
+	controller *controller
+
 	resourceURI string
 
 	name       string
@@ -18,22 +20,169 @@ type tag struct {
 	kernelOpts string
 }
 
-func (tag tag) Name() string {
+func (tag *tag) updateFrom(other *tag) {
+	tag.resourceURI = other.resourceURI
+	tag.name = other.name
+	tag.comment = other.comment
+	tag.definition = other.definition
+	tag.kernelOpts = other.kernelOpts
+}
+
+func (tag *tag) Name() string {
 	return tag.name
 }
 
-func (tag tag) Comment() string {
+func (tag *tag) Comment() string {
 	return tag.comment
 }
 
-func (tag tag) Definition() string {
+func (tag *tag) Definition() string {
 	return tag.definition
 }
 
-func (tag tag) KernelOpts() string {
+func (tag *tag) KernelOpts() string {
 	return tag.kernelOpts
 }
 
+// UpdateTagArgs is an argument struct for passing parameters to the
+// Tag.Update method. Only the values that are specified are changed.
+type UpdateTagArgs struct {
+	Name string
+	// Comment, Definition and KernelOpts replace the existing values when
+	// not nil. An empty string removes the value.
+	Comment *string
+	// Definition is an XPath expression that is evaluated against the
+	// hardware details of the machines. Changing the definition causes
+	// MAAS to rebuild the list of machines with the tag, and removing it
+	// makes the tag a manual tag again.
+	Definition *string
+	KernelOpts *string
+}
+
+// Update implements Tag.
+func (tag *tag) Update(args UpdateTagArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	if args.Comment != nil {
+		params.Values.Add("comment", *args.Comment)
+	}
+	if args.Definition != nil {
+		params.Values.Add("definition", *args.Definition)
+	}
+	if args.KernelOpts != nil {
+		params.Values.Add("kernel_opts", *args.KernelOpts)
+	}
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := tag.controller.put(tag.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readTag(tag.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	tag.updateFrom(response)
+	return nil
+}
+
+// Delete implements Tag.
+func (tag *tag) Delete() error {
+	err := tag.controller.delete(tag.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// Rebuild implements Tag.
+func (tag *tag) Rebuild() error {
+	_, err := tag.controller._postRaw(tag.resourceURI, "rebuild", nil, nil)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// Machines implements Tag.
+func (tag *tag) Machines() ([]Machine, error) {
+	source, err := tag.controller.getOp(tag.resourceURI, "machines")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	machines, err := readMachines(tag.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []Machine
+	for _, m := range machines {
+		m.controller = tag.controller
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+// Devices implements Tag.
+func (tag *tag) Devices() ([]Device, error) {
+	source, err := tag.controller.getOp(tag.resourceURI, "devices")
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	devices, err := readDevices(tag.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var result []Device
+	for _, d := range devices {
+		d.controller = tag.controller
+		result = append(result, d)
+	}
+	return result, nil
+}
+
+// UpdateNodes implements Tag.
+func (tag *tag) UpdateNodes(add, remove []string) (added int, removed int, err error) {
+	if len(add) == 0 && len(remove) == 0 {
+		return 0, 0, nil
+	}
+	params := NewURLParams()
+	params.MaybeAddMany("add", add)
+	params.MaybeAddMany("remove", remove)
+	source, err := tag.controller.post(tag.resourceURI, "update_nodes", params.Values)
+	if err != nil {
+		return 0, 0, translateServerError(err)
+	}
+
+	checker := schema.FieldMap(schema.Fields{
+		"added":   schema.ForceInt(),
+		"removed": schema.ForceInt(),
+	}, nil)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return 0, 0, WrapWithDeserializationError(err, "update nodes response schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return valid["added"].(int), valid["removed"].(int), nil
+}
+
+func readTag(controllerVersion version.Number, source interface{}) (*tag, error) {
+	readFunc, err := getTagDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "tag base schema check failed")
+	}
+
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readTags(controllerVersion version.Number, source interface{}) ([]*tag, error) {
 	readFunc, err := getTagDeserializationFunc(controllerVersion)
 	if err != nil {
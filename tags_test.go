@@ -1,6 +1,10 @@
 package gomaasapi
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -25,6 +29,204 @@ func (*tagSuite) TestReadTags(c *gc.C) {
 	c.Check(tag.KernelOpts(), gc.Equals, "nvme_core")
 }
 
+func (s *tagSuite) getServerAndTag(c *gc.C) (*SimpleTestServer, *tag) {
+	server, ctrl := createTestServerController(c, s)
+	tag, err := readTag(twoDotOh, parseJSON(c, tagResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	tag.controller = ctrl.(*controller)
+	return server, tag
+}
+
+func (s *tagSuite) TestReadTagBadSchema(c *gc.C) {
+	_, err := readTag(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `tag base schema check failed: expected map, got string("wat?")`)
+}
+
+func (s *tagSuite) TestUpdate(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	response := updateJSONMap(c, tagResponse, map[string]interface{}{
+		"comment":    "QEMU machines",
+		"definition": "//node[@class='system']/vendor = 'QEMU'",
+	})
+	server.AddPutResponse(tag.resourceURI, http.StatusOK, response)
+	comment, definition := "QEMU machines", "//node[@class='system']/vendor = 'QEMU'"
+	err := tag.Update(UpdateTagArgs{
+		Comment:    &comment,
+		Definition: &definition,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tag.Comment(), gc.Equals, "QEMU machines")
+	c.Check(tag.Definition(), gc.Equals, "//node[@class='system']/vendor = 'QEMU'")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("comment"), gc.Equals, "QEMU machines")
+	c.Check(form.Get("definition"), gc.Equals, "//node[@class='system']/vendor = 'QEMU'")
+}
+
+func (s *tagSuite) TestUpdateClear(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	response := updateJSONMap(c, tagResponse, map[string]interface{}{
+		"definition":  "",
+		"kernel_opts": "",
+	})
+	server.AddPutResponse(tag.resourceURI, http.StatusOK, response)
+	empty := ""
+	err := tag.Update(UpdateTagArgs{
+		Definition: &empty,
+		KernelOpts: &empty,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tag.Definition(), gc.Equals, "")
+	c.Check(tag.KernelOpts(), gc.Equals, "")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{
+		"definition":  {""},
+		"kernel_opts": {""},
+	})
+}
+
+func (s *tagSuite) TestUpdateNothing(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	count := server.RequestCount()
+	err := tag.Update(UpdateTagArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *tagSuite) TestUpdateBadRequest(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	server.AddPutResponse(tag.resourceURI, http.StatusBadRequest, "invalid xpath")
+	definition := "//["
+	err := tag.Update(UpdateTagArgs{Definition: &definition})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "invalid xpath")
+}
+
+func (s *tagSuite) TestDelete(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	server.AddDeleteResponse(tag.resourceURI, http.StatusNoContent, "")
+	err := tag.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *tagSuite) TestDeleteMissing(c *gc.C) {
+	_, tag := s.getServerAndTag(c)
+	err := tag.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *tagSuite) TestRebuild(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	server.AddPostResponse(tag.resourceURI+"?op=rebuild", http.StatusOK, `{"rebuilding": "virtual"}`)
+	err := tag.Rebuild()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.LastRequest().URL.String(), gc.Equals, tag.resourceURI+"?op=rebuild")
+}
+
+func (s *tagSuite) TestRebuildMissing(c *gc.C) {
+	_, tag := s.getServerAndTag(c)
+	err := tag.Rebuild()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *tagSuite) TestMachines(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	server.AddGetResponse(tag.resourceURI+"?op=machines", http.StatusOK, machinesResponse)
+	machines, err := tag.Machines()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(machines, gc.HasLen, 3)
+	c.Assert(machines[0].(*machine).controller, gc.Equals, tag.controller)
+}
+
+func (s *tagSuite) TestDevices(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	server.AddGetResponse(tag.resourceURI+"?op=devices", http.StatusOK, devicesResponse)
+	devices, err := tag.Devices()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(devices, gc.HasLen, 1)
+	c.Assert(devices[0].(*device).controller, gc.Equals, tag.controller)
+}
+
+func (s *tagSuite) TestUpdateNodes(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	server.AddPostResponse(tag.resourceURI+"?op=update_nodes", http.StatusOK, `{"added": 2, "removed": 1}`)
+	added, removed, err := tag.UpdateNodes([]string{"4y3ha3", "4y3ha4"}, []string{"4y3ha6"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(added, gc.Equals, 2)
+	c.Check(removed, gc.Equals, 1)
+
+	form := server.LastRequest().PostForm
+	c.Check(form["add"], jc.DeepEquals, []string{"4y3ha3", "4y3ha4"})
+	c.Check(form["remove"], jc.DeepEquals, []string{"4y3ha6"})
+}
+
+func (s *tagSuite) TestUpdateNodesNothing(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	count := server.RequestCount()
+	added, removed, err := tag.UpdateNodes(nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(added, gc.Equals, 0)
+	c.Check(removed, gc.Equals, 0)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *tagSuite) TestUpdateNodesWithDefinition(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	server.AddPostResponse(tag.resourceURI+"?op=update_nodes", http.StatusConflict, "tag has a definition")
+	_, _, err := tag.UpdateNodes([]string{"4y3ha3"}, nil)
+	c.Assert(err, jc.Satisfies, IsCannotCompleteError)
+	c.Assert(err.Error(), gc.Equals, "tag has a definition")
+}
+
+func (s *tagSuite) TestUpdateNodesBadResponse(c *gc.C) {
+	server, tag := s.getServerAndTag(c)
+	server.AddPostResponse(tag.resourceURI+"?op=update_nodes", http.StatusOK, `{"added": "lots"}`)
+	_, _, err := tag.UpdateNodes([]string{"4y3ha3"}, nil)
+	c.Assert(err, jc.Satisfies, IsDeserializationError)
+}
+
+func (s *tagSuite) TestCreateTagValidates(c *gc.C) {
+	_, ctrl := createTestServerController(c, s)
+	_, err := ctrl.CreateTag("", "comment", "", "")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, "missing name not valid")
+}
+
+func (s *tagSuite) TestCreateTag(c *gc.C) {
+	server, ctrl := createTestServerController(c, s)
+	server.AddPostResponse("/api/2.0/tags/?op=", http.StatusOK, tagResponse)
+	result, err := ctrl.CreateTag("virtual", "virtual machines", "", "console=ttyS0")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(result.Name(), gc.Equals, "virtual")
+	c.Check(result.Comment(), gc.Equals, "virtual machines")
+	c.Check(result.(*tag).controller, gc.Equals, ctrl.(*controller))
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 3)
+	c.Check(form.Get("name"), gc.Equals, "virtual")
+	c.Check(form.Get("comment"), gc.Equals, "virtual machines")
+	c.Check(form.Get("kernel_opts"), gc.Equals, "console=ttyS0")
+}
+
+func (s *tagSuite) TestCreateTagBadRequest(c *gc.C) {
+	server, ctrl := createTestServerController(c, s)
+	server.AddPostResponse("/api/2.0/tags/?op=", http.StatusBadRequest, "tag already exists")
+	_, err := ctrl.CreateTag("virtual", "", "", "")
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "tag already exists")
+}
+
+var tagResponse = `{
+	"resource_uri": "/MAAS/api/2.0/tags/virtual/",
+	"name": "virtual",
+	"comment": "virtual machines",
+	"definition": "",
+	"kernel_opts": ""
+}`
+
 var tagsResponse = `[
 	{
 		"resource_uri": "/2.0/tags/virtual",
@@ -4,7 +4,9 @@
 package gomaasapi
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
@@ -216,7 +218,14 @@ func (s *SimpleTestServer) handler(writer http.ResponseWriter, request *http.Req
 	case "PUT":
 		responses = s.putResponses
 		responseIndex = s.putResponseIndex
-		err = request.ParseForm()
+		if request.Header.Get("Content-Type") == "application/octet-stream" {
+			// Keep the content so that tests can check what was sent.
+			var content []byte
+			content, err = readAndClose(request.Body)
+			request.Body = io.NopCloser(bytes.NewReader(content))
+		} else {
+			err = request.ParseForm()
+		}
 		if err != nil {
 			panic(err)
 		}
@@ -109,6 +109,8 @@ type TestServer struct {
 	nextVLAN        int
 	staticRoutes    map[uint]*TestStaticRoute
 	nextStaticRoute uint
+	ipRanges        map[uint]*TestIPRange
+	nextIPRange     uint
 }
 
 type TestDevice struct {
@@ -257,6 +259,8 @@ func (server *TestServer) Clear() {
 	server.nextVLAN = 1
 	server.staticRoutes = make(map[uint]*TestStaticRoute)
 	server.nextStaticRoute = 1
+	server.ipRanges = make(map[uint]*TestIPRange)
+	server.nextIPRange = 1
 }
 
 // SetVersionJSON sets the JSON response (capabilities) returned from the
@@ -640,6 +644,11 @@ func NewTestServer(version string) *TestServer {
 		subnetsHandler(server, w, r)
 	})
 
+	ipRangesURL := getIPRangesEndpoint(server.version)
+	serveMux.HandleFunc(ipRangesURL, func(w http.ResponseWriter, r *http.Request) {
+		ipRangesHandler(server, w, r)
+	})
+
 	spacesURL := getSpacesEndpoint(server.version)
 	serveMux.HandleFunc(spacesURL, func(w http.ResponseWriter, r *http.Request) {
 		spacesHandler(server, w, r)
@@ -655,6 +664,12 @@ func NewTestServer(version string) *TestServer {
 		vlansHandler(server, w, r)
 	})
 
+	// The VLANs of a fabric are created and listed under the fabric.
+	fabricsURL := getFabricsEndpoint(server.version)
+	serveMux.HandleFunc(fabricsURL, func(w http.ResponseWriter, r *http.Request) {
+		vlansHandler(server, w, r)
+	})
+
 	var mu sync.Mutex
 	singleFile := func(w http.ResponseWriter, req *http.Request) {
 		mu.Lock()
@@ -1762,18 +1777,13 @@ func tagsHandler(server *TestServer, w http.ResponseWriter, r *http.Request) {
 
 // newTagHandler creates, stores and returns new tag.
 func newTagHandler(server *TestServer, w http.ResponseWriter, r *http.Request, name string, values url.Values) {
-	comment, hascomment := getValue(values, "comment")
-	var attrs map[string]interface{}
-	if hascomment {
-		attrs = map[string]interface{}{
-			"name":      name,
-			"comment":   comment,
-			resourceURI: getTagURL(server.version, name),
-		}
-	} else {
-		attrs = map[string]interface{}{
-			"name":      name,
-			resourceURI: getTagURL(server.version, name),
+	attrs := map[string]interface{}{
+		"name":      name,
+		resourceURI: getTagURL(server.version, name),
+	}
+	for _, key := range []string{"comment", "definition", "kernel_opts"} {
+		if value, ok := getValue(values, key); ok {
+			attrs[key] = value
 		}
 	}
 	obj := maasify(server.client, attrs)
@@ -1781,7 +1791,7 @@ func newTagHandler(server *TestServer, w http.ResponseWriter, r *http.Request, n
 	res, err := json.MarshalIndent(obj, "", "  ")
 	checkError(err)
 	w.WriteHeader(http.StatusOK)
-	fmt.Fprint(w, res)
+	fmt.Fprint(w, string(res))
 }
 
 // tagHandler handles requests for '/api/<version>/tag/<name>/'.
@@ -10,6 +10,8 @@ import (
 	"net/http"
 	"net/url"
 	"regexp"
+
+	"github.com/juju/errors"
 )
 
 func getSpacesEndpoint(version string) string {
@@ -19,6 +21,7 @@ func getSpacesEndpoint(version string) string {
 // TestSpace is the MAAS API space representation
 type TestSpace struct {
 	Name        string       `json:"name"`
+	Description string       `json:"description"`
 	Subnets     []TestSubnet `json:"subnets"`
 	ResourceURI string       `json:"resource_uri"`
 	ID          uint         `json:"id"`
@@ -41,8 +44,10 @@ func spacesHandler(server *TestServer, w http.ResponseWriter, r *http.Request) {
 	var ID uint
 	var gotID bool
 	if spacesURLMatch != nil {
-		ID, err = NameOrIDToID(spacesURLMatch[1], server.spaceNameToID, 1, uint(len(server.spaces)))
-
+		ID, err = NameOrIDToID(spacesURLMatch[1], server.spaceNameToID, 1, server.nextSpace-1)
+		if err == nil && server.spaces[ID] == nil {
+			err = errors.NotFoundf("space %d", ID)
+		}
 		if err != nil {
 			http.NotFoundHandler().ServeHTTP(w, r)
 			return
@@ -81,10 +86,46 @@ func spacesHandler(server *TestServer, w http.ResponseWriter, r *http.Request) {
 		}
 		checkError(err)
 	case "POST":
-		//server.NewSpace(r.Body)
+		if gotID {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// The Controller posts form values, while the tests in this
+		// package post the JSON of a CreateSpace.
+		if values := parseRequestValues(r); values != nil {
+			space := &TestSpace{}
+			if err := server.setSpaceValues(space, values); err != nil {
+				badRequestError(w, err)
+				return
+			}
+			if space.Name == "" {
+				space.Name = fmt.Sprintf("space-%d", server.nextSpace)
+			}
+			PrettyJsonWriter(server.addSpace(space), w)
+			return
+		}
+		PrettyJsonWriter(server.NewSpace(r.Body), w)
 	case "PUT":
-		//server.UpdateSpace(r.Body)
+		if !gotID {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		space := server.spaces[ID]
+		updated := *space
+		if err := server.setSpaceValues(&updated, parseRequestValues(r)); err != nil {
+			badRequestError(w, err)
+			return
+		}
+		server.renameSpace(space.Name, updated.Name)
+		*space = updated
+		server.setSubnetsOnSpace(space)
+		PrettyJsonWriter(space, w)
 	case "DELETE":
+		if !gotID {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		server.renameSpace(server.spaces[ID].Name, "")
 		delete(server.spaces, ID)
 		w.WriteHeader(http.StatusOK)
 	default:
@@ -94,7 +135,8 @@ func spacesHandler(server *TestServer, w http.ResponseWriter, r *http.Request) {
 
 // CreateSpace is used to create new spaces on the server.
 type CreateSpace struct {
-	Name string `json:"name"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
 }
 
 func decodePostedSpace(spaceJSON io.Reader) CreateSpace {
@@ -108,16 +150,73 @@ func decodePostedSpace(spaceJSON io.Reader) CreateSpace {
 // NewSpace creates a space in the test server
 func (server *TestServer) NewSpace(spaceJSON io.Reader) *TestSpace {
 	postedSpace := decodePostedSpace(spaceJSON)
-	newSpace := &TestSpace{Name: postedSpace.Name}
+	return server.addSpace(&TestSpace{
+		Name:        postedSpace.Name,
+		Description: postedSpace.Description,
+	})
+}
+
+func (server *TestServer) addSpace(newSpace *TestSpace) *TestSpace {
 	newSpace.ID = server.nextSpace
 	newSpace.ResourceURI = fmt.Sprintf("/api/%s/spaces/%d/", server.version, int(server.nextSpace))
 	server.spaces[server.nextSpace] = newSpace
 	server.spaceNameToID[newSpace.Name] = newSpace.ID
+	server.setSubnetsOnSpace(newSpace)
 
 	server.nextSpace++
 	return newSpace
 }
 
+// setSpaceValues updates the space with the form values posted by the
+// Controller. Space names must be unique.
+func (server *TestServer) setSpaceValues(space *TestSpace, values url.Values) error {
+	if name, ok := getValue(values, "name"); ok {
+		if ID, ok := server.spaceNameToID[name]; ok && ID != space.ID {
+			return errors.AlreadyExistsf("space %q", name)
+		}
+		space.Name = name
+	}
+	if description, ok := getValue(values, "description"); ok {
+		space.Description = description
+	}
+	return nil
+}
+
+// renameSpace updates the subnets and VLANs in the space with the old name
+// to be in the space with the new name. An empty new name removes the space.
+func (server *TestServer) renameSpace(oldName, newName string) {
+	if oldName == newName {
+		return
+	}
+	if ID, ok := server.spaceNameToID[oldName]; ok {
+		delete(server.spaceNameToID, oldName)
+		if newName != "" {
+			server.spaceNameToID[newName] = ID
+		}
+	}
+	for ID, subnet := range server.subnets {
+		if subnet.Space == oldName {
+			subnet.Space = newName
+			server.subnets[ID] = subnet
+		}
+	}
+	for ID, vlan := range server.vlans {
+		if vlan.Space == oldName {
+			vlan.Space = newName
+			server.vlans[ID] = vlan
+		}
+	}
+}
+
+// spaceName returns the name of the space with the given ID or name.
+func (server *TestServer) spaceName(nameOrID string) (string, error) {
+	ID, err := NameOrIDToID(nameOrID, server.spaceNameToID, 1, server.nextSpace-1)
+	if err != nil || server.spaces[ID] == nil {
+		return "", errors.NotFoundf("space %q", nameOrID)
+	}
+	return server.spaces[ID].Name, nil
+}
+
 // setSubnetsOnSpace fetches the subnets for the specified space and adds them
 // to it.
 func (server *TestServer) setSubnetsOnSpace(space *TestSpace) {
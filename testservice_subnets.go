@@ -12,7 +12,10 @@ import (
 	"net/url"
 	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/juju/errors"
 )
 
 func getSubnetsEndpoint(version string) string {
@@ -38,6 +41,12 @@ type CreateSubnet struct {
 	// VLAN belongs to or defaults to the default fabric.
 	Fabric *uint `json:"fabric"`
 
+	// AllowProxy, Managed and RDNSMode default to true, true and 2
+	// respectively, as they do in MAAS.
+	AllowProxy *bool `json:"allow_proxy"`
+	Managed    *bool `json:"managed"`
+	RDNSMode   *int  `json:"rdns_mode"`
+
 	// VID of the VLAN this subnet belongs to. Currently ignored.
 	// TODO: Only used when vlan
 	// is not provided. Picks the VLAN with this VID in the provided
@@ -56,6 +65,9 @@ type TestSubnet struct {
 	VLAN       TestVLAN `json:"vlan"`
 	GatewayIP  string   `json:"gateway_ip"`
 	CIDR       string   `json:"cidr"`
+	AllowProxy bool     `json:"allow_proxy"`
+	Managed    bool     `json:"managed"`
+	RDNSMode   int      `json:"rdns_mode"`
 
 	ResourceURI        string         `json:"resource_uri"`
 	ID                 uint           `json:"id"`
@@ -67,8 +79,6 @@ type TestSubnet struct {
 // that subnet stores.
 func (server *TestServer) AddFixedAddressRange(subnetID uint, ar AddressRange) {
 	subnet := server.subnets[subnetID]
-	ar.startUint = IPFromString(ar.Start).UInt64()
-	ar.endUint = IPFromString(ar.End).UInt64()
 	subnet.FixedAddressRanges = append(subnet.FixedAddressRanges, ar)
 	server.subnets[subnetID] = subnet
 }
@@ -87,12 +97,16 @@ func subnetsHandler(server *TestServer, w http.ResponseWriter, r *http.Request)
 	var ID uint
 	var gotID bool
 	if subnetsURLMatch != nil {
-		ID, err = NameOrIDToID(subnetsURLMatch[1], server.subnetNameToID, 1, uint(len(server.subnets)))
+		ID, err = NameOrIDToID(subnetsURLMatch[1], server.subnetNameToID, 1, server.nextSubnet-1)
 
 		if err != nil {
 			http.NotFoundHandler().ServeHTTP(w, r)
 			return
 		}
+		if _, ok := server.subnets[ID]; !ok {
+			http.NotFoundHandler().ServeHTTP(w, r)
+			return
+		}
 
 		gotID = true
 	}
@@ -139,10 +153,49 @@ func subnetsHandler(server *TestServer, w http.ResponseWriter, r *http.Request)
 		}
 		checkError(err)
 	case "POST":
+		// The Controller posts form values, while the tests in this
+		// package post the JSON of a CreateSubnet.
+		if values := parseRequestValues(r); values != nil {
+			subnet := TestSubnet{AllowProxy: true, Managed: true, RDNSMode: 2}
+			if err := server.setSubnetValues(&subnet, values); err != nil {
+				badRequestError(w, err)
+				return
+			}
+			if subnet.CIDR == "" {
+				badRequestError(w, errors.New("missing cidr"))
+				return
+			}
+			if subnet.Name == "" {
+				subnet.Name = subnet.CIDR
+			}
+			PrettyJsonWriter(server.addSubnet(subnet), w)
+			return
+		}
 		server.NewSubnet(r.Body)
 	case "PUT":
+		if values := parseRequestValues(r); values != nil {
+			if !gotID {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			subnet := server.subnets[ID]
+			if err := server.setSubnetValues(&subnet, values); err != nil {
+				badRequestError(w, err)
+				return
+			}
+			delete(server.subnetNameToID, server.subnets[ID].Name)
+			server.subnetNameToID[subnet.Name] = ID
+			server.subnets[ID] = subnet
+			PrettyJsonWriter(subnet, w)
+			return
+		}
 		server.UpdateSubnet(r.Body)
 	case "DELETE":
+		if !gotID {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		delete(server.subnetNameToID, server.subnets[ID].Name)
 		delete(server.subnets, ID)
 		w.WriteHeader(http.StatusOK)
 	default:
@@ -156,24 +209,22 @@ func (a addressList) Len() int           { return len(a) }
 func (a addressList) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
 func (a addressList) Less(i, j int) bool { return a[i].UInt64() < a[j].UInt64() }
 
-// AddressRange is used to generate reserved IP address range lists
-type AddressRange struct {
-	Start        string `json:"start"`
-	startUint    uint64
-	End          string `json:"end"`
-	endUint      uint64
-	Purpose      []string `json:"purpose,omitempty"`
-	NumAddresses uint     `json:"num_addresses"`
+// testAddressRange is an AddressRange along with the numeric form of its
+// addresses, which the TestServer works out the ranges of subnets with.
+type testAddressRange struct {
+	AddressRange
+	startUint uint64
+	endUint   uint64
 }
 
 // AddressRangeList is a list of AddressRange
 type AddressRangeList struct {
-	ar []AddressRange
+	ar []testAddressRange
 }
 
 // Append appends a new AddressRange to an AddressRangeList
 func (ranges *AddressRangeList) Append(startIP, endIP IP) {
-	var i AddressRange
+	var i testAddressRange
 	i.Start, i.End = startIP.String(), endIP.String()
 	i.startUint, i.endUint = startIP.UInt64(), endIP.UInt64()
 	i.NumAddresses = uint(1 + endIP.UInt64() - startIP.UInt64())
@@ -181,9 +232,20 @@ func (ranges *AddressRangeList) Append(startIP, endIP IP) {
 	ranges.ar = append(ranges.ar, i)
 }
 
+// addressRanges returns the ranges of the list without their numeric
+// addresses, as the API returns them.
+func (ranges *AddressRangeList) addressRanges() []AddressRange {
+	var result []AddressRange
+	for _, r := range ranges.ar {
+		result = append(result, r.AddressRange)
+	}
+	return result
+}
+
 func appendRangesToIPList(subnet TestSubnet, ipAddresses *[]IP) {
 	for _, r := range subnet.FixedAddressRanges {
-		for v := r.startUint; v <= r.endUint; v++ {
+		start, end := IPFromString(r.Start).UInt64(), IPFromString(r.End).UInt64()
+		for v := start; v <= end; v++ {
 			ip := IPFromInt64(v)
 			ip.Purpose = r.Purpose
 			*ipAddresses = append(*ipAddresses, ip)
@@ -238,7 +300,7 @@ func (server *TestServer) subnetUnreservedIPRanges(subnet TestSubnet) []AddressR
 		ranges.Append(startIP, lastUsableIP)
 	}
 
-	return ranges.ar
+	return ranges.addressRanges()
 }
 
 func (server *TestServer) subnetReservedIPRanges(subnet TestSubnet) []AddressRange {
@@ -251,7 +313,7 @@ func (server *TestServer) subnetReservedIPRanges(subnet TestSubnet) []AddressRan
 	appendRangesToIPList(subnet, &ipAddresses)
 	sort.Sort(addressList(ipAddresses))
 	if len(ipAddresses) == 0 {
-		ar := ranges.ar
+		ar := ranges.addressRanges()
 		if ar == nil {
 			ar = []AddressRange{}
 		}
@@ -278,18 +340,7 @@ func (server *TestServer) subnetReservedIPRanges(subnet TestSubnet) []AddressRan
 		ranges.Append(startIP, lastIP)
 	}
 
-	return ranges.ar
-}
-
-// SubnetStats holds statistics about a subnet
-type SubnetStats struct {
-	NumAvailable     uint           `json:"num_available"`
-	LargestAvailable uint           `json:"largest_available"`
-	NumUnavailable   uint           `json:"num_unavailable"`
-	TotalAddresses   uint           `json:"total_addresses"`
-	Usage            float32        `json:"usage"`
-	UsageString      string         `json:"usage_string"`
-	Ranges           []AddressRange `json:"ranges"`
+	return ranges.addressRanges()
 }
 
 func (server *TestServer) subnetStatistics(subnet TestSubnet, includeRanges bool) SubnetStats {
@@ -341,8 +392,12 @@ func (server *TestServer) UpdateSubnet(subnetJSON io.Reader) TestSubnet {
 // NewSubnet creates a subnet in the test server
 func (server *TestServer) NewSubnet(subnetJSON io.Reader) *TestSubnet {
 	postedSubnet := decodePostedSubnet(subnetJSON)
-	newSubnet := subnetFromCreateSubnet(postedSubnet)
+	return server.addSubnet(subnetFromCreateSubnet(postedSubnet))
+}
+
+func (server *TestServer) addSubnet(newSubnet TestSubnet) *TestSubnet {
 	newSubnet.ID = server.nextSubnet
+	newSubnet.ResourceURI = fmt.Sprintf("%s%d/", getSubnetsEndpoint(server.version), newSubnet.ID)
 	server.subnets[server.nextSubnet] = newSubnet
 	server.subnetNameToID[newSubnet.Name] = newSubnet.ID
 
@@ -350,6 +405,65 @@ func (server *TestServer) NewSubnet(subnetJSON io.Reader) *TestSubnet {
 	return &newSubnet
 }
 
+// setSubnetValues updates the subnet with the form values posted by the
+// Controller.
+func (server *TestServer) setSubnetValues(subnet *TestSubnet, values url.Values) error {
+	if cidr, ok := getValue(values, "cidr"); ok {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.NotValidf("cidr %q", cidr)
+		}
+		subnet.CIDR = cidr
+	}
+	if name, ok := getValue(values, "name"); ok {
+		subnet.Name = name
+	}
+	if space, ok := getValue(values, "space"); ok {
+		subnet.Space = space
+	}
+	if gateway, ok := getValue(values, "gateway_ip"); ok {
+		subnet.GatewayIP = gateway
+	}
+	if _, ok := values["dns_servers"]; ok {
+		subnet.DNSServers = []string{}
+		for _, server := range strings.Split(values.Get("dns_servers"), ",") {
+			if server != "" {
+				subnet.DNSServers = append(subnet.DNSServers, server)
+			}
+		}
+	}
+	if vlanID, ok := getValue(values, "vlan"); ok {
+		id, err := strconv.Atoi(vlanID)
+		if err != nil {
+			return errors.NotValidf("vlan %q", vlanID)
+		}
+		vlan, ok := server.vlans[id]
+		if !ok {
+			return errors.NotFoundf("vlan %d", id)
+		}
+		subnet.VLAN = vlan
+	}
+	for name, field := range map[string]*bool{
+		"allow_proxy": &subnet.AllowProxy,
+		"managed":     &subnet.Managed,
+	} {
+		if value, ok := getValue(values, name); ok {
+			b, err := strconv.ParseBool(value)
+			if err != nil {
+				return errors.NotValidf("%s %q", name, value)
+			}
+			*field = b
+		}
+	}
+	if value, ok := getValue(values, "rdns_mode"); ok {
+		mode, err := strconv.Atoi(value)
+		if err != nil || mode < 0 || mode > 2 {
+			return errors.NotValidf("rdns_mode %q", value)
+		}
+		subnet.RDNSMode = mode
+	}
+	return nil
+}
+
 // NodeNetworkInterface represents a network interface attached to a node
 type NodeNetworkInterface struct {
 	Name  string        `json:"name"`
@@ -392,5 +506,160 @@ func subnetFromCreateSubnet(postedSubnet CreateSubnet) TestSubnet {
 	newSubnet.GatewayIP = postedSubnet.GatewayIP
 	newSubnet.CIDR = postedSubnet.CIDR
 	newSubnet.ID = postedSubnet.ID
+	newSubnet.AllowProxy = postedSubnet.AllowProxy == nil || *postedSubnet.AllowProxy
+	newSubnet.Managed = postedSubnet.Managed == nil || *postedSubnet.Managed
+	newSubnet.RDNSMode = 2
+	if postedSubnet.RDNSMode != nil {
+		newSubnet.RDNSMode = *postedSubnet.RDNSMode
+	}
 	return newSubnet
 }
+
+func getIPRangesEndpoint(version string) string {
+	return fmt.Sprintf("/api/%s/ipranges/", version)
+}
+
+// TestIPRange is the MAAS API IP range representation
+type TestIPRange struct {
+	Type    string     `json:"type"`
+	StartIP string     `json:"start_ip"`
+	EndIP   string     `json:"end_ip"`
+	Comment string     `json:"comment"`
+	Subnet  TestSubnet `json:"subnet"`
+
+	ResourceURI string `json:"resource_uri"`
+	ID          uint   `json:"id"`
+}
+
+// ipRangesHandler handles requests for '/api/<version>/ipranges/'.
+func ipRangesHandler(server *TestServer, w http.ResponseWriter, r *http.Request) {
+	ipRangesURLRE := regexp.MustCompile(`/ipranges/(\d+)/`)
+	ipRangesURLMatch := ipRangesURLRE.FindStringSubmatch(r.URL.Path)
+	ipRangesURL := getIPRangesEndpoint(server.version)
+
+	var ipRange *TestIPRange
+	if ipRangesURLMatch != nil {
+		ID, err := strconv.Atoi(ipRangesURLMatch[1])
+		checkError(err)
+		ipRange = server.ipRanges[uint(ID)]
+	}
+	if ipRange == nil && r.URL.Path != ipRangesURL {
+		http.NotFoundHandler().ServeHTTP(w, r)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		if ipRange != nil {
+			PrettyJsonWriter(ipRange, w)
+			return
+		}
+		ipRanges := []*TestIPRange{}
+		for i := uint(1); i < server.nextIPRange; i++ {
+			if ipRange, ok := server.ipRanges[i]; ok {
+				ipRanges = append(ipRanges, ipRange)
+			}
+		}
+		PrettyJsonWriter(ipRanges, w)
+	case "POST":
+		if ipRange != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		newRange := &TestIPRange{}
+		if err := server.setIPRangeValues(newRange, parseRequestValues(r)); err != nil {
+			badRequestError(w, err)
+			return
+		}
+		newRange.ID = server.nextIPRange
+		newRange.ResourceURI = fmt.Sprintf("%s%d/", ipRangesURL, newRange.ID)
+		server.ipRanges[newRange.ID] = newRange
+		server.nextIPRange++
+		PrettyJsonWriter(newRange, w)
+	case "PUT":
+		if ipRange == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		updated := *ipRange
+		if err := server.setIPRangeValues(&updated, parseRequestValues(r)); err != nil {
+			badRequestError(w, err)
+			return
+		}
+		*ipRange = updated
+		PrettyJsonWriter(ipRange, w)
+	case "DELETE":
+		if ipRange == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		delete(server.ipRanges, ipRange.ID)
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// setIPRangeValues updates the IP range with the form values posted by the
+// Controller. If no subnet is specified for a new range, the subnet that
+// contains the start of the range is used.
+func (server *TestServer) setIPRangeValues(ipRange *TestIPRange, values url.Values) error {
+	if rangeType, ok := getValue(values, "type"); ok {
+		ipRange.Type = rangeType
+	}
+	if startIP, ok := getValue(values, "start_ip"); ok {
+		ipRange.StartIP = startIP
+	}
+	if endIP, ok := getValue(values, "end_ip"); ok {
+		ipRange.EndIP = endIP
+	}
+	if comment, ok := getValue(values, "comment"); ok {
+		ipRange.Comment = comment
+	}
+	switch ipRange.Type {
+	case "dynamic", "reserved":
+	default:
+		return errors.NotValidf("type %q", ipRange.Type)
+	}
+	start, end := net.ParseIP(ipRange.StartIP), net.ParseIP(ipRange.EndIP)
+	if start == nil {
+		return errors.NotValidf("start_ip %q", ipRange.StartIP)
+	}
+	if end == nil {
+		return errors.NotValidf("end_ip %q", ipRange.EndIP)
+	}
+
+	if subnetID, ok := getValue(values, "subnet"); ok {
+		ID, err := strconv.Atoi(subnetID)
+		if err != nil {
+			return errors.NotValidf("subnet %q", subnetID)
+		}
+		subnet, ok := server.subnets[uint(ID)]
+		if !ok {
+			return errors.NotFoundf("subnet %d", ID)
+		}
+		ipRange.Subnet = subnet
+	} else if ipRange.Subnet.ID == 0 {
+		for i := uint(1); i < server.nextSubnet; i++ {
+			subnet, ok := server.subnets[i]
+			if !ok {
+				continue
+			}
+			if _, ipNet, err := net.ParseCIDR(subnet.CIDR); err == nil && ipNet.Contains(start) {
+				ipRange.Subnet = subnet
+				break
+			}
+		}
+		if ipRange.Subnet.ID == 0 {
+			return errors.NotFoundf("subnet for %s", ipRange.StartIP)
+		}
+	}
+
+	_, ipNet, err := net.ParseCIDR(ipRange.Subnet.CIDR)
+	checkError(err)
+	if !ipNet.Contains(start) || !ipNet.Contains(end) {
+		return errors.NotValidf("range %s-%s in subnet %s", ipRange.StartIP, ipRange.EndIP, ipRange.Subnet.CIDR)
+	}
+	return nil
+}
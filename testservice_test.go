@@ -869,6 +869,188 @@ func (suite *TestServerSuite) TestSubnetDelete(c *C) {
 	c.Check(resp.StatusCode, Equals, http.StatusNotFound)
 }
 
+func (suite *TestServerSuite) putForm(c *C, url string, values url.Values) *http.Response {
+	req, err := http.NewRequest("PUT", url, strings.NewReader(values.Encode()))
+	c.Assert(err, IsNil)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, IsNil)
+	return resp
+}
+
+func (suite *TestServerSuite) TestSubnetPostForm(c *C) {
+	resp, err := http.PostForm(suite.subnetsURL(), url.Values{
+		"cidr":        {"10.0.0.0/24"},
+		"gateway_ip":  {"10.0.0.1"},
+		"dns_servers": {"10.0.0.2,10.0.0.3"},
+		"managed":     {"false"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	var subnet TestSubnet
+	err = json.NewDecoder(resp.Body).Decode(&subnet)
+	c.Assert(err, IsNil)
+	c.Check(subnet.ID, Equals, uint(1))
+	c.Check(subnet.Name, Equals, "10.0.0.0/24")
+	c.Check(subnet.ResourceURI, Equals, getSubnetsEndpoint(suite.server.version)+"1/")
+	c.Check(subnet.GatewayIP, Equals, "10.0.0.1")
+	c.Check(subnet.DNSServers, DeepEquals, []string{"10.0.0.2", "10.0.0.3"})
+	c.Check(subnet.AllowProxy, Equals, true)
+	c.Check(subnet.Managed, Equals, false)
+	c.Check(subnet.RDNSMode, Equals, 2)
+
+	subnets := suite.getSubnets(c)
+	c.Check(subnets, DeepEquals, []TestSubnet{subnet})
+}
+
+func (suite *TestServerSuite) TestSubnetPostFormBadRequest(c *C) {
+	resp, err := http.PostForm(suite.subnetsURL(), url.Values{"name": {"no-cidr"}})
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	resp, err = http.PostForm(suite.subnetsURL(), url.Values{"cidr": {"wat"}})
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusBadRequest)
+}
+
+func (suite *TestServerSuite) TestSubnetPutForm(c *C) {
+	suite.server.NewSubnet(subnetJSON(defaultSubnet()))
+
+	resp := suite.putForm(c, suite.subnetURL(1), url.Values{
+		"name":        {"renamed"},
+		"dns_servers": {""},
+		"rdns_mode":   {"0"},
+	})
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	subnets := suite.getSubnets(c)
+	c.Assert(subnets, HasLen, 1)
+	c.Check(subnets[0].Name, Equals, "renamed")
+	c.Check(subnets[0].CIDR, Equals, "192.168.1.0/24")
+	c.Check(subnets[0].DNSServers, DeepEquals, []string{})
+	c.Check(subnets[0].RDNSMode, Equals, 0)
+
+	resp, err := http.Get(suite.subnetsURL() + "renamed/")
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusOK)
+}
+
+func (suite *TestServerSuite) TestSubnetDeleteMissing(c *C) {
+	suite.server.NewSubnet(subnetJSON(defaultSubnet()))
+
+	req, err := http.NewRequest("DELETE", suite.subnetURL(2), nil)
+	c.Assert(err, IsNil)
+	resp, err := http.DefaultClient.Do(req)
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusNotFound)
+}
+
+func (suite *TestServerSuite) ipRangesURL() string {
+	return suite.server.Server.URL + getIPRangesEndpoint(suite.server.version)
+}
+
+func (suite *TestServerSuite) getIPRanges(c *C) []TestIPRange {
+	resp, err := http.Get(suite.ipRangesURL())
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	var ipRanges []TestIPRange
+	err = json.NewDecoder(resp.Body).Decode(&ipRanges)
+	c.Assert(err, IsNil)
+	return ipRanges
+}
+
+func (suite *TestServerSuite) TestIPRangesEmpty(c *C) {
+	c.Check(suite.getIPRanges(c), DeepEquals, []TestIPRange{})
+}
+
+func (suite *TestServerSuite) TestIPRangeCreate(c *C) {
+	suite.server.NewSubnet(subnetJSON(defaultSubnet()))
+	suite.server.NewSubnet(subnetJSON(newSubnetOnSpace("space-0", 2)))
+
+	resp, err := http.PostForm(suite.ipRangesURL(), url.Values{
+		"type":     {"dynamic"},
+		"start_ip": {"192.168.2.100"},
+		"end_ip":   {"192.168.2.200"},
+		"comment":  {"dhcp"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	var ipRange TestIPRange
+	err = json.NewDecoder(resp.Body).Decode(&ipRange)
+	c.Assert(err, IsNil)
+	c.Check(ipRange.ID, Equals, uint(1))
+	c.Check(ipRange.ResourceURI, Equals, getIPRangesEndpoint(suite.server.version)+"1/")
+	c.Check(ipRange.Type, Equals, "dynamic")
+	c.Check(ipRange.Comment, Equals, "dhcp")
+	c.Check(ipRange.Subnet.ID, Equals, uint(2))
+
+	c.Check(suite.getIPRanges(c), DeepEquals, []TestIPRange{ipRange})
+}
+
+func (suite *TestServerSuite) TestIPRangeCreateBadRequest(c *C) {
+	suite.server.NewSubnet(subnetJSON(defaultSubnet()))
+
+	for i, values := range []url.Values{{
+		"start_ip": {"192.168.1.100"},
+		"end_ip":   {"192.168.1.200"},
+	}, {
+		"type":     {"reserved"},
+		"start_ip": {"192.168.1.100"},
+	}, {
+		"type":     {"reserved"},
+		"start_ip": {"10.0.0.1"},
+		"end_ip":   {"10.0.0.2"},
+	}, {
+		"type":     {"reserved"},
+		"start_ip": {"192.168.1.100"},
+		"end_ip":   {"192.168.2.200"},
+	}} {
+		c.Logf("test %d", i)
+		resp, err := http.PostForm(suite.ipRangesURL(), values)
+		c.Assert(err, IsNil)
+		c.Check(resp.StatusCode, Equals, http.StatusBadRequest)
+	}
+	c.Check(suite.getIPRanges(c), HasLen, 0)
+}
+
+func (suite *TestServerSuite) TestIPRangeUpdateAndDelete(c *C) {
+	suite.server.NewSubnet(subnetJSON(defaultSubnet()))
+	resp, err := http.PostForm(suite.ipRangesURL(), url.Values{
+		"type":     {"reserved"},
+		"start_ip": {"192.168.1.10"},
+		"end_ip":   {"192.168.1.20"},
+		"subnet":   {"1"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	ipRangeURL := suite.ipRangesURL() + "1/"
+	resp = suite.putForm(c, ipRangeURL, url.Values{"end_ip": {"192.168.1.30"}})
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	resp = suite.putForm(c, ipRangeURL, url.Values{"type": {"wat"}})
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	ipRanges := suite.getIPRanges(c)
+	c.Assert(ipRanges, HasLen, 1)
+	c.Check(ipRanges[0].Type, Equals, "reserved")
+	c.Check(ipRanges[0].StartIP, Equals, "192.168.1.10")
+	c.Check(ipRanges[0].EndIP, Equals, "192.168.1.30")
+
+	req, err := http.NewRequest("DELETE", ipRangeURL, nil)
+	c.Assert(err, IsNil)
+	resp, err = http.DefaultClient.Do(req)
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusOK)
+	c.Check(suite.getIPRanges(c), HasLen, 0)
+
+	resp, err = http.Get(ipRangeURL)
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusNotFound)
+}
+
 func (suite *TestServerSuite) reserveSomeAddresses() map[int]bool {
 	reserved := make(map[int]bool)
 	rand.Seed(6)
@@ -1950,6 +2132,29 @@ func (suite *TestMAASObjectSuite) TestListTags(c *C) {
 	c.Assert(m, DeepEquals, expected)
 }
 
+func (suite *TestMAASObjectSuite) TestNewTag(c *C) {
+	result, err := suite.TestMAASObject.GetSubObject("tags").CallPost("new", url.Values{
+		"name":        {"virtual"},
+		"comment":     {"virtual machines"},
+		"definition":  {"//node[@class='system']/vendor = 'QEMU'"},
+		"kernel_opts": {"console=ttyS0"},
+	})
+	c.Assert(err, IsNil)
+
+	tag, err := result.GetMap()
+	c.Assert(err, IsNil)
+	for key, expected := range map[string]string{
+		"name":        "virtual",
+		"comment":     "virtual machines",
+		"definition":  "//node[@class='system']/vendor = 'QEMU'",
+		"kernel_opts": "console=ttyS0",
+	} {
+		value, err := tag[key].GetString()
+		c.Assert(err, IsNil)
+		c.Check(value, Equals, expected)
+	}
+}
+
 func (suite *TestMAASObjectSuite) TestAcquireNodeZone(c *C) {
 	suite.TestMAASObject.TestServer.AddZone("z0", "rox")
 	suite.TestMAASObject.TestServer.AddZone("z1", "sux")
@@ -2061,3 +2266,258 @@ func (suite *TestMAASObjectSuite) TestDeploymentStatus(c *C) {
 		c.Assert(nodeStatus, Equals, status)
 	}
 }
+
+func (suite *TestServerSuite) fabricVLANsURL(fabricID int) string {
+	return fmt.Sprintf("%s%s%d/vlans/", suite.server.Server.URL, getFabricsEndpoint(suite.server.version), fabricID)
+}
+
+func (suite *TestServerSuite) vlanURL(ID uint) string {
+	return fmt.Sprintf("%s%s%d/", suite.server.Server.URL, getVLANsEndpoint(suite.server.version), ID)
+}
+
+func (suite *TestServerSuite) getVLANs(c *C, url string) []TestVLAN {
+	resp, err := http.Get(url)
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	var vlans []TestVLAN
+	err = json.NewDecoder(resp.Body).Decode(&vlans)
+	c.Assert(err, IsNil)
+	return vlans
+}
+
+func (suite *TestServerSuite) TestVLANCreate(c *C) {
+	suite.server.NewVLAN(TestVLAN{Name: "untagged", Fabric: "fabric-0", MTU: 1500})
+
+	resp, err := http.PostForm(suite.fabricVLANsURL(1), url.Values{
+		"vid":  {"10"},
+		"name": {"storage"},
+		"mtu":  {"9000"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	var vlan TestVLAN
+	err = json.NewDecoder(resp.Body).Decode(&vlan)
+	c.Assert(err, IsNil)
+	c.Check(vlan.ID, Equals, uint(2))
+	c.Check(vlan.ResourceURI, Equals, getVLANsEndpoint(suite.server.version)+"2/")
+	c.Check(vlan.Fabric, Equals, "fabric-1")
+	c.Check(vlan.FabricID, Equals, uint(1))
+	c.Check(vlan.VID, Equals, uint(10))
+	c.Check(vlan.Name, Equals, "storage")
+	c.Check(vlan.MTU, Equals, 9000)
+
+	c.Check(suite.getVLANs(c, suite.fabricVLANsURL(1)), DeepEquals, []TestVLAN{vlan})
+	c.Check(suite.getVLANs(c, suite.server.Server.URL+getVLANsEndpoint(suite.server.version)), HasLen, 2)
+}
+
+func (suite *TestServerSuite) TestVLANCreateBadRequest(c *C) {
+	for i, values := range []url.Values{
+		{"name": {"no-vid"}},
+		{"vid": {"0"}},
+		{"vid": {"4095"}},
+		{"vid": {"10"}, "mtu": {"wat"}},
+	} {
+		c.Logf("test %d", i)
+		resp, err := http.PostForm(suite.fabricVLANsURL(0), values)
+		c.Assert(err, IsNil)
+		c.Check(resp.StatusCode, Equals, http.StatusBadRequest)
+	}
+
+	resp, err := http.PostForm(suite.fabricVLANsURL(0), url.Values{"vid": {"10"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	resp, err = http.PostForm(suite.fabricVLANsURL(0), url.Values{"vid": {"10"}})
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusBadRequest)
+	c.Check(suite.getVLANs(c, suite.fabricVLANsURL(0)), HasLen, 1)
+}
+
+func (suite *TestServerSuite) TestVLANUpdate(c *C) {
+	suite.server.NewSpace(spaceJSON(CreateSpace{Name: "storage"}))
+	relay := suite.server.NewVLAN(TestVLAN{Name: "untagged", Fabric: "fabric-0", MTU: 1500})
+	vlan := suite.server.NewVLAN(TestVLAN{Name: "tagged", Fabric: "fabric-0", VID: 10, MTU: 1500})
+
+	resp := suite.putForm(c, suite.vlanURL(vlan.ID), url.Values{"dhcp_on": {"true"}})
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	resp = suite.putForm(c, suite.vlanURL(vlan.ID), url.Values{
+		"mtu":            {"9000"},
+		"dhcp_on":        {"true"},
+		"primary_rack":   {"4y3h7n"},
+		"secondary_rack": {"xyz123"},
+		"relay_vlan":     {fmt.Sprint(relay.ID)},
+		"space":          {"storage"},
+	})
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	resp, err := http.Get(suite.vlanURL(vlan.ID))
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	var updated TestVLAN
+	err = json.NewDecoder(resp.Body).Decode(&updated)
+	c.Assert(err, IsNil)
+	c.Check(updated.MTU, Equals, 9000)
+	c.Check(updated.DHCPOn, Equals, true)
+	c.Check(updated.PrimaryRack, Equals, "4y3h7n")
+	c.Check(updated.SecondaryRack, Equals, "xyz123")
+	c.Assert(updated.RelayVLAN, NotNil)
+	c.Check(*updated.RelayVLAN, Equals, relay.ID)
+	c.Check(updated.Space, Equals, "storage")
+
+	resp = suite.putForm(c, suite.vlanURL(vlan.ID), url.Values{
+		"dhcp_on":        {"false"},
+		"primary_rack":   {""},
+		"secondary_rack": {""},
+		"relay_vlan":     {""},
+	})
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	resp, err = http.Get(suite.vlanURL(vlan.ID))
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	updated = TestVLAN{}
+	err = json.NewDecoder(resp.Body).Decode(&updated)
+	c.Assert(err, IsNil)
+	c.Check(updated.PrimaryRack, Equals, "")
+	c.Check(updated.SecondaryRack, Equals, "")
+	c.Check(updated.RelayVLAN, IsNil)
+}
+
+func (suite *TestServerSuite) TestVLANDelete(c *C) {
+	untagged := suite.server.NewVLAN(TestVLAN{Name: "untagged", Fabric: "fabric-0", MTU: 1500})
+	tagged := suite.server.NewVLAN(TestVLAN{Name: "tagged", Fabric: "fabric-0", VID: 10, MTU: 1500})
+
+	for _, test := range []struct {
+		ID     uint
+		status int
+	}{
+		{untagged.ID, http.StatusBadRequest},
+		{tagged.ID, http.StatusOK},
+		{tagged.ID, http.StatusNotFound},
+	} {
+		req, err := http.NewRequest("DELETE", suite.vlanURL(test.ID), nil)
+		c.Assert(err, IsNil)
+		resp, err := http.DefaultClient.Do(req)
+		c.Assert(err, IsNil)
+		c.Check(resp.StatusCode, Equals, test.status)
+	}
+	c.Check(suite.getVLANs(c, suite.fabricVLANsURL(0)), DeepEquals, []TestVLAN{untagged})
+}
+
+func (suite *TestServerSuite) spaceURL(ID uint) string {
+	return fmt.Sprintf("%s%s%d/", suite.server.Server.URL, getSpacesEndpoint(suite.server.version), ID)
+}
+
+func (suite *TestServerSuite) getSpaces(c *C) []TestSpace {
+	resp, err := http.Get(suite.server.Server.URL + getSpacesEndpoint(suite.server.version))
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	var spaces []TestSpace
+	err = json.NewDecoder(resp.Body).Decode(&spaces)
+	c.Assert(err, IsNil)
+	return spaces
+}
+
+func (suite *TestServerSuite) TestSpacePostForm(c *C) {
+	spacesURL := suite.server.Server.URL + getSpacesEndpoint(suite.server.version)
+	resp, err := http.PostForm(spacesURL, url.Values{
+		"name":        {"storage"},
+		"description": {"storage network"},
+	})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	var space TestSpace
+	err = json.NewDecoder(resp.Body).Decode(&space)
+	c.Assert(err, IsNil)
+	c.Check(space.ID, Equals, uint(1))
+	c.Check(space.Name, Equals, "storage")
+	c.Check(space.Description, Equals, "storage network")
+	c.Check(space.Subnets, DeepEquals, []TestSubnet{})
+
+	resp, err = http.PostForm(spacesURL, url.Values{"name": {"storage"}})
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	resp, err = http.PostForm(spacesURL, url.Values{"description": {"unnamed"}})
+	c.Assert(err, IsNil)
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	err = json.NewDecoder(resp.Body).Decode(&space)
+	c.Assert(err, IsNil)
+	c.Check(space.Name, Equals, "space-2")
+}
+
+func (suite *TestServerSuite) TestSpacePutForm(c *C) {
+	suite.server.NewSpace(spaceJSON(CreateSpace{Name: "foo"}))
+	suite.server.NewSpace(spaceJSON(CreateSpace{Name: "bar"}))
+	suite.server.NewSubnet(subnetJSON(newSubnetOnSpace("foo", 1)))
+
+	resp := suite.putForm(c, suite.spaceURL(1), url.Values{"name": {"bar"}})
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	resp = suite.putForm(c, suite.spaceURL(1), url.Values{
+		"name":        {"renamed"},
+		"description": {"moved"},
+	})
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+
+	spaces := suite.getSpaces(c)
+	c.Assert(spaces, HasLen, 2)
+	c.Check(spaces[0].Name, Equals, "renamed")
+	c.Check(spaces[0].Description, Equals, "moved")
+	c.Assert(spaces[0].Subnets, HasLen, 1)
+	c.Check(spaces[0].Subnets[0].Space, Equals, "renamed")
+
+	resp, err := http.Get(suite.server.Server.URL + getSpacesEndpoint(suite.server.version) + "renamed/")
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusOK)
+}
+
+func (suite *TestServerSuite) TestSpaceDelete(c *C) {
+	suite.server.NewSpace(spaceJSON(CreateSpace{Name: "foo"}))
+	suite.server.NewSpace(spaceJSON(CreateSpace{Name: "bar"}))
+	suite.server.NewSubnet(subnetJSON(newSubnetOnSpace("foo", 1)))
+
+	for _, status := range []int{http.StatusOK, http.StatusNotFound} {
+		req, err := http.NewRequest("DELETE", suite.spaceURL(1), nil)
+		c.Assert(err, IsNil)
+		resp, err := http.DefaultClient.Do(req)
+		c.Assert(err, IsNil)
+		c.Check(resp.StatusCode, Equals, status)
+	}
+
+	spaces := suite.getSpaces(c)
+	c.Assert(spaces, HasLen, 1)
+	c.Check(spaces[0].Name, Equals, "bar")
+	c.Check(suite.getSubnets(c)[0].Space, Equals, "")
+
+	resp, err := http.Get(suite.spaceURL(2))
+	c.Assert(err, IsNil)
+	c.Check(resp.StatusCode, Equals, http.StatusOK)
+}
+
+func (suite *TestServerSuite) TestVLANSetSpace(c *C) {
+	space := suite.server.NewSpace(spaceJSON(CreateSpace{Name: "storage"}))
+	vlan := suite.server.NewVLAN(TestVLAN{Name: "tagged", Fabric: "fabric-0", VID: 10, MTU: 1500})
+	subnet := suite.server.NewSubnet(subnetJSON(defaultSubnet()))
+	subnet.VLAN = vlan
+	suite.server.subnets[subnet.ID] = *subnet
+
+	resp := suite.putForm(c, suite.vlanURL(vlan.ID), url.Values{"space": {"99"}})
+	c.Assert(resp.StatusCode, Equals, http.StatusBadRequest)
+
+	resp = suite.putForm(c, suite.vlanURL(vlan.ID), url.Values{"space": {fmt.Sprint(space.ID)}})
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Check(suite.server.vlans[int(vlan.ID)].Space, Equals, "storage")
+	c.Check(suite.getSubnets(c)[0].Space, Equals, "storage")
+	spaces := suite.getSpaces(c)
+	c.Assert(spaces, HasLen, 1)
+	c.Check(spaces[0].Subnets, HasLen, 1)
+
+	resp = suite.putForm(c, suite.vlanURL(vlan.ID), url.Values{"space": {""}})
+	c.Assert(resp.StatusCode, Equals, http.StatusOK)
+	c.Check(suite.server.vlans[int(vlan.ID)].Space, Equals, "")
+	c.Check(suite.getSubnets(c)[0].Space, Equals, "")
+}
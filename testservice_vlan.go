@@ -6,17 +6,34 @@ package gomaasapi
 import (
 	"fmt"
 	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/juju/errors"
 )
 
 func getVLANsEndpoint(version string) string {
 	return fmt.Sprintf("/api/%s/vlans/", version)
 }
 
+func getFabricsEndpoint(version string) string {
+	return fmt.Sprintf("/api/%s/fabrics/", version)
+}
+
 // TestVLAN is the MAAS API VLAN representation
 type TestVLAN struct {
-	Name   string `json:"name"`
-	Fabric string `json:"fabric"`
-	VID    uint   `json:"vid"`
+	Name     string `json:"name"`
+	Fabric   string `json:"fabric"`
+	FabricID uint   `json:"fabric_id"`
+	VID      uint   `json:"vid"`
+	MTU      int    `json:"mtu"`
+
+	DHCPOn        bool   `json:"dhcp_on"`
+	PrimaryRack   string `json:"primary_rack"`
+	SecondaryRack string `json:"secondary_rack"`
+	RelayVLAN     *uint  `json:"relay_vlan"`
+	Space         string `json:"space"`
 
 	ResourceURI string `json:"resource_uri"`
 	ID          uint   `json:"id"`
@@ -28,6 +45,198 @@ type PostedVLAN struct {
 	VID  uint   `json:"vid"`
 }
 
+// vlansHandler handles requests for '/api/<version>/vlans/' and for the
+// VLANs of a fabric at '/api/<version>/fabrics/<fabric>/vlans/'. The test
+// server doesn't model fabrics themselves, so new VLANs are created in a
+// fabric named after its ID.
 func vlansHandler(server *TestServer, w http.ResponseWriter, r *http.Request) {
-	//TODO
+	vlansURL := getVLANsEndpoint(server.version)
+	vlanURLRE := regexp.MustCompile(fmt.Sprintf(`^%s(\d+)/$`, regexp.QuoteMeta(vlansURL)))
+	fabricVLANsURLRE := regexp.MustCompile(fmt.Sprintf(`^%s(\d+)/vlans/$`, regexp.QuoteMeta(getFabricsEndpoint(server.version))))
+
+	if match := fabricVLANsURLRE.FindStringSubmatch(r.URL.Path); match != nil {
+		fabricID, err := strconv.Atoi(match[1])
+		checkError(err)
+		fabricVLANsHandler(server, w, r, uint(fabricID))
+		return
+	}
+
+	var vlan TestVLAN
+	if match := vlanURLRE.FindStringSubmatch(r.URL.Path); match != nil {
+		ID, err := strconv.Atoi(match[1])
+		checkError(err)
+		var ok bool
+		if vlan, ok = server.vlans[ID]; !ok {
+			http.NotFoundHandler().ServeHTTP(w, r)
+			return
+		}
+	} else if r.URL.Path != vlansURL {
+		http.NotFoundHandler().ServeHTTP(w, r)
+		return
+	}
+
+	switch r.Method {
+	case "GET":
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		if vlan.ID != 0 {
+			PrettyJsonWriter(vlan, w)
+			return
+		}
+		PrettyJsonWriter(server.vlansInFabric(nil), w)
+	case "PUT":
+		if vlan.ID == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if err := server.setVLANValues(&vlan, parseRequestValues(r)); err != nil {
+			badRequestError(w, err)
+			return
+		}
+		server.vlans[int(vlan.ID)] = vlan
+		// The subnets on a VLAN are always in the space of the VLAN.
+		for ID, subnet := range server.subnets {
+			if subnet.VLAN.ID == vlan.ID {
+				subnet.VLAN = vlan
+				subnet.Space = vlan.Space
+				server.subnets[ID] = subnet
+			}
+		}
+		PrettyJsonWriter(vlan, w)
+	case "DELETE":
+		if vlan.ID == 0 {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if vlan.VID == 0 {
+			badRequestError(w, errors.New("the default VLAN of a fabric cannot be deleted"))
+			return
+		}
+		delete(server.vlans, int(vlan.ID))
+		w.WriteHeader(http.StatusOK)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// fabricVLANsHandler handles listing and creating the VLANs of a fabric.
+func fabricVLANsHandler(server *TestServer, w http.ResponseWriter, r *http.Request, fabricID uint) {
+	switch r.Method {
+	case "GET":
+		w.Header().Set("Content-Type", "application/vnd.api+json")
+		PrettyJsonWriter(server.vlansInFabric(&fabricID), w)
+	case "POST":
+		values := parseRequestValues(r)
+		vidValue, ok := getValue(values, "vid")
+		if !ok {
+			badRequestError(w, errors.New("vid is required"))
+			return
+		}
+		vid, err := strconv.Atoi(vidValue)
+		if err != nil || vid < 1 || vid > 4094 {
+			badRequestError(w, errors.NotValidf("vid %q", vidValue))
+			return
+		}
+		for _, existing := range server.vlansInFabric(&fabricID) {
+			if existing.VID == uint(vid) {
+				badRequestError(w, errors.AlreadyExistsf("vlan %d in fabric %d", vid, fabricID))
+				return
+			}
+		}
+		vlan := TestVLAN{
+			Fabric:   fmt.Sprintf("fabric-%d", fabricID),
+			FabricID: fabricID,
+			VID:      uint(vid),
+			MTU:      1500,
+		}
+		if err := server.setVLANValues(&vlan, values); err != nil {
+			badRequestError(w, err)
+			return
+		}
+		PrettyJsonWriter(server.NewVLAN(vlan), w)
+	default:
+		w.WriteHeader(http.StatusBadRequest)
+	}
+}
+
+// NewVLAN adds the VLAN to the test server, and returns it with its ID and
+// resource URI set.
+func (server *TestServer) NewVLAN(vlan TestVLAN) TestVLAN {
+	vlan.ID = uint(server.nextVLAN)
+	vlan.ResourceURI = fmt.Sprintf("%s%d/", getVLANsEndpoint(server.version), vlan.ID)
+	server.vlans[server.nextVLAN] = vlan
+	server.nextVLAN++
+	return vlan
+}
+
+// vlansInFabric returns the VLANs in the fabric with the given ID, or all
+// VLANs if fabricID is nil, ordered by ID.
+func (server *TestServer) vlansInFabric(fabricID *uint) []TestVLAN {
+	vlans := []TestVLAN{}
+	for i := 1; i < server.nextVLAN; i++ {
+		vlan, ok := server.vlans[i]
+		if ok && (fabricID == nil || vlan.FabricID == *fabricID) {
+			vlans = append(vlans, vlan)
+		}
+	}
+	return vlans
+}
+
+// setVLANValues updates the VLAN with the form values posted by the
+// Controller.
+func (server *TestServer) setVLANValues(vlan *TestVLAN, values url.Values) error {
+	if name, ok := getValue(values, "name"); ok {
+		vlan.Name = name
+	}
+	if value, ok := getValue(values, "mtu"); ok {
+		mtu, err := strconv.Atoi(value)
+		if err != nil || mtu < 552 || mtu > 65535 {
+			return errors.NotValidf("mtu %q", value)
+		}
+		vlan.MTU = mtu
+	}
+	// Empty values remove the racks and the relay VLAN.
+	if _, ok := values["primary_rack"]; ok {
+		vlan.PrimaryRack = values.Get("primary_rack")
+	}
+	if _, ok := values["secondary_rack"]; ok {
+		vlan.SecondaryRack = values.Get("secondary_rack")
+	}
+	if value, ok := getValue(values, "dhcp_on"); ok {
+		dhcpOn, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.NotValidf("dhcp_on %q", value)
+		}
+		vlan.DHCPOn = dhcpOn
+	}
+	if _, ok := values["relay_vlan"]; ok && values.Get("relay_vlan") == "" {
+		vlan.RelayVLAN = nil
+	} else if value, ok := getValue(values, "relay_vlan"); ok {
+		ID, err := strconv.Atoi(value)
+		if err != nil {
+			return errors.NotValidf("relay_vlan %q", value)
+		}
+		if _, ok := server.vlans[ID]; !ok || uint(ID) == vlan.ID {
+			return errors.NotValidf("relay_vlan %d", ID)
+		}
+		relay := uint(ID)
+		vlan.RelayVLAN = &relay
+	}
+	if _, ok := values["space"]; ok {
+		// An empty space removes the VLAN from its space.
+		vlan.Space = ""
+		if nameOrID := values.Get("space"); nameOrID != "" {
+			space, err := server.spaceName(nameOrID)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			vlan.Space = space
+		}
+	}
+	if vlan.DHCPOn && vlan.PrimaryRack == "" {
+		return errors.New("dhcp can only be turned on when a primary rack controller is set")
+	}
+	if vlan.SecondaryRack != "" && vlan.SecondaryRack == vlan.PrimaryRack {
+		return errors.New("primary rack and secondary rack must be different")
+	}
+	return nil
 }
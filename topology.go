@@ -0,0 +1,349 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// Topology is the graph of the switches that machines are connected to, as
+// their LLDP neighbors were found when they were commissioned, along with
+// the places where the graph doesn't match the interfaces in MAAS.
+type Topology struct {
+	// Switches are ordered by chassis ID.
+	Switches []TopologySwitch
+	Findings []TopologyFinding
+}
+
+// TopologySwitch is a switch, or any other LLDP neighbor, that machines are
+// connected to.
+type TopologySwitch struct {
+	ChassisID           string
+	ChassisIDType       string
+	Name                string
+	Description         string
+	ManagementAddresses []string
+
+	// Ports are ordered by port ID.
+	Ports []TopologyPort
+}
+
+// TopologyPort is a port of a switch, and the machine interfaces that are
+// connected to it.
+type TopologyPort struct {
+	ID          string
+	Description string
+	VLANs       []LLDPVLAN
+
+	Interfaces []TopologyInterface
+}
+
+// TopologyInterface is a physical interface of a machine.
+type TopologyInterface struct {
+	Machine   Machine
+	Interface Interface
+}
+
+// TopologyFindingType is the kind of problem that a TopologyFinding is
+// about.
+type TopologyFindingType string
+
+const (
+	// FindingNoNeighbor is an enabled physical interface that didn't find
+	// a switch.
+	FindingNoNeighbor TopologyFindingType = "no-neighbor"
+	// FindingUnknownInterface is an LLDP neighbor on an interface that
+	// MAAS doesn't know about.
+	FindingUnknownInterface TopologyFindingType = "unknown-interface"
+	// FindingBondSingleSwitch is a bond whose interfaces are all
+	// connected to the same switch.
+	FindingBondSingleSwitch TopologyFindingType = "bond-single-switch"
+	// FindingUntaggedVLANMismatch is an interface whose VLAN isn't the
+	// untagged VLAN of the switch port.
+	FindingUntaggedVLANMismatch TopologyFindingType = "untagged-vlan-mismatch"
+	// FindingVLANNotTrunked is a VLAN interface whose VLAN isn't one of the
+	// VLANs of the switch port.
+	FindingVLANNotTrunked TopologyFindingType = "vlan-not-trunked"
+)
+
+// TopologyFinding is a place where the switch topology doesn't match the
+// interfaces of a machine.
+type TopologyFinding struct {
+	Type     TopologyFindingType
+	SystemID string
+	Hostname string
+	// Interface is the name of the interface in MAAS, or the name in the
+	// commissioning environment for FindingUnknownInterface.
+	Interface string
+	// ChassisID and PortID are empty for FindingNoNeighbor.
+	ChassisID string
+	PortID    string
+	Message   string
+}
+
+// MapTopology maps the physical interfaces of the machines that match the
+// args to the switch ports that they are connected to, and checks that
+// bonds span more than one switch and that the VLANs of the interfaces are
+// the VLANs of their switch ports. The VLANs are only checked for switches
+// that report them.
+func MapTopology(controller Controller, args MachinesArgs) (Topology, error) {
+	machines, err := controller.Machines(args)
+	if err != nil {
+		return Topology{}, errors.Trace(err)
+	}
+	details := make([]MachineDetails, len(machines))
+	for i, m := range machines {
+		details[i], err = m.Details()
+		if err != nil {
+			return Topology{}, errors.Annotatef(err, "machine %s", m.SystemID())
+		}
+	}
+	return buildTopology(machines, details), nil
+}
+
+type topologyPortKey struct {
+	chassisID string
+	portID    string
+}
+
+type topologyBuilder struct {
+	switches map[string]*TopologySwitch
+	ports    map[topologyPortKey]*TopologyPort
+	findings []TopologyFinding
+}
+
+// buildTopology expects the details to be in the same order as the
+// machines.
+func buildTopology(machines []Machine, details []MachineDetails) Topology {
+	b := &topologyBuilder{
+		switches: make(map[string]*TopologySwitch),
+		ports:    make(map[topologyPortKey]*TopologyPort),
+	}
+	for i, m := range machines {
+		b.addMachine(m, details[i])
+	}
+	return b.topology()
+}
+
+func (b *topologyBuilder) addMachine(m Machine, details MachineDetails) {
+	interfaces := make(map[string]Interface)
+	for _, iface := range m.InterfaceSet() {
+		interfaces[iface.Name()] = iface
+	}
+	finding := func(findingType TopologyFindingType, name string, key topologyPortKey, format string, args ...interface{}) {
+		b.findings = append(b.findings, TopologyFinding{
+			Type:      findingType,
+			SystemID:  m.SystemID(),
+			Hostname:  m.Hostname(),
+			Interface: name,
+			ChassisID: key.chassisID,
+			PortID:    key.portID,
+			Message:   fmt.Sprintf(format, args...),
+		})
+	}
+
+	// The LLDP neighbors are found by interface name in the commissioning
+	// environment, which may have been renamed in MAAS since, so they are
+	// matched by MAC address where lshw found one.
+	macAddresses := make(map[string]string)
+	for _, nic := range details.Hardware.NICs {
+		macAddresses[nic.Name] = strings.ToLower(nic.MACAddress)
+	}
+	ports := make(map[string]topologyPortKey)
+	for _, neighbor := range details.LLDPNeighbors {
+		key := topologyPortKey{chassisID: neighbor.ChassisID, portID: neighbor.PortID}
+		iface := matchNeighborInterface(m.InterfaceSet(), neighbor.Interface, macAddresses[neighbor.Interface])
+		if iface == nil {
+			finding(FindingUnknownInterface, neighbor.Interface, key,
+				"interface %s is connected to %s but isn't known to MAAS", neighbor.Interface, describePort(key))
+			continue
+		}
+		ports[iface.Name()] = key
+		port := b.port(neighbor)
+		port.Interfaces = append(port.Interfaces, TopologyInterface{Machine: m, Interface: iface})
+	}
+
+	for _, iface := range m.InterfaceSet() {
+		if iface.Type() == "physical" && iface.Enabled() {
+			if _, ok := ports[iface.Name()]; !ok {
+				finding(FindingNoNeighbor, iface.Name(), topologyPortKey{},
+					"interface %s isn't connected to a switch", iface.Name())
+			}
+		}
+
+		physical := physicalInterfaces(iface, interfaces)
+		if iface.Type() == "bond" {
+			var chassisIDs []string
+			for _, p := range physical {
+				if key, ok := ports[p.Name()]; ok {
+					chassisIDs = append(chassisIDs, key.chassisID)
+				}
+			}
+			if len(chassisIDs) > 1 && sameStrings(chassisIDs) {
+				finding(FindingBondSingleSwitch, iface.Name(), topologyPortKey{chassisID: chassisIDs[0]},
+					"bond %s only spans switch %s", iface.Name(), chassisIDs[0])
+			}
+		}
+
+		vlan := iface.VLAN()
+		if vlan == nil || !carriesVLAN(iface, interfaces) {
+			continue
+		}
+		for _, p := range physical {
+			key, ok := ports[p.Name()]
+			if !ok {
+				continue
+			}
+			port := b.ports[key]
+			if len(port.VLANs) == 0 {
+				continue
+			}
+			if iface.Type() == "vlan" {
+				if !portHasVLAN(port, vlan.VID()) {
+					finding(FindingVLANNotTrunked, iface.Name(), key,
+						"VLAN %d of interface %s isn't trunked on %s", vlan.VID(), iface.Name(), describePort(key))
+				}
+				continue
+			}
+			// MAAS uses VID 0 for the untagged VLAN of a fabric, which
+			// could be any VLAN on the switch.
+			if vlan.VID() == 0 {
+				continue
+			}
+			if pvid, ok := portUntaggedVLAN(port); ok && pvid != vlan.VID() {
+				finding(FindingUntaggedVLANMismatch, iface.Name(), key,
+					"interface %s is on VLAN %d but the untagged VLAN of %s is %d", iface.Name(), vlan.VID(), describePort(key), pvid)
+			}
+		}
+	}
+}
+
+// port returns the port of the neighbor, adding it and its switch to the
+// topology the first time that they are found.
+func (b *topologyBuilder) port(neighbor LLDPNeighbor) *TopologyPort {
+	sw, ok := b.switches[neighbor.ChassisID]
+	if !ok {
+		sw = &TopologySwitch{
+			ChassisID:           neighbor.ChassisID,
+			ChassisIDType:       neighbor.ChassisIDType,
+			Name:                neighbor.SystemName,
+			Description:         neighbor.SystemDescription,
+			ManagementAddresses: neighbor.ManagementAddresses,
+		}
+		b.switches[neighbor.ChassisID] = sw
+	}
+	key := topologyPortKey{chassisID: neighbor.ChassisID, portID: neighbor.PortID}
+	port, ok := b.ports[key]
+	if !ok {
+		port = &TopologyPort{
+			ID:          neighbor.PortID,
+			Description: neighbor.PortDescription,
+			VLANs:       neighbor.VLANs,
+		}
+		b.ports[key] = port
+	}
+	return port
+}
+
+func (b *topologyBuilder) topology() Topology {
+	result := Topology{Findings: b.findings}
+	for _, sw := range b.switches {
+		for key, port := range b.ports {
+			if key.chassisID == sw.ChassisID {
+				sw.Ports = append(sw.Ports, *port)
+			}
+		}
+		sort.Slice(sw.Ports, func(i, j int) bool {
+			return sw.Ports[i].ID < sw.Ports[j].ID
+		})
+		result.Switches = append(result.Switches, *sw)
+	}
+	sort.Slice(result.Switches, func(i, j int) bool {
+		return result.Switches[i].ChassisID < result.Switches[j].ChassisID
+	})
+	return result
+}
+
+// matchNeighborInterface returns the physical interface with the MAC
+// address, or else with the name, if there is one.
+func matchNeighborInterface(interfaces []Interface, name, macAddress string) Interface {
+	var byName Interface
+	for _, iface := range interfaces {
+		if iface.Type() != "physical" {
+			continue
+		}
+		if macAddress != "" && strings.ToLower(iface.MACAddress()) == macAddress {
+			return iface
+		}
+		if iface.Name() == name {
+			byName = iface
+		}
+	}
+	return byName
+}
+
+// physicalInterfaces returns the physical interfaces that the interface is
+// made of, following the parents of bonds, bridges and VLANs.
+func physicalInterfaces(iface Interface, interfaces map[string]Interface) []Interface {
+	if iface.Type() == "physical" {
+		return []Interface{iface}
+	}
+	var result []Interface
+	for _, name := range iface.Parents() {
+		if parent, ok := interfaces[name]; ok {
+			result = append(result, physicalInterfaces(parent, interfaces)...)
+		}
+	}
+	return result
+}
+
+// carriesVLAN returns false for interfaces that are part of a bond or a
+// bridge, as their VLAN is the VLAN of the bond or bridge, which is checked
+// instead.
+func carriesVLAN(iface Interface, interfaces map[string]Interface) bool {
+	if iface.Type() == "vlan" {
+		return true
+	}
+	for _, name := range iface.Children() {
+		if child, ok := interfaces[name]; ok && child.Type() != "vlan" {
+			return false
+		}
+	}
+	return true
+}
+
+func portHasVLAN(port *TopologyPort, vid int) bool {
+	for _, vlan := range port.VLANs {
+		if vlan.ID == vid {
+			return true
+		}
+	}
+	return false
+}
+
+func portUntaggedVLAN(port *TopologyPort) (int, bool) {
+	for _, vlan := range port.VLANs {
+		if vlan.PVID {
+			return vlan.ID, true
+		}
+	}
+	return 0, false
+}
+
+func sameStrings(values []string) bool {
+	for _, value := range values {
+		if value != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+func describePort(key topologyPortKey) string {
+	return fmt.Sprintf("port %s of switch %s", key.portID, key.chassisID)
+}
@@ -0,0 +1,204 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+)
+
+type topologySuite struct {
+	testing.LoggingCleanupSuite
+}
+
+var _ = gc.Suite(&topologySuite{})
+
+func topologyInterface(id int, name, ifaceType, macAddress string, vid int, parents, children []string) map[string]interface{} {
+	return map[string]interface{}{
+		"resource_uri":  "/MAAS/api/2.0/nodes/4y3ha3/interfaces/" + name + "/",
+		"id":            id,
+		"name":          name,
+		"type":          ifaceType,
+		"enabled":       true,
+		"tags":          []string{},
+		"mac_address":   macAddress,
+		"effective_mtu": 1500,
+		"links":         []interface{}{},
+		"parents":       parents,
+		"children":      children,
+		"params":        "",
+		"vlan": map[string]interface{}{
+			"resource_uri":   "/MAAS/api/2.0/vlans/5001/",
+			"id":             5000 + vid,
+			"name":           "untagged",
+			"fabric":         "fabric-0",
+			"vid":            vid,
+			"mtu":            1500,
+			"dhcp_on":        false,
+			"external_dhcp":  nil,
+			"primary_rack":   nil,
+			"secondary_rack": nil,
+		},
+	}
+}
+
+// topologyMachineResponse is a machine with eno1 and eno2 in a bond that is
+// on VLAN 100 and has a VLAN 200 interface, eno3 on VLAN 30, and eno4 that
+// isn't connected.
+func topologyMachineResponse(c *gc.C) string {
+	return updateJSONMap(c, machineResponse, map[string]interface{}{
+		"interface_set": []interface{}{
+			topologyInterface(1, "eno1", "physical", "24:6E:96:A1:B2:C0", 100, []string{}, []string{"bond0"}),
+			topologyInterface(2, "eno2", "physical", "24:6e:96:a1:b2:c2", 100, []string{}, []string{"bond0"}),
+			topologyInterface(3, "bond0", "bond", "24:6e:96:a1:b2:c0", 100, []string{"eno1", "eno2"}, []string{"bond0.200"}),
+			topologyInterface(4, "bond0.200", "vlan", "24:6e:96:a1:b2:c0", 200, []string{"bond0"}, []string{}),
+			topologyInterface(5, "eno3", "physical", "24:6e:96:a1:b2:c4", 30, []string{}, []string{}),
+			topologyInterface(6, "eno4", "physical", "24:6e:96:a1:b2:c6", 0, []string{}, []string{}),
+		},
+	})
+}
+
+// The lshw details name the third interface ens3, which was renamed to eno3
+// in MAAS.
+const topologyLSHWDetails = `<?xml version="1.0" standalone="yes" ?>
+<node id="r640-07" class="system">
+  <node id="network:0" class="network">
+    <logicalname>eno1</logicalname>
+    <serial>24:6e:96:a1:b2:c0</serial>
+  </node>
+  <node id="network:1" class="network">
+    <logicalname>eno2</logicalname>
+    <serial>24:6e:96:a1:b2:c2</serial>
+  </node>
+  <node id="network:2" class="network">
+    <logicalname>ens3</logicalname>
+    <serial>24:6e:96:a1:b2:c4</serial>
+  </node>
+</node>
+`
+
+const topologyLLDPDetails = `<?xml version="1.0" encoding="UTF-8"?>
+<lldp label="LLDP neighbors">
+ <interface label="Interface" name="eno1">
+  <chassis label="Chassis">
+   <id label="ChassisID" type="mac">00:1c:73:aa:bb:cc</id>
+   <name label="SysName">leaf-01</name>
+  </chassis>
+  <port label="Port">
+   <id label="PortID" type="ifname">Ethernet12</id>
+  </port>
+  <vlan label="VLAN" vlan-id="100" pvid="yes">provisioning</vlan>
+  <vlan label="VLAN" vlan-id="300">backup</vlan>
+ </interface>
+ <interface label="Interface" name="eno2">
+  <chassis label="Chassis">
+   <id label="ChassisID" type="mac">00:1c:73:aa:bb:cc</id>
+   <name label="SysName">leaf-01</name>
+  </chassis>
+  <port label="Port">
+   <id label="PortID" type="ifname">Ethernet11</id>
+  </port>
+  <vlan label="VLAN" vlan-id="100" pvid="yes">provisioning</vlan>
+  <vlan label="VLAN" vlan-id="200">storage</vlan>
+ </interface>
+ <interface label="Interface" name="ens3">
+  <chassis label="Chassis">
+   <id label="ChassisID" type="mac">00:1c:73:dd:ee:ff</id>
+   <name label="SysName">leaf-02</name>
+  </chassis>
+  <port label="Port">
+   <id label="PortID" type="ifname">Ethernet1</id>
+  </port>
+  <vlan label="VLAN" vlan-id="50" pvid="yes">management</vlan>
+ </interface>
+ <interface label="Interface" name="enp9s0">
+  <chassis label="Chassis">
+   <id label="ChassisID" type="mac">00:1c:73:dd:ee:ff</id>
+   <name label="SysName">leaf-02</name>
+  </chassis>
+  <port label="Port">
+   <id label="PortID" type="ifname">Ethernet2</id>
+  </port>
+ </interface>
+</lldp>
+`
+
+func (s *topologySuite) getController(c *gc.C) Controller {
+	server, controller := createTestServerController(c, s)
+	server.AddGetResponse("/api/2.0/machines/", http.StatusOK, "["+topologyMachineResponse(c)+"]")
+	body := machineDetailsBSON(c, topologyLSHWDetails, topologyLLDPDetails)
+	server.AddGetResponse("/MAAS/api/2.0/machines/4y3ha3/?op=details", http.StatusOK, string(body))
+	return controller
+}
+
+func (s *topologySuite) TestMapTopologySwitches(c *gc.C) {
+	controller := s.getController(c)
+	topology, err := MapTopology(controller, MachinesArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(topology.Switches, gc.HasLen, 2)
+	leaf01 := topology.Switches[0]
+	c.Check(leaf01.ChassisID, gc.Equals, "00:1c:73:aa:bb:cc")
+	c.Check(leaf01.ChassisIDType, gc.Equals, "mac")
+	c.Check(leaf01.Name, gc.Equals, "leaf-01")
+	c.Assert(leaf01.Ports, gc.HasLen, 2)
+	c.Check(leaf01.Ports[0].ID, gc.Equals, "Ethernet11")
+	c.Assert(leaf01.Ports[0].Interfaces, gc.HasLen, 1)
+	c.Check(leaf01.Ports[0].Interfaces[0].Machine.SystemID(), gc.Equals, "4y3ha3")
+	c.Check(leaf01.Ports[0].Interfaces[0].Interface.Name(), gc.Equals, "eno2")
+	c.Check(leaf01.Ports[1].ID, gc.Equals, "Ethernet12")
+	c.Check(leaf01.Ports[1].VLANs, gc.HasLen, 2)
+	c.Assert(leaf01.Ports[1].Interfaces, gc.HasLen, 1)
+	c.Check(leaf01.Ports[1].Interfaces[0].Interface.Name(), gc.Equals, "eno1")
+
+	leaf02 := topology.Switches[1]
+	c.Check(leaf02.Name, gc.Equals, "leaf-02")
+	// The port of the unknown interface only has the interfaces that
+	// MAAS knows about.
+	c.Assert(leaf02.Ports, gc.HasLen, 1)
+	c.Check(leaf02.Ports[0].ID, gc.Equals, "Ethernet1")
+	c.Assert(leaf02.Ports[0].Interfaces, gc.HasLen, 1)
+	c.Check(leaf02.Ports[0].Interfaces[0].Interface.Name(), gc.Equals, "eno3")
+}
+
+func (s *topologySuite) TestMapTopologyFindings(c *gc.C) {
+	controller := s.getController(c)
+	topology, err := MapTopology(controller, MachinesArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	type finding struct {
+		findingType TopologyFindingType
+		iface       string
+		chassisID   string
+		portID      string
+	}
+	var findings []finding
+	for _, f := range topology.Findings {
+		c.Check(f.SystemID, gc.Equals, "4y3ha3")
+		c.Check(f.Hostname, gc.Equals, "untasted-markita")
+		c.Check(f.Message, gc.Not(gc.Equals), "")
+		findings = append(findings, finding{f.Type, f.Interface, f.ChassisID, f.PortID})
+	}
+	c.Check(findings, jc.DeepEquals, []finding{
+		{FindingUnknownInterface, "enp9s0", "00:1c:73:dd:ee:ff", "Ethernet2"},
+		{FindingBondSingleSwitch, "bond0", "00:1c:73:aa:bb:cc", ""},
+		{FindingVLANNotTrunked, "bond0.200", "00:1c:73:aa:bb:cc", "Ethernet12"},
+		{FindingUntaggedVLANMismatch, "eno3", "00:1c:73:dd:ee:ff", "Ethernet1"},
+		{FindingNoNeighbor, "eno4", "", ""},
+	})
+	c.Check(topology.Findings[2].Message, gc.Equals,
+		"VLAN 200 of interface bond0.200 isn't trunked on port Ethernet12 of switch 00:1c:73:aa:bb:cc")
+}
+
+func (s *topologySuite) TestMapTopologyDetailsError(c *gc.C) {
+	server, controller := createTestServerController(c, s)
+	server.AddGetResponse("/api/2.0/machines/", http.StatusOK, "["+topologyMachineResponse(c)+"]")
+	_, err := MapTopology(controller, MachinesArgs{})
+	c.Assert(err, gc.ErrorMatches, "(?s)machine 4y3ha3: .*")
+	c.Assert(errors.Cause(err), jc.Satisfies, IsNoMatchError)
+}
@@ -0,0 +1,141 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type user struct {
+	resourceURI string
+
+	username    string
+	email       string
+	isSuperuser bool
+	isLocal     bool
+}
+
+// Username implements User.
+func (u *user) Username() string {
+	return u.username
+}
+
+// Email implements User.
+func (u *user) Email() string {
+	return u.email
+}
+
+// IsSuperuser implements User.
+func (u *user) IsSuperuser() bool {
+	return u.isSuperuser
+}
+
+// IsLocal implements User.
+func (u *user) IsLocal() bool {
+	return u.isLocal
+}
+
+func readUser(controllerVersion version.Number, source interface{}) (*user, error) {
+	readFunc, err := getUserDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "user base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readUsers(controllerVersion version.Number, source interface{}) ([]*user, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "user base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getUserDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readUserList(valid, readFunc)
+}
+
+func getUserDeserializationFunc(controllerVersion version.Number) (userDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range userDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no user read func for version %s", controllerVersion)
+	}
+	return userDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readUserList expects the values of the sourceList to be string maps.
+func readUserList(sourceList []interface{}, readFunc userDeserializationFunc) ([]*user, error) {
+	result := make([]*user, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for user %d, %T", i, value)
+		}
+		user, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "user %d", i)
+		}
+		result = append(result, user)
+	}
+	return result, nil
+}
+
+type userDeserializationFunc func(map[string]interface{}) (*user, error)
+
+var userDeserializationFuncs = map[version.Number]userDeserializationFunc{
+	twoDotOh: user_2_0,
+}
+
+func user_2_0(source map[string]interface{}) (*user, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"username":     schema.String(),
+		"email":        schema.OneOf(schema.Nil(""), schema.String()),
+		"is_superuser": schema.Bool(),
+		"is_local":     schema.Bool(),
+	}
+	defaults := schema.Defaults{
+		"resource_uri": "",
+		"email":        "",
+		// Only MAAS 2.5 and later support external users.
+		"is_local": true,
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "user 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	email, _ := valid["email"].(string)
+	result := &user{
+		resourceURI: valid["resource_uri"].(string),
+
+		username:    valid["username"].(string),
+		email:       email,
+		isSuperuser: valid["is_superuser"].(bool),
+		isLocal:     valid["is_local"].(bool),
+	}
+	return result, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type userSuite struct{}
+
+var _ = gc.Suite(&userSuite{})
+
+func (*userSuite) TestReadUsersBadSchema(c *gc.C) {
+	_, err := readUsers(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `user base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*userSuite) TestReadUsers(c *gc.C) {
+	users, err := readUsers(twoDotOh, parseJSON(c, usersResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, gc.HasLen, 2)
+
+	user := users[0]
+	c.Check(user.Username(), gc.Equals, "admin")
+	c.Check(user.Email(), gc.Equals, "admin@example.com")
+	c.Check(user.IsSuperuser(), jc.IsTrue)
+	c.Check(user.IsLocal(), jc.IsTrue)
+
+	user = users[1]
+	c.Check(user.Username(), gc.Equals, "jane")
+	c.Check(user.Email(), gc.Equals, "")
+	c.Check(user.IsSuperuser(), jc.IsFalse)
+	c.Check(user.IsLocal(), jc.IsFalse)
+}
+
+func (*userSuite) TestReadUserWithoutIsLocal(c *gc.C) {
+	user, err := readUser(twoDotOh, parseJSON(c, `{
+        "username": "admin",
+        "email": "admin@example.com",
+        "is_superuser": true,
+        "resource_uri": "/MAAS/api/2.0/users/admin/"
+    }`))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(user.IsLocal(), jc.IsTrue)
+}
+
+func (*userSuite) TestLowVersion(c *gc.C) {
+	_, err := readUsers(version.MustParse("1.9.0"), parseJSON(c, usersResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*userSuite) TestHighVersion(c *gc.C) {
+	users, err := readUsers(version.MustParse("2.1.9"), parseJSON(c, usersResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(users, gc.HasLen, 2)
+}
+
+const (
+	whoAmIResponse = `
+{
+    "is_superuser": true,
+    "username": "admin",
+    "email": "admin@example.com",
+    "is_local": true,
+    "resource_uri": "/MAAS/api/2.0/users/admin/"
+}
+`
+	usersResponse = `
+[` + whoAmIResponse + `,
+    {
+        "is_superuser": false,
+        "username": "jane",
+        "email": null,
+        "is_local": false,
+        "resource_uri": "/MAAS/api/2.0/users/jane/"
+    }
+]
+`
+)
@@ -4,14 +4,16 @@
 package gomaasapi
 
 import (
+	"fmt"
+	"net/url"
+
 	"github.com/juju/errors"
 	"github.com/juju/schema"
 	"github.com/juju/version"
 )
 
 type vlan struct {
-	// Add the controller in when we need to do things with the vlan.
-	// controller Controller
+	controller *controller
 
 	resourceURI string
 
@@ -25,6 +27,21 @@ type vlan struct {
 
 	primaryRack   string
 	secondaryRack string
+
+	space string
+}
+
+func (v *vlan) updateFrom(other *vlan) {
+	v.resourceURI = other.resourceURI
+	v.id = other.id
+	v.name = other.name
+	v.fabric = other.fabric
+	v.vid = other.vid
+	v.mtu = other.mtu
+	v.dhcp = other.dhcp
+	v.primaryRack = other.primaryRack
+	v.secondaryRack = other.secondaryRack
+	v.space = other.space
 }
 
 // ID implements VLAN.
@@ -67,6 +84,143 @@ func (v *vlan) SecondaryRack() string {
 	return v.secondaryRack
 }
 
+// Space implements VLAN.
+func (v *vlan) Space() string {
+	return v.space
+}
+
+// UpdateVLANArgs is an argument struct for passing parameters to the
+// VLAN.Update method. Only the values that are specified are changed.
+type UpdateVLANArgs struct {
+	Name        string
+	Description string
+	MTU         int
+
+	// DHCPOn turns the MAAS DHCP server for the VLAN on or off. MAAS
+	// requires a primary rack controller for DHCP to be enabled.
+	DHCPOn        *bool
+	PrimaryRack   string
+	SecondaryRack string
+	// ClearPrimaryRack and ClearSecondaryRack remove the rack controllers
+	// from the VLAN.
+	ClearPrimaryRack   bool
+	ClearSecondaryRack bool
+
+	// RelayVLAN is the VLAN to relay DHCP requests to, for when the DHCP
+	// server for this VLAN is provided by a different VLAN.
+	RelayVLAN VLAN
+	// ClearRelayVLAN stops relaying DHCP requests.
+	ClearRelayVLAN bool
+	// Space is the name of the space the VLAN belongs to.
+	Space string
+}
+
+// Validate ensures that the MTU isn't negative, that the primary and
+// secondary racks are not the same rack controller, and that values aren't
+// both set and cleared.
+func (a *UpdateVLANArgs) Validate() error {
+	if a.MTU < 0 {
+		return errors.NotValidf("negative MTU")
+	}
+	if a.PrimaryRack != "" && a.ClearPrimaryRack {
+		return errors.NotValidf("both PrimaryRack and ClearPrimaryRack")
+	}
+	if a.SecondaryRack != "" && a.ClearSecondaryRack {
+		return errors.NotValidf("both SecondaryRack and ClearSecondaryRack")
+	}
+	if a.RelayVLAN != nil && a.ClearRelayVLAN {
+		return errors.NotValidf("both RelayVLAN and ClearRelayVLAN")
+	}
+	if a.SecondaryRack != "" && a.SecondaryRack == a.PrimaryRack {
+		return errors.NotValidf("SecondaryRack same as PrimaryRack")
+	}
+	return nil
+}
+
+// Update implements VLAN.
+func (v *vlan) Update(args UpdateVLANArgs) error {
+	if err := args.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	params.MaybeAdd("description", args.Description)
+	params.MaybeAddInt("mtu", args.MTU)
+	if args.DHCPOn != nil {
+		params.Values.Add("dhcp_on", fmt.Sprint(*args.DHCPOn))
+	}
+	params.MaybeAdd("primary_rack", args.PrimaryRack)
+	params.MaybeAdd("secondary_rack", args.SecondaryRack)
+	if args.RelayVLAN != nil {
+		params.Values.Add("relay_vlan", fmt.Sprint(args.RelayVLAN.ID()))
+	}
+	// Empty values remove the racks and the relay VLAN.
+	if args.ClearPrimaryRack {
+		params.Values.Add("primary_rack", "")
+	}
+	if args.ClearSecondaryRack {
+		params.Values.Add("secondary_rack", "")
+	}
+	if args.ClearRelayVLAN {
+		params.Values.Add("relay_vlan", "")
+	}
+	params.MaybeAdd("space", args.Space)
+	if len(params.Values) == 0 {
+		return nil
+	}
+	return v.update(params.Values)
+}
+
+// SetSpace implements VLAN.
+func (v *vlan) SetSpace(space Space) error {
+	params := NewURLParams()
+	if space == nil {
+		// An empty space removes the VLAN from its space.
+		params.Values.Add("space", "")
+	} else {
+		params.Values.Add("space", fmt.Sprint(space.ID()))
+	}
+	return v.update(params.Values)
+}
+
+func (v *vlan) update(params url.Values) error {
+	source, err := v.controller.put(v.resourceURI, params)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readVLAN(v.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	v.updateFrom(response)
+	return nil
+}
+
+// Delete implements VLAN.
+func (v *vlan) Delete() error {
+	err := v.controller.delete(v.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+func readVLAN(controllerVersion version.Number, source interface{}) (*vlan, error) {
+	readFunc, err := getVLANDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "vlan base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readVLANs(controllerVersion version.Number, source interface{}) ([]*vlan, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -75,6 +229,14 @@ func readVLANs(controllerVersion version.Number, source interface{}) ([]*vlan, e
 	}
 	valid := coerced.([]interface{})
 
+	readFunc, err := getVLANDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readVLANList(valid, readFunc)
+}
+
+func getVLANDeserializationFunc(controllerVersion version.Number) (vlanDeserializationFunc, error) {
 	var deserialisationVersion version.Number
 	for v := range vlanDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
@@ -84,8 +246,7 @@ func readVLANs(controllerVersion version.Number, source interface{}) ([]*vlan, e
 	if deserialisationVersion == version.Zero {
 		return nil, errors.Errorf("no vlan read func for version %s", controllerVersion)
 	}
-	readFunc := vlanDeserializationFuncs[deserialisationVersion]
-	return readVLANList(valid, readFunc)
+	return vlanDeserializationFuncs[deserialisationVersion], nil
 }
 
 func readVLANList(sourceList []interface{}, readFunc vlanDeserializationFunc) ([]*vlan, error) {
@@ -122,8 +283,12 @@ func vlan_2_0(source map[string]interface{}) (*vlan, error) {
 		// racks are not always set.
 		"primary_rack":   schema.OneOf(schema.Nil(""), schema.String()),
 		"secondary_rack": schema.OneOf(schema.Nil(""), schema.String()),
+		"space":          schema.OneOf(schema.Nil(""), schema.String()),
+	}
+	defaults := schema.Defaults{
+		"space": "",
 	}
-	checker := schema.FieldMap(fields, nil)
+	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(source, nil)
 	if err != nil {
 		return nil, errors.Annotatef(err, "vlan 2.0 schema check failed")
@@ -138,6 +303,7 @@ func vlan_2_0(source map[string]interface{}) (*vlan, error) {
 	primary_rack, _ := valid["primary_rack"].(string)
 	secondary_rack, _ := valid["secondary_rack"].(string)
 	name, _ := valid["name"].(string)
+	space, _ := valid["space"].(string)
 
 	result := &vlan{
 		resourceURI:   valid["resource_uri"].(string),
@@ -149,6 +315,7 @@ func vlan_2_0(source map[string]interface{}) (*vlan, error) {
 		dhcp:          valid["dhcp_on"].(bool),
 		primaryRack:   primary_rack,
 		secondaryRack: secondary_rack,
+		space:         space,
 	}
 	return result, nil
 }
@@ -4,12 +4,19 @@
 package gomaasapi
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 )
 
-type vlanSuite struct{}
+type vlanSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&vlanSuite{})
 
@@ -74,6 +81,223 @@ func (*vlanSuite) TestHighVersion(c *gc.C) {
 	c.Assert(vlans, gc.HasLen, 1)
 }
 
+func (s *vlanSuite) TestReadVLANSpace(c *gc.C) {
+	vlan, err := readVLAN(twoDotOh, parseJSON(c, vlanItemResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(vlan.Space(), gc.Equals, "")
+
+	vlan, err = readVLAN(twoDotOh, parseJSON(c, updateJSONMap(c, vlanItemResponse, map[string]interface{}{
+		"space": "storage",
+	})))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(vlan.Space(), gc.Equals, "storage")
+}
+
+func (s *vlanSuite) getServerAndVLAN(c *gc.C) (*SimpleTestServer, *vlan) {
+	server, ctrl := createTestServerController(c, s)
+	vlan, err := readVLAN(twoDotOh, parseJSON(c, vlanItemResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	vlan.controller = ctrl.(*controller)
+	return server, vlan
+}
+
+func (s *vlanSuite) TestUpdateArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    UpdateVLANArgs
+		errText string
+	}{{
+		args: UpdateVLANArgs{},
+	}, {
+		args: UpdateVLANArgs{PrimaryRack: "4y3h7n", SecondaryRack: "xyz123"},
+	}, {
+		args:    UpdateVLANArgs{MTU: -1},
+		errText: "negative MTU not valid",
+	}, {
+		args:    UpdateVLANArgs{PrimaryRack: "4y3h7n", SecondaryRack: "4y3h7n"},
+		errText: "SecondaryRack same as PrimaryRack not valid",
+	}, {
+		args: UpdateVLANArgs{ClearPrimaryRack: true, ClearSecondaryRack: true, ClearRelayVLAN: true},
+	}, {
+		args:    UpdateVLANArgs{PrimaryRack: "4y3h7n", ClearPrimaryRack: true},
+		errText: "both PrimaryRack and ClearPrimaryRack not valid",
+	}, {
+		args:    UpdateVLANArgs{SecondaryRack: "xyz123", ClearSecondaryRack: true},
+		errText: "both SecondaryRack and ClearSecondaryRack not valid",
+	}, {
+		args:    UpdateVLANArgs{RelayVLAN: &vlan{}, ClearRelayVLAN: true},
+		errText: "both RelayVLAN and ClearRelayVLAN not valid",
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *vlanSuite) TestUpdate(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	relay, err := readVLAN(twoDotOh, parseJSON(c, updateJSONMap(c, vlanItemResponse, map[string]interface{}{
+		"id": 5002,
+	})))
+	c.Assert(err, jc.ErrorIsNil)
+	response := updateJSONMap(c, vlanItemResponse, map[string]interface{}{
+		"mtu":            9000,
+		"dhcp_on":        true,
+		"primary_rack":   "4y3h7n",
+		"secondary_rack": "xyz123",
+		"space":          "storage",
+	})
+	server.AddPutResponse(vlan.resourceURI, http.StatusOK, response)
+	dhcpOn := true
+	err = vlan.Update(UpdateVLANArgs{
+		MTU:           9000,
+		DHCPOn:        &dhcpOn,
+		PrimaryRack:   "4y3h7n",
+		SecondaryRack: "xyz123",
+		RelayVLAN:     relay,
+		Space:         "storage",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(vlan.MTU(), gc.Equals, 9000)
+	c.Check(vlan.DHCP(), jc.IsTrue)
+	c.Check(vlan.PrimaryRack(), gc.Equals, "4y3h7n")
+	c.Check(vlan.SecondaryRack(), gc.Equals, "xyz123")
+	c.Check(vlan.Space(), gc.Equals, "storage")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 6)
+	c.Check(form.Get("mtu"), gc.Equals, "9000")
+	c.Check(form.Get("dhcp_on"), gc.Equals, "true")
+	c.Check(form.Get("primary_rack"), gc.Equals, "4y3h7n")
+	c.Check(form.Get("secondary_rack"), gc.Equals, "xyz123")
+	c.Check(form.Get("relay_vlan"), gc.Equals, "5002")
+	c.Check(form.Get("space"), gc.Equals, "storage")
+}
+
+func (s *vlanSuite) TestUpdateDHCPOff(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	server.AddPutResponse(vlan.resourceURI, http.StatusOK, vlanItemResponse)
+	dhcpOn := false
+	err := vlan.Update(UpdateVLANArgs{DHCPOn: &dhcpOn})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(vlan.DHCP(), jc.IsFalse)
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 1)
+	c.Check(form.Get("dhcp_on"), gc.Equals, "false")
+}
+
+func (s *vlanSuite) TestUpdateNothing(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	count := server.RequestCount()
+	err := vlan.Update(UpdateVLANArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *vlanSuite) TestUpdateBadRequest(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	server.AddPutResponse(vlan.resourceURI, http.StatusBadRequest, "dhcp needs a primary rack")
+	dhcpOn := true
+	err := vlan.Update(UpdateVLANArgs{DHCPOn: &dhcpOn})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "dhcp needs a primary rack")
+}
+
+func (s *vlanSuite) TestSetSpace(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	space, err := readSpace(twoDotOh, parseJSON(c, spaceResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	response := updateJSONMap(c, vlanItemResponse, map[string]interface{}{
+		"space": "storage",
+	})
+	server.AddPutResponse(vlan.resourceURI, http.StatusOK, response)
+	err = vlan.SetSpace(space)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(vlan.Space(), gc.Equals, "storage")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 1)
+	c.Check(form.Get("space"), gc.Equals, "2")
+}
+
+func (s *vlanSuite) TestSetSpaceNil(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	vlan.space = "storage"
+	server.AddPutResponse(vlan.resourceURI, http.StatusOK, vlanItemResponse)
+	err := vlan.SetSpace(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(vlan.Space(), gc.Equals, "")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 1)
+	c.Check(form["space"], jc.DeepEquals, []string{""})
+}
+
+func (s *vlanSuite) TestSetSpaceMissing(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	server.AddPutResponse(vlan.resourceURI, http.StatusNotFound, "no such space")
+	space, err := readSpace(twoDotOh, parseJSON(c, spaceResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	err = vlan.SetSpace(space)
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+	c.Check(vlan.Space(), gc.Equals, "")
+}
+
+func (s *vlanSuite) TestUpdateClear(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	vlan.primaryRack = "4y3h7n"
+	vlan.secondaryRack = "xyz123"
+	server.AddPutResponse(vlan.resourceURI, http.StatusOK, vlanItemResponse)
+	err := vlan.Update(UpdateVLANArgs{
+		ClearPrimaryRack:   true,
+		ClearSecondaryRack: true,
+		ClearRelayVLAN:     true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(vlan.PrimaryRack(), gc.Equals, "")
+	c.Check(vlan.SecondaryRack(), gc.Equals, "")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{
+		"primary_rack":   {""},
+		"secondary_rack": {""},
+		"relay_vlan":     {""},
+	})
+}
+
+func (s *vlanSuite) TestDelete(c *gc.C) {
+	server, vlan := s.getServerAndVLAN(c)
+	server.AddDeleteResponse(vlan.resourceURI, http.StatusNoContent, "")
+	err := vlan.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *vlanSuite) TestDeleteMissing(c *gc.C) {
+	_, vlan := s.getServerAndVLAN(c)
+	err := vlan.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+var vlanItemResponse = `
+{
+    "name": "untagged",
+    "vid": 0,
+    "primary_rack": null,
+    "resource_uri": "/MAAS/api/2.0/vlans/5001/",
+    "id": 5001,
+    "secondary_rack": null,
+    "fabric": "fabric-1",
+    "mtu": 1500,
+    "dhcp_on": false,
+    "space": null
+}
+`
+
 const (
 	vlanResponseWithName = `
 [
@@ -0,0 +1,476 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"fmt"
+
+	"github.com/juju/collections/set"
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+// VMHostResources are the amounts of the resources of a VM host that are
+// in total, used, or available for composing machines.
+type VMHostResources struct {
+	Cores int
+	// Memory is in MB.
+	Memory int
+	// LocalStorage is in bytes.
+	LocalStorage uint64
+}
+
+// VMHostStoragePool is a storage pool of a VM host that the disks of
+// composed machines can be created in. The sizes are in bytes.
+type VMHostStoragePool struct {
+	ID        string
+	Name      string
+	Type      string
+	Path      string
+	Default   bool
+	Total     uint64
+	Used      uint64
+	Available uint64
+}
+
+type vmHost struct {
+	controller *controller
+
+	resourceURI string
+
+	id                    int
+	name                  string
+	hostType              string
+	version               string
+	architectures         []string
+	capabilities          []string
+	tags                  []string
+	cpuOverCommitRatio    float64
+	memoryOverCommitRatio float64
+
+	total        VMHostResources
+	used         VMHostResources
+	available    VMHostResources
+	storagePools []VMHostStoragePool
+
+	zone *zone
+	pool *pool
+}
+
+func (h *vmHost) updateFrom(other *vmHost) {
+	h.resourceURI = other.resourceURI
+	h.id = other.id
+	h.name = other.name
+	h.hostType = other.hostType
+	h.version = other.version
+	h.architectures = other.architectures
+	h.capabilities = other.capabilities
+	h.tags = other.tags
+	h.cpuOverCommitRatio = other.cpuOverCommitRatio
+	h.memoryOverCommitRatio = other.memoryOverCommitRatio
+	h.total = other.total
+	h.used = other.used
+	h.available = other.available
+	h.storagePools = other.storagePools
+	h.zone = other.zone
+	h.pool = other.pool
+}
+
+// ID implements VMHost.
+func (h *vmHost) ID() int {
+	return h.id
+}
+
+// Name implements VMHost.
+func (h *vmHost) Name() string {
+	return h.name
+}
+
+// Type implements VMHost.
+func (h *vmHost) Type() string {
+	return h.hostType
+}
+
+// Version implements VMHost.
+func (h *vmHost) Version() string {
+	return h.version
+}
+
+// Architectures implements VMHost.
+func (h *vmHost) Architectures() []string {
+	return h.architectures
+}
+
+// Capabilities implements VMHost.
+func (h *vmHost) Capabilities() set.Strings {
+	return set.NewStrings(h.capabilities...)
+}
+
+// Tags implements VMHost.
+func (h *vmHost) Tags() []string {
+	return h.tags
+}
+
+// CPUOverCommitRatio implements VMHost.
+func (h *vmHost) CPUOverCommitRatio() float64 {
+	return h.cpuOverCommitRatio
+}
+
+// MemoryOverCommitRatio implements VMHost.
+func (h *vmHost) MemoryOverCommitRatio() float64 {
+	return h.memoryOverCommitRatio
+}
+
+// Total implements VMHost.
+func (h *vmHost) Total() VMHostResources {
+	return h.total
+}
+
+// Used implements VMHost.
+func (h *vmHost) Used() VMHostResources {
+	return h.used
+}
+
+// Available implements VMHost.
+func (h *vmHost) Available() VMHostResources {
+	return h.available
+}
+
+// StoragePools implements VMHost.
+func (h *vmHost) StoragePools() []VMHostStoragePool {
+	return h.storagePools
+}
+
+// Zone implements VMHost.
+func (h *vmHost) Zone() Zone {
+	if h.zone == nil {
+		return nil
+	}
+	h.zone.controller = h.controller
+	return h.zone
+}
+
+// Pool implements VMHost.
+func (h *vmHost) Pool() Pool {
+	if h.pool == nil {
+		return nil
+	}
+	h.pool.controller = h.controller
+	return h.pool
+}
+
+// Refresh implements VMHost.
+func (h *vmHost) Refresh() error {
+	source, err := h.controller.post(h.resourceURI, "refresh", nil)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readVMHost(h.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	h.updateFrom(response)
+	return nil
+}
+
+// Delete implements VMHost.
+func (h *vmHost) Delete() error {
+	err := h.controller.delete(h.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// ComposeArgs is an argument struct for passing parameters to the
+// VMHost.Compose method. MAAS uses its defaults for the values that aren't
+// specified.
+type ComposeArgs struct {
+	Hostname     string
+	Architecture string
+	Cores        int
+	// Memory is in MB.
+	Memory int
+	// CPUSpeed is in MHz.
+	CPUSpeed int
+
+	// Storage are the disks of the machine, and the first one is the root
+	// disk. The tags of a StorageSpec select the storage pool of the
+	// disk.
+	Storage []StorageSpec
+	// Interfaces are the interfaces of the machine, and the spaces that
+	// they are connected to.
+	Interfaces []InterfaceSpec
+
+	Domain Domain
+	Zone   Zone
+	Pool   Pool
+}
+
+// Validate ensures the arguments are acceptable.
+func (a *ComposeArgs) Validate() error {
+	if a.Cores < 0 {
+		return errors.NotValidf("negative Cores")
+	}
+	if a.Memory < 0 {
+		return errors.NotValidf("negative Memory")
+	}
+	if a.CPUSpeed < 0 {
+		return errors.NotValidf("negative CPUSpeed")
+	}
+	if err := validateStorageSpecs(a.Storage); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(validateInterfaceSpecs(a.Interfaces))
+}
+
+// Compose implements VMHost.
+func (h *vmHost) Compose(args ComposeArgs) (Machine, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.MaybeAdd("hostname", args.Hostname)
+	params.MaybeAdd("architecture", args.Architecture)
+	params.MaybeAddInt("cores", args.Cores)
+	params.MaybeAddInt("memory", args.Memory)
+	params.MaybeAddInt("cpu_speed", args.CPUSpeed)
+	params.MaybeAdd("storage", storageSpecsString(args.Storage))
+	params.MaybeAdd("interfaces", interfaceSpecsString(args.Interfaces))
+	if args.Domain != nil {
+		params.Values.Add("domain", fmt.Sprint(args.Domain.ID()))
+	}
+	if args.Zone != nil {
+		params.Values.Add("zone", fmt.Sprint(args.Zone.ID()))
+	}
+	if args.Pool != nil {
+		params.Values.Add("pool", fmt.Sprint(args.Pool.ID()))
+	}
+	source, err := h.controller.post(h.resourceURI, "compose", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	// The response only identifies the machine, which is then fetched.
+	checker := schema.FieldMap(schema.Fields{
+		"resource_uri": schema.String(),
+	}, nil)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "composed machine schema check failed")
+	}
+	resourceURI := coerced.(map[string]interface{})["resource_uri"].(string)
+	source, err = h.controller.get(resourceURI)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+	machine, err := readMachine(h.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine.controller = h.controller
+	return machine, nil
+}
+
+func readVMHost(controllerVersion version.Number, source interface{}) (*vmHost, error) {
+	readFunc, err := getVMHostDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "vm host base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readVMHosts(controllerVersion version.Number, source interface{}) ([]*vmHost, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "vm host base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getVMHostDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readVMHostList(valid, readFunc)
+}
+
+func getVMHostDeserializationFunc(controllerVersion version.Number) (vmHostDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range vmHostDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no vm host read func for version %s", controllerVersion)
+	}
+	return vmHostDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readVMHostList expects the values of the sourceList to be string maps.
+func readVMHostList(sourceList []interface{}, readFunc vmHostDeserializationFunc) ([]*vmHost, error) {
+	result := make([]*vmHost, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for vm host %d, %T", i, value)
+		}
+		host, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "vm host %d", i)
+		}
+		result = append(result, host)
+	}
+	return result, nil
+}
+
+type vmHostDeserializationFunc func(map[string]interface{}) (*vmHost, error)
+
+var vmHostDeserializationFuncs = map[version.Number]vmHostDeserializationFunc{
+	twoDotOh: vmHost_2_0,
+}
+
+func vmHost_2_0(source map[string]interface{}) (*vmHost, error) {
+	resources := schema.FieldMap(schema.Fields{
+		"cores":         schema.ForceInt(),
+		"memory":        schema.ForceInt(),
+		"local_storage": schema.ForceUint(),
+	}, schema.Defaults{
+		"local_storage": uint64(0),
+	})
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":                       schema.ForceInt(),
+		"name":                     schema.String(),
+		"type":                     schema.String(),
+		"version":                  schema.OneOf(schema.Nil(""), schema.String()),
+		"architectures":            schema.List(schema.String()),
+		"capabilities":             schema.List(schema.String()),
+		"tags":                     schema.List(schema.String()),
+		"cpu_over_commit_ratio":    schema.Float(),
+		"memory_over_commit_ratio": schema.Float(),
+
+		"total":         resources,
+		"used":          resources,
+		"available":     resources,
+		"storage_pools": schema.List(schema.StringMap(schema.Any())),
+
+		"zone": schema.StringMap(schema.Any()),
+		"pool": schema.OneOf(schema.Nil(""), schema.Any()),
+	}
+	defaults := schema.Defaults{
+		// Only LXD VM hosts report their version.
+		"version":                  "",
+		"architectures":            []interface{}{},
+		"capabilities":             []interface{}{},
+		"tags":                     []interface{}{},
+		"cpu_over_commit_ratio":    float64(1),
+		"memory_over_commit_ratio": float64(1),
+		"storage_pools":            []interface{}{},
+		"pool":                     nil,
+	}
+	checker := schema.FieldMap(fields, defaults)
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "vm host 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	storagePools, err := readVMHostStoragePools(valid["storage_pools"].([]interface{}))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	zone, err := zone_2_0(valid["zone"].(map[string]interface{}))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var pool *pool
+	if valid["pool"] != nil {
+		if pool, err = pool_2_0(valid["pool"].(map[string]interface{})); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	hostVersion, _ := valid["version"].(string)
+	result := &vmHost{
+		resourceURI: valid["resource_uri"].(string),
+
+		id:                    valid["id"].(int),
+		name:                  valid["name"].(string),
+		hostType:              valid["type"].(string),
+		version:               hostVersion,
+		architectures:         convertToStringSlice(valid["architectures"]),
+		capabilities:          convertToStringSlice(valid["capabilities"]),
+		tags:                  convertToStringSlice(valid["tags"]),
+		cpuOverCommitRatio:    valid["cpu_over_commit_ratio"].(float64),
+		memoryOverCommitRatio: valid["memory_over_commit_ratio"].(float64),
+
+		total:        convertToVMHostResources(valid["total"]),
+		used:         convertToVMHostResources(valid["used"]),
+		available:    convertToVMHostResources(valid["available"]),
+		storagePools: storagePools,
+
+		zone: zone,
+		pool: pool,
+	}
+	return result, nil
+}
+
+func convertToVMHostResources(field interface{}) VMHostResources {
+	valid := field.(map[string]interface{})
+	return VMHostResources{
+		Cores:        valid["cores"].(int),
+		Memory:       valid["memory"].(int),
+		LocalStorage: valid["local_storage"].(uint64),
+	}
+}
+
+func readVMHostStoragePools(sourceList []interface{}) ([]VMHostStoragePool, error) {
+	checker := schema.FieldMap(schema.Fields{
+		"id":        schema.String(),
+		"name":      schema.String(),
+		"type":      schema.String(),
+		"path":      schema.String(),
+		"default":   schema.Bool(),
+		"total":     schema.ForceUint(),
+		"used":      schema.ForceUint(),
+		"available": schema.ForceUint(),
+	}, schema.Defaults{
+		"path":    "",
+		"default": false,
+	})
+	result := make([]VMHostStoragePool, 0, len(sourceList))
+	for i, value := range sourceList {
+		coerced, err := checker.Coerce(value, nil)
+		if err != nil {
+			return nil, WrapWithDeserializationError(err, "storage pool %d schema check failed", i)
+		}
+		valid := coerced.(map[string]interface{})
+		result = append(result, VMHostStoragePool{
+			ID:        valid["id"].(string),
+			Name:      valid["name"].(string),
+			Type:      valid["type"].(string),
+			Path:      valid["path"].(string),
+			Default:   valid["default"].(bool),
+			Total:     valid["total"].(uint64),
+			Used:      valid["used"].(uint64),
+			Available: valid["available"].(uint64),
+		})
+	}
+	return result, nil
+}
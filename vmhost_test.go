@@ -0,0 +1,274 @@
+// Copyright 2022 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type vmHostSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&vmHostSuite{})
+
+func (*vmHostSuite) TestReadVMHostsBadSchema(c *gc.C) {
+	_, err := readVMHosts(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `vm host base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*vmHostSuite) TestReadVMHosts(c *gc.C) {
+	hosts, err := readVMHosts(twoDotOh, parseJSON(c, vmHostsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hosts, gc.HasLen, 2)
+
+	host := hosts[0]
+	c.Check(host.ID(), gc.Equals, 1)
+	c.Check(host.Name(), gc.Equals, "lxd-1")
+	c.Check(host.Type(), gc.Equals, "lxd")
+	c.Check(host.Version(), gc.Equals, "5.0.2")
+	c.Check(host.Architectures(), jc.DeepEquals, []string{"amd64/generic"})
+	c.Check(host.Capabilities().Contains("composable"), jc.IsTrue)
+	c.Check(host.Tags(), jc.DeepEquals, []string{"pod-console-logging"})
+	c.Check(host.CPUOverCommitRatio(), gc.Equals, 2.0)
+	c.Check(host.MemoryOverCommitRatio(), gc.Equals, 1.0)
+	c.Check(host.Total(), jc.DeepEquals, VMHostResources{Cores: 8, Memory: 16384, LocalStorage: 250000000000})
+	c.Check(host.Used(), jc.DeepEquals, VMHostResources{Cores: 2, Memory: 4096, LocalStorage: 8000000000})
+	c.Check(host.Available(), jc.DeepEquals, VMHostResources{Cores: 14, Memory: 12288, LocalStorage: 242000000000})
+	c.Check(host.StoragePools(), jc.DeepEquals, []VMHostStoragePool{{
+		ID:        "default",
+		Name:      "default",
+		Type:      "dir",
+		Path:      "/var/snap/lxd/common/lxd/storage-pools/default",
+		Default:   true,
+		Total:     250000000000,
+		Used:      8000000000,
+		Available: 242000000000,
+	}})
+	c.Check(host.Zone().Name(), gc.Equals, "default")
+	c.Check(host.Pool().Name(), gc.Equals, "default")
+
+	host = hosts[1]
+	c.Check(host.Type(), gc.Equals, "virsh")
+	c.Check(host.Version(), gc.Equals, "")
+	c.Check(host.StoragePools(), gc.HasLen, 0)
+	c.Check(host.Pool(), gc.IsNil)
+}
+
+func (*vmHostSuite) TestReadVMHostBadStoragePool(c *gc.C) {
+	_, err := readVMHost(twoDotOh, parseJSON(c, updateJSONMap(c, vmHostResponse, map[string]interface{}{
+		"storage_pools": []interface{}{map[string]interface{}{"id": "default"}},
+	})))
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Check(err, gc.ErrorMatches, `storage pool 0 schema check failed: .*`)
+}
+
+func (*vmHostSuite) TestLowVersion(c *gc.C) {
+	_, err := readVMHosts(version.MustParse("1.9.0"), parseJSON(c, vmHostsResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*vmHostSuite) TestHighVersion(c *gc.C) {
+	hosts, err := readVMHosts(version.MustParse("2.1.9"), parseJSON(c, vmHostsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hosts, gc.HasLen, 2)
+}
+
+func (s *vmHostSuite) getServerAndVMHost(c *gc.C) (*SimpleTestServer, *vmHost) {
+	server, ctrl := createTestServerController(c, s)
+	host, err := readVMHost(twoDotOh, parseJSON(c, vmHostResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	host.controller = ctrl.(*controller)
+	return server, host
+}
+
+func (s *vmHostSuite) TestRefresh(c *gc.C) {
+	server, host := s.getServerAndVMHost(c)
+	response := updateJSONMap(c, vmHostResponse, map[string]interface{}{
+		"used": map[string]interface{}{"cores": 4, "memory": 8192, "local_storage": 16000000000},
+	})
+	server.AddPostResponse(host.resourceURI+"?op=refresh", http.StatusOK, response)
+	err := host.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(host.Used(), jc.DeepEquals, VMHostResources{Cores: 4, Memory: 8192, LocalStorage: 16000000000})
+}
+
+func (s *vmHostSuite) TestRefreshNotFound(c *gc.C) {
+	_, host := s.getServerAndVMHost(c)
+	err := host.Refresh()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *vmHostSuite) TestDelete(c *gc.C) {
+	server, host := s.getServerAndVMHost(c)
+	server.AddDeleteResponse(host.resourceURI, http.StatusNoContent, "")
+	err := host.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *vmHostSuite) TestDeleteNotFound(c *gc.C) {
+	_, host := s.getServerAndVMHost(c)
+	err := host.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+func (s *vmHostSuite) TestCompose(c *gc.C) {
+	server, host := s.getServerAndVMHost(c)
+	server.AddPostResponse(host.resourceURI+"?op=compose", http.StatusOK, `{
+        "system_id": "4y3ha3",
+        "resource_uri": "/MAAS/api/2.0/machines/4y3ha3/"
+    }`)
+	server.AddGetResponse("/MAAS/api/2.0/machines/4y3ha3/", http.StatusOK, machineResponse)
+	composed, err := host.Compose(ComposeArgs{
+		Hostname:     "composed",
+		Architecture: "amd64/generic",
+		Cores:        2,
+		Memory:       4096,
+		Storage: []StorageSpec{
+			{Label: "root", Size: 20, Tags: []string{"default"}},
+			{Label: "data", Size: 100},
+		},
+		Interfaces: []InterfaceSpec{{Label: "eth0", Space: "dmz"}},
+		Zone:       host.Zone(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(composed.SystemID(), gc.Equals, "4y3ha3")
+	c.Check(composed.(*machine).controller, gc.NotNil)
+
+	request := server.LastNRequests(2)[0]
+	form := request.PostForm
+	c.Check(form.Get("hostname"), gc.Equals, "composed")
+	c.Check(form.Get("architecture"), gc.Equals, "amd64/generic")
+	c.Check(form.Get("cores"), gc.Equals, "2")
+	c.Check(form.Get("memory"), gc.Equals, "4096")
+	c.Check(form.Get("storage"), gc.Equals, "root:20(default),data:100")
+	c.Check(form.Get("interfaces"), gc.Equals, "eth0:space=dmz")
+	c.Check(form.Get("zone"), gc.Equals, "1")
+	_, found := form["pool"]
+	c.Check(found, jc.IsFalse)
+}
+
+func (s *vmHostSuite) TestComposeValidates(c *gc.C) {
+	_, host := s.getServerAndVMHost(c)
+	_, err := host.Compose(ComposeArgs{
+		Storage: []StorageSpec{{Label: "root", Size: 20}, {Label: "root", Size: 10}},
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(err.Error(), gc.Equals, `reusing storage label "root" not valid`)
+}
+
+func (s *vmHostSuite) TestComposeFails(c *gc.C) {
+	server, host := s.getServerAndVMHost(c)
+	server.AddPostResponse(host.resourceURI+"?op=compose", http.StatusConflict, "Unable to compose KVM instance")
+	_, err := host.Compose(ComposeArgs{Cores: 64})
+	c.Assert(err, jc.Satisfies, IsCannotCompleteError)
+}
+
+func (*vmHostSuite) TestComposeArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    ComposeArgs
+		errText string
+	}{{
+		args:    ComposeArgs{Cores: -1},
+		errText: "negative Cores not valid",
+	}, {
+		args:    ComposeArgs{Memory: -1},
+		errText: "negative Memory not valid",
+	}, {
+		args:    ComposeArgs{CPUSpeed: -1},
+		errText: "negative CPUSpeed not valid",
+	}, {
+		args:    ComposeArgs{Interfaces: []InterfaceSpec{{Label: "eth0"}}},
+		errText: "Interfaces: empty Space constraint not valid",
+	}, {
+		args: ComposeArgs{Cores: 2, Memory: 2048},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+const (
+	vmHostResponse = `
+{
+    "id": 1,
+    "name": "lxd-1",
+    "type": "lxd",
+    "version": "5.0.2",
+    "resource_uri": "/MAAS/api/2.0/pods/1/",
+    "architectures": ["amd64/generic"],
+    "capabilities": ["composable", "dynamic_local_storage", "over_commit", "storage_pools"],
+    "tags": ["pod-console-logging"],
+    "cpu_over_commit_ratio": 2.0,
+    "memory_over_commit_ratio": 1.0,
+    "default_macvlan_mode": null,
+    "host": {"system_id": "4y3h7n", "__incomplete__": true},
+    "total": {"cores": 8, "memory": 16384, "local_storage": 250000000000},
+    "used": {"cores": 2, "memory": 4096, "local_storage": 8000000000},
+    "available": {"cores": 14, "memory": 12288, "local_storage": 242000000000},
+    "storage_pools": [
+        {
+            "id": "default",
+            "name": "default",
+            "type": "dir",
+            "path": "/var/snap/lxd/common/lxd/storage-pools/default",
+            "total": 250000000000,
+            "used": 8000000000,
+            "available": 242000000000,
+            "default": true
+        }
+    ],
+    "zone": {
+        "description": "",
+        "resource_uri": "/MAAS/api/2.0/zones/default/",
+        "name": "default",
+        "id": 1
+    },
+    "pool": {
+        "description": "Default pool",
+        "resource_uri": "/MAAS/api/2.0/resourcepool/0/",
+        "name": "default",
+        "id": 0
+    }
+}
+`
+	vmHostsResponse = `
+[` + vmHostResponse + `,
+    {
+        "id": 2,
+        "name": "virsh-1",
+        "type": "virsh",
+        "resource_uri": "/MAAS/api/2.0/pods/2/",
+        "architectures": ["amd64/generic"],
+        "capabilities": ["composable", "dynamic_local_storage", "over_commit"],
+        "tags": [],
+        "cpu_over_commit_ratio": 1.0,
+        "memory_over_commit_ratio": 1.0,
+        "total": {"cores": 4, "memory": 8192, "local_storage": 0},
+        "used": {"cores": 0, "memory": 0, "local_storage": 0},
+        "available": {"cores": 4, "memory": 8192, "local_storage": 0},
+        "zone": {
+            "description": "",
+            "resource_uri": "/MAAS/api/2.0/zones/default/",
+            "name": "default",
+            "id": 1
+        },
+        "pool": null
+    }
+]
+`
+)
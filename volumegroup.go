@@ -0,0 +1,271 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/schema"
+	"github.com/juju/version"
+)
+
+type volumeGroup struct {
+	controller *controller
+
+	resourceURI string
+
+	id            int
+	name          string
+	uuid          string
+	size          uint64
+	usedSize      uint64
+	availableSize uint64
+
+	blockDevices   []*blockdevice
+	partitions     []*partition
+	logicalVolumes []*logicalVolume
+}
+
+// ID implements VolumeGroup.
+func (v *volumeGroup) ID() int {
+	return v.id
+}
+
+// Name implements VolumeGroup.
+func (v *volumeGroup) Name() string {
+	return v.name
+}
+
+// UUID implements VolumeGroup.
+func (v *volumeGroup) UUID() string {
+	return v.uuid
+}
+
+// Size implements VolumeGroup.
+func (v *volumeGroup) Size() uint64 {
+	return v.size
+}
+
+// UsedSize implements VolumeGroup.
+func (v *volumeGroup) UsedSize() uint64 {
+	return v.usedSize
+}
+
+// AvailableSize implements VolumeGroup.
+func (v *volumeGroup) AvailableSize() uint64 {
+	return v.availableSize
+}
+
+// Devices implements VolumeGroup.
+func (v *volumeGroup) Devices() []StorageDevice {
+	return storageDevices(v.controller, v.blockDevices, v.partitions)
+}
+
+// LogicalVolumes implements VolumeGroup.
+func (v *volumeGroup) LogicalVolumes() []LogicalVolume {
+	result := make([]LogicalVolume, len(v.logicalVolumes))
+	for i, lv := range v.logicalVolumes {
+		lv.controller = v.controller
+		result[i] = lv
+	}
+	return result
+}
+
+// CreateLogicalVolumeArgs is an argument struct for passing parameters to
+// the VolumeGroup.CreateLogicalVolume method.
+type CreateLogicalVolumeArgs struct {
+	// Name of the logical volume (required).
+	Name string
+	// Size of the logical volume in bytes. If not specified, the logical
+	// volume uses all the available space in the volume group.
+	Size uint64
+	// UUID is optional, and MAAS generates one if it isn't specified.
+	UUID string
+}
+
+// Validate ensures that the Name is specified.
+func (a *CreateLogicalVolumeArgs) Validate() error {
+	if a.Name == "" {
+		return errors.NotValidf("missing Name")
+	}
+	return nil
+}
+
+// CreateLogicalVolume implements VolumeGroup.
+func (v *volumeGroup) CreateLogicalVolume(args CreateLogicalVolumeArgs) (LogicalVolume, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	params := NewURLParams()
+	params.Values.Add("name", args.Name)
+	maybeAddSize(params, "size", args.Size)
+	params.MaybeAdd("uuid", args.UUID)
+	source, err := v.controller.post(v.resourceURI, "create_logical_volume", params.Values)
+	if err != nil {
+		return nil, translateServerError(err)
+	}
+
+	lv, err := readLogicalVolume(v.controller.apiVersion, source)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	lv.controller = v.controller
+	lv.volumeGroupURI = v.resourceURI
+	v.logicalVolumes = append(v.logicalVolumes, lv)
+	return lv, nil
+}
+
+// Delete implements VolumeGroup.
+func (v *volumeGroup) Delete() error {
+	err := v.controller.delete(v.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+// CreateVolumeGroupArgs is an argument struct for passing parameters to the
+// Machine.CreateVolumeGroup method.
+type CreateVolumeGroupArgs struct {
+	// Name of the volume group (required).
+	Name string
+	// UUID is optional, and MAAS generates one if it isn't specified.
+	UUID string
+	// BlockDevices and Partitions are the physical volumes for the volume
+	// group. At least one block device or partition is required.
+	BlockDevices []BlockDevice
+	Partitions   []Partition
+}
+
+// Validate ensures that the Name and at least one physical volume are
+// specified.
+func (a *CreateVolumeGroupArgs) Validate() error {
+	if a.Name == "" {
+		return errors.NotValidf("missing Name")
+	}
+	if len(a.BlockDevices) == 0 && len(a.Partitions) == 0 {
+		return errors.NotValidf("missing BlockDevices or Partitions")
+	}
+	return nil
+}
+
+func readVolumeGroup(controllerVersion version.Number, source interface{}) (*volumeGroup, error) {
+	readFunc, err := getVolumeGroupDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "volume group base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
+func readVolumeGroups(controllerVersion version.Number, source interface{}) ([]*volumeGroup, error) {
+	checker := schema.List(schema.StringMap(schema.Any()))
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "volume group base schema check failed")
+	}
+	valid := coerced.([]interface{})
+
+	readFunc, err := getVolumeGroupDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readVolumeGroupList(valid, readFunc)
+}
+
+func getVolumeGroupDeserializationFunc(controllerVersion version.Number) (volumeGroupDeserializationFunc, error) {
+	var deserialisationVersion version.Number
+	for v := range volumeGroupDeserializationFuncs {
+		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
+			deserialisationVersion = v
+		}
+	}
+	if deserialisationVersion == version.Zero {
+		return nil, NewUnsupportedVersionError("no volume group read func for version %s", controllerVersion)
+	}
+	return volumeGroupDeserializationFuncs[deserialisationVersion], nil
+}
+
+// readVolumeGroupList expects the values of the sourceList to be string maps.
+func readVolumeGroupList(sourceList []interface{}, readFunc volumeGroupDeserializationFunc) ([]*volumeGroup, error) {
+	result := make([]*volumeGroup, 0, len(sourceList))
+	for i, value := range sourceList {
+		source, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, NewDeserializationError("unexpected value for volume group %d, %T", i, value)
+		}
+		volumeGroup, err := readFunc(source)
+		if err != nil {
+			return nil, errors.Annotatef(err, "volume group %d", i)
+		}
+		result = append(result, volumeGroup)
+	}
+	return result, nil
+}
+
+type volumeGroupDeserializationFunc func(map[string]interface{}) (*volumeGroup, error)
+
+var volumeGroupDeserializationFuncs = map[version.Number]volumeGroupDeserializationFunc{
+	twoDotOh: volumeGroup_2_0,
+}
+
+func volumeGroup_2_0(source map[string]interface{}) (*volumeGroup, error) {
+	fields := schema.Fields{
+		"resource_uri": schema.String(),
+
+		"id":             schema.ForceInt(),
+		"name":           schema.String(),
+		"uuid":           schema.OneOf(schema.Nil(""), schema.String()),
+		"size":           schema.ForceUint(),
+		"used_size":      schema.ForceUint(),
+		"available_size": schema.ForceUint(),
+
+		"devices":         schema.List(schema.StringMap(schema.Any())),
+		"logical_volumes": schema.List(schema.StringMap(schema.Any())),
+	}
+	checker := schema.FieldMap(fields, nil) // no defaults
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "volume group 2.0 schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	// From here we know that the map returned from the schema coercion
+	// contains fields of the right type.
+
+	blockDevices, partitions, err := storageDeviceList_2_0(valid["devices"].([]interface{}))
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	resourceURI := valid["resource_uri"].(string)
+	logicalVolumes, err := readLogicalVolumeList(valid["logical_volumes"].([]interface{}), logicalVolume_2_0)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for _, lv := range logicalVolumes {
+		lv.volumeGroupURI = resourceURI
+	}
+
+	uuid, _ := valid["uuid"].(string)
+	result := &volumeGroup{
+		resourceURI: resourceURI,
+
+		id:            valid["id"].(int),
+		name:          valid["name"].(string),
+		uuid:          uuid,
+		size:          valid["size"].(uint64),
+		usedSize:      valid["used_size"].(uint64),
+		availableSize: valid["available_size"].(uint64),
+
+		blockDevices:   blockDevices,
+		partitions:     partitions,
+		logicalVolumes: logicalVolumes,
+	}
+	return result, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2016 Canonical Ltd.
+// Licensed under the LGPLv3, see LICENCE file for details.
+
+package gomaasapi
+
+import (
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+)
+
+type volumeGroupSuite struct {
+	testing.CleanupSuite
+}
+
+var _ = gc.Suite(&volumeGroupSuite{})
+
+func (*volumeGroupSuite) TestReadVolumeGroupsBadSchema(c *gc.C) {
+	_, err := readVolumeGroups(twoDotOh, "wat?")
+	c.Check(err, jc.Satisfies, IsDeserializationError)
+	c.Assert(err.Error(), gc.Equals, `volume group base schema check failed: expected list, got string("wat?")`)
+}
+
+func (*volumeGroupSuite) TestReadVolumeGroups(c *gc.C) {
+	volumeGroups, err := readVolumeGroups(twoDotOh, parseJSON(c, volumeGroupsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeGroups, gc.HasLen, 1)
+	vg := volumeGroups[0]
+
+	c.Check(vg.ID(), gc.Equals, 5)
+	c.Check(vg.Name(), gc.Equals, "vg0")
+	c.Check(vg.UUID(), gc.Equals, "a3c3d7f8-8a4a-4fe0-9c1c-3c3bc7d6b0d1")
+	c.Check(vg.Size(), gc.Equals, uint64(16101933056))
+	c.Check(vg.UsedSize(), gc.Equals, uint64(4294967296))
+	c.Check(vg.AvailableSize(), gc.Equals, uint64(11806965760))
+
+	devices := vg.Devices()
+	c.Assert(devices, gc.HasLen, 2)
+	c.Check(devices[0].Type(), gc.Equals, "blockdevice")
+	c.Check(devices[0].ID(), gc.Equals, 98)
+	c.Check(devices[1].Type(), gc.Equals, "partition")
+	c.Check(devices[1].ID(), gc.Equals, 102)
+
+	lvs := vg.LogicalVolumes()
+	c.Assert(lvs, gc.HasLen, 1)
+	c.Check(lvs[0].Name(), gc.Equals, "vg0-lv0")
+	c.Check(lvs[0].(*logicalVolume).volumeGroupURI, gc.Equals, vg.resourceURI)
+}
+
+func (*volumeGroupSuite) TestLowVersion(c *gc.C) {
+	_, err := readVolumeGroups(version.MustParse("1.9.0"), parseJSON(c, volumeGroupsResponse))
+	c.Assert(err, jc.Satisfies, IsUnsupportedVersionError)
+}
+
+func (*volumeGroupSuite) TestHighVersion(c *gc.C) {
+	volumeGroups, err := readVolumeGroups(version.MustParse("2.1.9"), parseJSON(c, volumeGroupsResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumeGroups, gc.HasLen, 1)
+}
+
+func (s *volumeGroupSuite) getServerAndVolumeGroup(c *gc.C) (*SimpleTestServer, *volumeGroup) {
+	server, ctrl := createTestServerController(c, s)
+	vg, err := readVolumeGroup(twoDotOh, parseJSON(c, volumeGroupResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	vg.controller = ctrl.(*controller)
+	return server, vg
+}
+
+func (s *volumeGroupSuite) TestCreateLogicalVolumeArgsValidate(c *gc.C) {
+	for i, test := range []struct {
+		args    CreateLogicalVolumeArgs
+		errText string
+	}{{
+		errText: "missing Name not valid",
+	}, {
+		args: CreateLogicalVolumeArgs{Name: "lv1"},
+	}} {
+		c.Logf("test %d", i)
+		err := test.args.Validate()
+		if test.errText == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, jc.Satisfies, errors.IsNotValid)
+			c.Check(err.Error(), gc.Equals, test.errText)
+		}
+	}
+}
+
+func (s *volumeGroupSuite) TestCreateLogicalVolume(c *gc.C) {
+	server, vg := s.getServerAndVolumeGroup(c)
+	response := updateJSONMap(c, logicalVolumeResponse, map[string]interface{}{
+		"id":   48,
+		"name": "vg0-lv1",
+	})
+	server.AddPostResponse(vg.resourceURI+"?op=create_logical_volume", http.StatusOK, response)
+
+	lv, err := vg.CreateLogicalVolume(CreateLogicalVolumeArgs{
+		Name: "lv1",
+		Size: 2147483648,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(lv.ID(), gc.Equals, 48)
+	c.Check(lv.Name(), gc.Equals, "vg0-lv1")
+	c.Check(vg.LogicalVolumes(), gc.HasLen, 2)
+
+	form := server.LastRequest().PostForm
+	c.Assert(form, gc.HasLen, 2)
+	c.Check(form.Get("name"), gc.Equals, "lv1")
+	c.Check(form.Get("size"), gc.Equals, "2147483648")
+}
+
+func (s *volumeGroupSuite) TestCreateLogicalVolumeValidates(c *gc.C) {
+	_, vg := s.getServerAndVolumeGroup(c)
+	_, err := vg.CreateLogicalVolume(CreateLogicalVolumeArgs{})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *volumeGroupSuite) TestCreateLogicalVolumeBadRequest(c *gc.C) {
+	server, vg := s.getServerAndVolumeGroup(c)
+	server.AddPostResponse(vg.resourceURI+"?op=create_logical_volume", http.StatusBadRequest, "not enough space")
+	_, err := vg.CreateLogicalVolume(CreateLogicalVolumeArgs{Name: "lv1"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "not enough space")
+}
+
+func (s *volumeGroupSuite) TestDelete(c *gc.C) {
+	server, vg := s.getServerAndVolumeGroup(c)
+	server.AddDeleteResponse(vg.resourceURI, http.StatusNoContent, "")
+	err := vg.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *volumeGroupSuite) TestDeleteNotFound(c *gc.C) {
+	server, vg := s.getServerAndVolumeGroup(c)
+	server.AddDeleteResponse(vg.resourceURI, http.StatusNotFound, "")
+	err := vg.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
+var (
+	volumeGroupsResponse = "[" + volumeGroupResponse + "]"
+	volumeGroupResponse  = `
+    {
+        "id": 5,
+        "name": "vg0",
+        "uuid": "a3c3d7f8-8a4a-4fe0-9c1c-3c3bc7d6b0d1",
+        "system_id": "4y3ha3",
+        "size": 16101933056,
+        "used_size": 4294967296,
+        "available_size": 11806965760,
+        "human_size": "16.1 GB",
+        "devices": [
+            {
+                "path": "/dev/disk/by-dname/sdb",
+                "name": "sdb",
+                "used_for": "lvm-pv(vg0)",
+                "partitions": [],
+                "filesystem": {
+                    "fstype": "lvm-pv",
+                    "mount_point": null,
+                    "label": null,
+                    "mount_options": null,
+                    "uuid": "b1a5ee8d-8b0f-4a3a-9b5d-9f4b0aa1e2f7"
+                },
+                "id_path": "/dev/disk/by-id/ata-QEMU_HARDDISK_QM00002",
+                "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/98/",
+                "id": 98,
+                "type": "physical",
+                "block_size": 4096,
+                "used_size": 8589934592,
+                "available_size": 0,
+                "uuid": null,
+                "size": 8589934592,
+                "model": "QEMU HARDDISK",
+                "tags": []
+            },
+            {
+                "bootable": false,
+                "id": 102,
+                "path": "/dev/disk/by-dname/sdc-part1",
+                "filesystem": {
+                    "fstype": "lvm-pv",
+                    "mount_point": null,
+                    "label": null,
+                    "mount_options": null,
+                    "uuid": "2b5a0f3c-e1e8-4b8d-8a38-0c3cf2c7d8b4"
+                },
+                "type": "partition",
+                "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/blockdevices/99/partition/102",
+                "uuid": "7a0b8c35-2cd1-4a4c-86a2-6db0f1b2c7a1",
+                "used_for": "lvm-pv(vg0)",
+                "size": 7511998464,
+                "tags": []
+            }
+        ],
+        "logical_volumes": [` + logicalVolumeResponse + `],
+        "resource_uri": "/MAAS/api/2.0/nodes/4y3ha3/volume-group/5/"
+    }
+`
+)
@@ -10,13 +10,31 @@ import (
 )
 
 type zone struct {
-	// Add the controller in when we need to do things with the zone.
-	// controller Controller
+	controller *controller
 
 	resourceURI string
 
+	id          int
 	name        string
 	description string
+
+	// The machine and device counts are only included in the zone
+	// listing by some versions of MAAS, hasCounts records whether they were.
+	hasCounts bool
+	machines  int
+	devices   int
+}
+
+func (z *zone) updateFrom(other *zone) {
+	z.resourceURI = other.resourceURI
+	z.id = other.id
+	z.name = other.name
+	z.description = other.description
+}
+
+// ID implements Zone.
+func (z *zone) ID() int {
+	return z.id
 }
 
 // Name implements Zone.
@@ -29,6 +47,62 @@ func (z *zone) Description() string {
 	return z.description
 }
 
+// UpdateZoneArgs is an argument struct for passing parameters to the
+// Zone.Update method. Only the values that are specified are changed.
+type UpdateZoneArgs struct {
+	Name string
+	// Description replaces the existing description when not nil. An empty
+	// string removes the description.
+	Description *string
+}
+
+// Update implements Zone.
+func (z *zone) Update(args UpdateZoneArgs) error {
+	params := NewURLParams()
+	params.MaybeAdd("name", args.Name)
+	if args.Description != nil {
+		params.Values.Add("description", *args.Description)
+	}
+	if len(params.Values) == 0 {
+		return nil
+	}
+	source, err := z.controller.put(z.resourceURI, params.Values)
+	if err != nil {
+		return translateServerError(err)
+	}
+
+	response, err := readZone(z.controller.apiVersion, source)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	z.updateFrom(response)
+	return nil
+}
+
+// Delete implements Zone.
+func (z *zone) Delete() error {
+	err := z.controller.delete(z.resourceURI)
+	if err != nil {
+		return translateServerError(err)
+	}
+	return nil
+}
+
+func readZone(controllerVersion version.Number, source interface{}) (*zone, error) {
+	readFunc, err := getZoneDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	checker := schema.StringMap(schema.Any())
+	coerced, err := checker.Coerce(source, nil)
+	if err != nil {
+		return nil, WrapWithDeserializationError(err, "zone base schema check failed")
+	}
+	valid := coerced.(map[string]interface{})
+	return readFunc(valid)
+}
+
 func readZones(controllerVersion version.Number, source interface{}) ([]*zone, error) {
 	checker := schema.List(schema.StringMap(schema.Any()))
 	coerced, err := checker.Coerce(source, nil)
@@ -37,6 +111,14 @@ func readZones(controllerVersion version.Number, source interface{}) ([]*zone, e
 	}
 	valid := coerced.([]interface{})
 
+	readFunc, err := getZoneDeserializationFunc(controllerVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return readZoneList(valid, readFunc)
+}
+
+func getZoneDeserializationFunc(controllerVersion version.Number) (zoneDeserializationFunc, error) {
 	var deserialisationVersion version.Number
 	for v := range zoneDeserializationFuncs {
 		if v.Compare(deserialisationVersion) > 0 && v.Compare(controllerVersion) <= 0 {
@@ -46,8 +128,7 @@ func readZones(controllerVersion version.Number, source interface{}) ([]*zone, e
 	if deserialisationVersion == version.Zero {
 		return nil, errors.Errorf("no zone read func for version %s", controllerVersion)
 	}
-	readFunc := zoneDeserializationFuncs[deserialisationVersion]
-	return readZoneList(valid, readFunc)
+	return zoneDeserializationFuncs[deserialisationVersion], nil
 }
 
 // readZoneList expects the values of the sourceList to be string maps.
@@ -75,11 +156,19 @@ var zoneDeserializationFuncs = map[version.Number]zoneDeserializationFunc{
 
 func zone_2_0(source map[string]interface{}) (*zone, error) {
 	fields := schema.Fields{
+		"id":           schema.ForceInt(),
 		"name":         schema.String(),
 		"description":  schema.String(),
 		"resource_uri": schema.String(),
+		"machines":     schema.OneOf(schema.Nil(""), schema.ForceInt()),
+		"devices":      schema.OneOf(schema.Nil(""), schema.ForceInt()),
+	}
+	defaults := schema.Defaults{
+		"id":       0,
+		"machines": nil,
+		"devices":  nil,
 	}
-	checker := schema.FieldMap(fields, nil) // no defaults
+	checker := schema.FieldMap(fields, defaults)
 	coerced, err := checker.Coerce(source, nil)
 	if err != nil {
 		return nil, errors.Annotatef(err, "zone 2.0 schema check failed")
@@ -88,10 +177,16 @@ func zone_2_0(source map[string]interface{}) (*zone, error) {
 	// From here we know that the map returned from the schema coercion
 	// contains fields of the right type.
 
+	machines, hasMachines := valid["machines"].(int)
+	devices, hasDevices := valid["devices"].(int)
 	result := &zone{
+		id:          valid["id"].(int),
 		name:        valid["name"].(string),
 		description: valid["description"].(string),
 		resourceURI: valid["resource_uri"].(string),
+		hasCounts:   hasMachines && hasDevices,
+		machines:    machines,
+		devices:     devices,
 	}
 	return result, nil
 }
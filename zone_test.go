@@ -4,12 +4,18 @@
 package gomaasapi
 
 import (
+	"net/http"
+	"net/url"
+
+	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
 )
 
-type zoneSuite struct{}
+type zoneSuite struct {
+	testing.CleanupSuite
+}
 
 var _ = gc.Suite(&zoneSuite{})
 
@@ -39,6 +45,81 @@ func (*zoneSuite) TestHighVersion(c *gc.C) {
 	c.Assert(zones, gc.HasLen, 2)
 }
 
+func (s *zoneSuite) getServerAndZone(c *gc.C) (*SimpleTestServer, *zone) {
+	server, ctrl := createTestServerController(c, s)
+	zones, err := readZones(twoDotOh, parseJSON(c, zoneResponse))
+	c.Assert(err, jc.ErrorIsNil)
+	zones[1].controller = ctrl.(*controller)
+	return server, zones[1]
+}
+
+func (s *zoneSuite) TestUpdate(c *gc.C) {
+	server, zone := s.getServerAndZone(c)
+	server.AddPutResponse(zone.resourceURI, http.StatusOK, `{
+        "id": 2,
+        "name": "renamed",
+        "description": "new description",
+        "resource_uri": "/MAAS/api/2.0/zones/renamed/"
+    }`)
+	description := "new description"
+	err := zone.Update(UpdateZoneArgs{Name: "renamed", Description: &description})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(zone.ID(), gc.Equals, 2)
+	c.Check(zone.Name(), gc.Equals, "renamed")
+	c.Check(zone.Description(), gc.Equals, "new description")
+	c.Check(zone.resourceURI, gc.Equals, "/MAAS/api/2.0/zones/renamed/")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, gc.HasLen, 2)
+	c.Check(form.Get("name"), gc.Equals, "renamed")
+	c.Check(form.Get("description"), gc.Equals, "new description")
+}
+
+func (s *zoneSuite) TestUpdateClearDescription(c *gc.C) {
+	server, zone := s.getServerAndZone(c)
+	server.AddPutResponse(zone.resourceURI, http.StatusOK, `{
+        "description": "",
+        "resource_uri": "/MAAS/api/2.0/zones/special/",
+        "name": "special"
+    }`)
+	description := ""
+	err := zone.Update(UpdateZoneArgs{Description: &description})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(zone.Description(), gc.Equals, "")
+
+	form := server.LastRequest().PostForm
+	c.Check(form, jc.DeepEquals, url.Values{"description": {""}})
+}
+
+func (s *zoneSuite) TestUpdateNothing(c *gc.C) {
+	server, zone := s.getServerAndZone(c)
+	count := server.RequestCount()
+	err := zone.Update(UpdateZoneArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(server.RequestCount(), gc.Equals, count)
+}
+
+func (s *zoneSuite) TestUpdateBadRequest(c *gc.C) {
+	server, zone := s.getServerAndZone(c)
+	server.AddPutResponse(zone.resourceURI, http.StatusBadRequest, "name in use")
+	err := zone.Update(UpdateZoneArgs{Name: "default"})
+	c.Assert(err, jc.Satisfies, IsBadRequestError)
+	c.Assert(err.Error(), gc.Equals, "name in use")
+}
+
+func (s *zoneSuite) TestDelete(c *gc.C) {
+	server, zone := s.getServerAndZone(c)
+	server.AddDeleteResponse(zone.resourceURI, http.StatusNoContent, "")
+	err := zone.Delete()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *zoneSuite) TestDeleteMissing(c *gc.C) {
+	_, zone := s.getServerAndZone(c)
+	err := zone.Delete()
+	c.Assert(err, jc.Satisfies, IsNoMatchError)
+}
+
 var zoneResponse = `
 [
     {